@@ -0,0 +1,102 @@
+package flipswitch
+
+import (
+	"context"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// defaultReevaluateTimeout bounds the background re-evaluation started by
+// WithAutoReevaluate for each change event.
+const defaultReevaluateTimeout = 5 * time.Second
+
+// WithAutoReevaluate re-evaluates flags in the background when an SSE event
+// reports that they changed, for the evaluation context passed to Init (or
+// set with SetRefreshContext), so
+// that with WithCache the next read is served locally. Bulk invalidations
+// that name no flags are not re-evaluated. Re-evaluations run on their own
+// goroutine, so a slow server never holds up the processing of later SSE
+// events, and are bounded by WithReevaluateTimeout. Shutdown cancels those
+// still in flight.
+func WithAutoReevaluate(enabled bool) Option {
+	return func(p *FlipswitchProvider) {
+		p.autoReevaluate = enabled
+	}
+}
+
+// WithReevaluateTimeout bounds the re-evaluation started by
+// WithAutoReevaluate for each change event (default 5s).
+func WithReevaluateTimeout(timeout time.Duration) Option {
+	return func(p *FlipswitchProvider) {
+		p.reevaluateTimeout = timeout
+	}
+}
+
+// setInitContext stores the context passed to Init for background
+// evaluations, unless SetRefreshContext replaced it.
+func (p *FlipswitchProvider) setInitContext(evalCtx openfeature.FlattenedContext) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.refreshContextSet {
+		p.refreshContext = evalCtx
+	}
+}
+
+// startBackgroundEvaluation allows background evaluations to start.
+// Background evaluations are made by WithAutoReevaluate, WithAutoRefresh and
+// polling.
+func (p *FlipswitchProvider) startBackgroundEvaluation() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cancelBackground == nil {
+		p.backgroundCtx, p.cancelBackground = context.WithCancel(context.Background())
+	}
+}
+
+// stopBackgroundEvaluation cancels background evaluations in flight and
+// stops new ones.
+func (p *FlipswitchProvider) stopBackgroundEvaluation() {
+	p.mu.Lock()
+	cancel := p.cancelBackground
+	p.backgroundCtx, p.cancelBackground = nil, nil
+	if p.refreshTimer != nil {
+		p.refreshTimer.Stop()
+		p.refreshTimer = nil
+	}
+	p.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// reevaluate re-evaluates the flags event reports changed on a new
+// goroutine. Failures are logged and recorded by evaluateFlag.
+func (p *FlipswitchProvider) reevaluate(event FlagChangeEvent) {
+	if !p.autoReevaluate {
+		return
+	}
+	keys := event.AffectedKeys
+	if event.FlagKey != "" {
+		keys = []string{event.FlagKey}
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	p.mu.RLock()
+	parent, evalCtx := p.backgroundCtx, p.refreshContext
+	p.mu.RUnlock()
+	if parent == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(parent, p.reevaluateTimeout)
+		defer cancel()
+		for _, key := range keys {
+			p.evaluateFlag(ctx, key, evalCtx)
+		}
+	}()
+}
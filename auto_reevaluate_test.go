@@ -0,0 +1,181 @@
+package flipswitch
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// sseEventServer serves the dispatcher, and an SSE stream that sends each
+// frame received on frames.
+func sseEventServer(dispatcher *TestDispatcher, frames <-chan string) *httptest.Server {
+	dispatcher.SetSseHandler(sseFramesHandler(frames))
+	return httptest.NewServer(dispatcher)
+}
+
+// sseFramesHandler serves an SSE stream that sends each frame received on
+// frames.
+func sseFramesHandler(frames <-chan string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		flusher := w.(http.Flusher)
+		flusher.Flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case frame := <-frames:
+				fmt.Fprint(w, frame)
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// flagUpdatedFrame is the SSE frame reporting that flagKey changed.
+func flagUpdatedFrame(flagKey string) string {
+	return sseFrame("flag-updated", `{"flagKey":"`+flagKey+`"}`)
+}
+
+func createReevaluatingProvider(t *testing.T, server *httptest.Server, opts ...Option) *FlipswitchProvider {
+	t.Helper()
+	provider, err := NewProvider(
+		"test-api-key",
+		append([]Option{
+			WithBaseURL(server.URL),
+			WithAutoReevaluate(true),
+		}, opts...)...,
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	if err := provider.Init(openfeature.NewTargetlessEvaluationContext(nil)); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	return provider
+}
+
+func TestAutoReevaluate_RefreshesCacheOnChange(t *testing.T) {
+	var calls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", countingFlag("my-flag", &calls))
+	changes := make(chan string, 1)
+	server := sseEventServer(dispatcher, changes)
+	defer server.Close()
+
+	provider := createReevaluatingProvider(t, server, WithCache(time.Minute))
+	defer provider.Shutdown()
+
+	changes <- flagUpdatedFrame("my-flag")
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&calls) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("Expected the changed flag to be re-evaluated once, got %d requests", got)
+	}
+
+	// Wait for the result to be cached
+	deadline = time.Now().Add(5 * time.Second)
+	for provider.cache.len() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{}); result == nil {
+		t.Fatal("Expected my-flag to evaluate")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected the read to be served from the cache, got %d requests", got)
+	}
+}
+
+func TestAutoReevaluate_SlowServerDoesNotBlockEvents(t *testing.T) {
+	const timeout = 300 * time.Millisecond
+
+	cancelled := make(chan time.Duration, 1)
+	dispatcher := NewTestDispatcher()
+	changes := make(chan string, 2)
+	sse := sseEventServer(dispatcher, changes)
+	defer sse.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/slow-flag") {
+			dispatcher.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		// The server only notices the client going away once the body is read
+		io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+			cancelled <- time.Since(start)
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	provider := createReevaluatingProvider(t, server, WithReevaluateTimeout(timeout))
+	defer provider.Shutdown()
+
+	received := make(chan time.Time, 1)
+	provider.AddFlagKeyChangeListener("other-flag", func(FlagChangeEvent) {
+		received <- time.Now()
+	})
+
+	changes <- flagUpdatedFrame("slow-flag")
+	time.Sleep(50 * time.Millisecond)
+	sent := time.Now()
+	changes <- flagUpdatedFrame("other-flag")
+
+	select {
+	case at := <-received:
+		if delay := at.Sub(sent); delay > timeout/2 {
+			t.Errorf("Expected the next event promptly, took %v", delay)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the next event")
+	}
+
+	select {
+	case elapsed := <-cancelled:
+		if elapsed > 2*timeout {
+			t.Errorf("Expected the re-evaluation to be cancelled after about %v, took %v", timeout, elapsed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the re-evaluation did not respect its deadline")
+	}
+}
+
+func TestAutoReevaluate_DisabledByDefault(t *testing.T) {
+	var calls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", countingFlag("my-flag", &calls))
+	changes := make(chan string, 1)
+	server := sseEventServer(dispatcher, changes)
+	defer server.Close()
+
+	provider := createReevaluatingProvider(t, server, WithAutoReevaluate(false))
+	defer provider.Shutdown()
+
+	received := make(chan struct{}, 1)
+	provider.AddFlagKeyChangeListener("my-flag", func(FlagChangeEvent) {
+		received <- struct{}{}
+	})
+	changes <- flagUpdatedFrame("my-flag")
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the change event")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Errorf("Expected no re-evaluation, got %d requests", got)
+	}
+}
@@ -0,0 +1,76 @@
+package flipswitch
+
+import (
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// defaultRefreshDebounce is how long WithAutoRefresh waits after a bulk
+// change before refreshing, so that a burst of events causes one request.
+const defaultRefreshDebounce = 500 * time.Millisecond
+
+// WithAutoRefresh re-fetches all flags with a single bulk evaluation when an
+// SSE event reports a bulk change (config-updated, or flag-updated without a
+// flag key), for the evaluation context passed to Init or set with
+// SetRefreshContext. With WithCache the results are cached, so reads
+// following a configuration change are served locally. Events arriving
+// within half a second of each other are coalesced into one refresh.
+// Shutdown cancels a refresh in flight.
+func WithAutoRefresh(enabled bool) Option {
+	return func(p *FlipswitchProvider) {
+		p.autoRefresh = enabled
+	}
+}
+
+// SetRefreshContext sets the evaluation context that WithAutoRefresh,
+// WithAutoReevaluate and polling evaluate flags for, replacing the one
+// passed to Init.
+func (p *FlipswitchProvider) SetRefreshContext(evalCtx openfeature.FlattenedContext) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.refreshContext = evalCtx
+	p.refreshContextSet = true
+}
+
+// isRefreshContext reports whether evalCtx is the context background
+// evaluations are made for. It is false until Init or SetRefreshContext
+// sets one.
+func (p *FlipswitchProvider) isRefreshContext(evalCtx openfeature.FlattenedContext) bool {
+	p.mu.RLock()
+	refresh, ok := p.refreshContext, p.refreshContext != nil || p.refreshContextSet
+	p.mu.RUnlock()
+	return ok && contextHash(evalCtx) == contextHash(refresh)
+}
+
+// scheduleRefresh schedules a refresh after a bulk change event, unless one
+// is already pending.
+func (p *FlipswitchProvider) scheduleRefresh(event FlagChangeEvent) {
+	if !p.autoRefresh || event.FlagKey != "" {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.backgroundCtx == nil || p.refreshTimer != nil {
+		return
+	}
+	p.refreshTimer = p.clock.AfterFunc(p.refreshDebounce, p.refresh)
+}
+
+// refresh performs a scheduled refresh. Events arriving while it runs
+// schedule another one.
+func (p *FlipswitchProvider) refresh() {
+	p.mu.Lock()
+	p.refreshTimer = nil
+	ctx, evalCtx := p.backgroundCtx, p.refreshContext
+	p.mu.Unlock()
+	if ctx == nil {
+		return
+	}
+
+	if err := p.warmContext(ctx, evalCtx); err != nil {
+		p.logger.Warnw("Automatic flag refresh failed", errorFields(err)...)
+		return
+	}
+	p.logger.Debugw("Refreshed all flags after a configuration change")
+}
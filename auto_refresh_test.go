@@ -0,0 +1,109 @@
+package flipswitch
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const configUpdatedFrame = "event: config-updated\ndata: {}\n\n"
+
+func TestAutoRefresh_CoalescesBurstIntoOneBulkRequest(t *testing.T) {
+	var bulkCalls, flagCalls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		atomic.AddInt32(&bulkCalls, 1)
+		return 200, map[string]interface{}{
+			"flags": []interface{}{map[string]interface{}{"key": "my-flag", "value": true}},
+		}
+	})
+	dispatcher.SetFlagResponse("my-flag", countingFlag("my-flag", &flagCalls))
+	frames := make(chan string, 5)
+	server := sseEventServer(dispatcher, frames)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithCache(time.Minute),
+		WithAutoRefresh(true),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+	if err := provider.Init(openfeature.NewTargetlessEvaluationContext(nil)); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	atomic.StoreInt32(&bulkCalls, 0)
+
+	for i := 0; i < 5; i++ {
+		frames <- configUpdatedFrame
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&bulkCalls) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Leave time for a second refresh, which should not happen
+	time.Sleep(2 * defaultRefreshDebounce)
+	if got := atomic.LoadInt32(&bulkCalls); got != 1 {
+		t.Fatalf("Expected the burst to cause 1 bulk request, got %d", got)
+	}
+
+	if result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{}); result == nil || !result.AsBoolean() {
+		t.Fatalf("Expected my-flag to be true, got %+v", result)
+	}
+	if got := atomic.LoadInt32(&flagCalls); got != 0 {
+		t.Errorf("Expected the read to be served from the refreshed cache, got %d requests", got)
+	}
+}
+
+func TestAutoRefresh_UsesRefreshContext(t *testing.T) {
+	refreshed := make(chan map[string]interface{}, 1)
+	dispatcher := NewTestDispatcher()
+	frames := make(chan string, 1)
+	dispatcher.SetSseHandler(sseFramesHandler(frames))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ofrep/v1/evaluate/flags" {
+			body, _ := io.ReadAll(r.Body)
+			var parsed struct {
+				Context map[string]interface{} `json:"context"`
+			}
+			json.Unmarshal(body, &parsed)
+			if parsed.Context["targetingKey"] != "_init_" {
+				refreshed <- parsed.Context
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		dispatcher.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key", WithBaseURL(server.URL), WithAutoRefresh(true))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+	provider.SetRefreshContext(openfeature.FlattenedContext{"targetingKey": "svc", "tenant": "acme"})
+	if err := provider.Init(openfeature.NewEvaluationContext("init-user", nil)); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	frames <- configUpdatedFrame
+
+	select {
+	case sent := <-refreshed:
+		if sent["targetingKey"] != "svc" || sent["tenant"] != "acme" {
+			t.Errorf("Expected the refresh context to be sent, got %v", sent)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the refresh")
+	}
+}
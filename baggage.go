@@ -0,0 +1,72 @@
+package flipswitch
+
+import (
+	"context"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// BaggageReader returns the baggage members carried by ctx, keyed by member
+// name.
+type BaggageReader func(ctx context.Context) map[string]string
+
+// WithBaggageAttributes copies the named baggage members from the
+// context.Context passed to EvaluateFlagCtx and EvaluateAllFlagsCtx into the
+// evaluation context. Attributes set explicitly in the evaluation context
+// take precedence over baggage. Baggage is read from OpenTelemetry baggage,
+// or with the reader given to WithBaggageReader.
+func WithBaggageAttributes(keys ...string) Option {
+	return func(p *FlipswitchProvider) {
+		p.baggageKeys = append(p.baggageKeys, keys...)
+	}
+}
+
+// WithBaggageReader sets how baggage is read from a context.Context for
+// WithBaggageAttributes, in place of OpenTelemetry baggage.
+func WithBaggageReader(reader BaggageReader) Option {
+	return func(p *FlipswitchProvider) {
+		p.baggageReader = reader
+	}
+}
+
+// withBaggage returns evalCtx with the configured baggage members from ctx
+// merged in. evalCtx itself is never modified.
+func (p *FlipswitchProvider) withBaggage(ctx context.Context, evalCtx openfeature.FlattenedContext) openfeature.FlattenedContext {
+	if len(p.baggageKeys) == 0 {
+		return evalCtx
+	}
+	reader := p.baggageReader
+	if reader == nil {
+		reader = otelBaggage
+	}
+	members := reader(ctx)
+	if len(members) == 0 {
+		return evalCtx
+	}
+
+	merged := make(openfeature.FlattenedContext, len(evalCtx)+len(p.baggageKeys))
+	for _, key := range p.baggageKeys {
+		if value, ok := members[key]; ok {
+			merged[key] = value
+		}
+	}
+	for key, value := range evalCtx {
+		merged[key] = value
+	}
+	return merged
+}
+
+// otelBaggage is the default BaggageReader, which reads OpenTelemetry
+// baggage.
+func otelBaggage(ctx context.Context) map[string]string {
+	bag := baggage.FromContext(ctx)
+	if bag.Len() == 0 {
+		return nil
+	}
+	members := make(map[string]string, bag.Len())
+	for _, member := range bag.Members() {
+		members[member.Key()] = member.Value()
+	}
+	return members
+}
@@ -0,0 +1,114 @@
+package flipswitch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+type baggageKey struct{}
+
+// contextBaggage stands in for OpenTelemetry baggage in tests.
+func contextBaggage(ctx context.Context) map[string]string {
+	members, _ := ctx.Value(baggageKey{}).(map[string]string)
+	return members
+}
+
+func createBaggageProvider(t *testing.T, serverURL string) *FlipswitchProvider {
+	t.Helper()
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(serverURL),
+		WithRealtime(false),
+		WithBaggageReader(contextBaggage),
+		WithBaggageAttributes("tenant", "cohort"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	return provider
+}
+
+func TestBaggage_MergedIntoEvaluationContext(t *testing.T) {
+	server, sent := contextRecordingServer()
+	defer server.Close()
+
+	provider := createBaggageProvider(t, server.URL)
+	defer provider.Shutdown()
+
+	ctx := context.WithValue(context.Background(), baggageKey{}, map[string]string{
+		"tenant":  "acme",
+		"cohort":  "beta",
+		"traceId": "not-requested",
+	})
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+
+	provider.EvaluateFlagCtx(ctx, "my-flag", evalCtx)
+	provider.EvaluateAllFlagsCtx(ctx, evalCtx)
+
+	bodies := sent()
+	if len(bodies) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(bodies))
+	}
+	for _, body := range bodies {
+		if body["tenant"] != "acme" || body["cohort"] != "beta" {
+			t.Errorf("Expected baggage attributes in the request, got %v", body)
+		}
+		if _, ok := body["traceId"]; ok {
+			t.Errorf("Expected unlisted baggage members to be left out, got %v", body)
+		}
+		if body["targetingKey"] != "user-1" {
+			t.Errorf("Expected the targeting key to be kept, got %v", body)
+		}
+	}
+	if _, ok := evalCtx["tenant"]; ok {
+		t.Error("Expected the caller's evaluation context not to be modified")
+	}
+}
+
+func TestBaggage_ExplicitAttributesTakePrecedence(t *testing.T) {
+	server, sent := contextRecordingServer()
+	defer server.Close()
+
+	provider := createBaggageProvider(t, server.URL)
+	defer provider.Shutdown()
+
+	ctx := context.WithValue(context.Background(), baggageKey{}, map[string]string{"tenant": "acme"})
+	provider.EvaluateFlagCtx(ctx, "my-flag", openfeature.FlattenedContext{"targetingKey": "user-1", "tenant": "globex"})
+
+	if got := sent()[0]["tenant"]; got != "globex" {
+		t.Errorf("Expected the explicit tenant to win, got %v", got)
+	}
+}
+
+func TestBaggage_ReadsOpenTelemetryBaggageByDefault(t *testing.T) {
+	server, sent := contextRecordingServer()
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithBaggageAttributes("tenant"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	member, err := baggage.NewMember("tenant", "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bag, err := baggage.New(member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := baggage.ContextWithBaggage(context.Background(), bag)
+	provider.EvaluateFlagCtx(ctx, "my-flag", openfeature.FlattenedContext{"targetingKey": "user-1"})
+
+	if got := sent()[0]["tenant"]; got != "acme" {
+		t.Errorf("Expected the tenant from OpenTelemetry baggage, got %v", got)
+	}
+}
@@ -0,0 +1,117 @@
+package flipswitch
+
+import (
+	"sync"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// bootstrapStore holds flag values supplied up front, served when the
+// Flipswitch backend cannot be reached.
+type bootstrapStore struct {
+	flags []FlagEvaluation
+	index map[string]int
+	mu    sync.RWMutex
+}
+
+func newBootstrapStore(flags []FlagEvaluation) *bootstrapStore {
+	s := &bootstrapStore{}
+	s.set(flags)
+	return s
+}
+
+func (s *bootstrapStore) set(flags []FlagEvaluation) {
+	copied := make([]FlagEvaluation, len(flags))
+	copy(copied, flags)
+	index := make(map[string]int, len(copied))
+	for i, flag := range copied {
+		index[flag.Key] = i
+	}
+
+	s.mu.Lock()
+	s.flags = copied
+	s.index = index
+	s.mu.Unlock()
+}
+
+// put adds eval, or replaces the flag with the same key.
+func (s *bootstrapStore) put(eval FlagEvaluation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i, ok := s.index[eval.Key]; ok {
+		s.flags[i] = eval
+		return
+	}
+	s.index[eval.Key] = len(s.flags)
+	s.flags = append(s.flags, eval)
+}
+
+func (s *bootstrapStore) hasFlags() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.flags) > 0
+}
+
+func (s *bootstrapStore) get(flagKey string) (FlagEvaluation, bool) {
+	if s == nil {
+		return FlagEvaluation{}, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i, ok := s.index[flagKey]
+	if !ok {
+		return FlagEvaluation{}, false
+	}
+	return s.flags[i], true
+}
+
+func (s *bootstrapStore) all() []FlagEvaluation {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]FlagEvaluation, len(s.flags))
+	copy(result, s.flags)
+	return result
+}
+
+// WithBootstrap seeds the provider with flag values that are served when the
+// Flipswitch server cannot be reached, including during Init.
+func WithBootstrap(flags []FlagEvaluation) Option {
+	return func(p *FlipswitchProvider) {
+		p.bootstrap = newBootstrapStore(flags)
+	}
+}
+
+// bootstrapFallback returns the bootstrapped evaluation for flag if the live
+// resolution failed with a general (network or server) error.
+func (p *FlipswitchProvider) bootstrapFallback(flag string, detail openfeature.ProviderResolutionDetail) (FlagEvaluation, bool) {
+	if detail.Reason != openfeature.ErrorReason {
+		return FlagEvaluation{}, false
+	}
+	if detail.ResolutionDetail().ErrorCode != openfeature.GeneralCode {
+		return FlagEvaluation{}, false
+	}
+	return p.bootstrap.get(flag)
+}
+
+func cachedResolutionDetail(eval FlagEvaluation) openfeature.ProviderResolutionDetail {
+	return openfeature.ProviderResolutionDetail{
+		Reason:  openfeature.CachedReason,
+		Variant: eval.Variant,
+	}
+}
+
+// bootstrapFlag returns a copy of the bootstrapped evaluation for flagKey, or
+// nil if none was supplied.
+func (p *FlipswitchProvider) bootstrapFlag(flagKey string) *FlagEvaluation {
+	eval, ok := p.bootstrap.get(flagKey)
+	if !ok {
+		return nil
+	}
+	return &eval
+}
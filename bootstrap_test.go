@@ -0,0 +1,260 @@
+package flipswitch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// unreachableURL returns the URL of a server that has already been shut down.
+func unreachableURL() string {
+	server := httptest.NewServer(NewTestDispatcher())
+	url := server.URL
+	server.Close()
+	return url
+}
+
+func TestInit_OfflineWithBootstrap_SucceedsAndServesBootstrappedFlags(t *testing.T) {
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(unreachableURL()),
+		WithRealtime(false),
+		WithBootstrap([]FlagEvaluation{
+			{Key: "dark-mode", Value: true, ValueType: "boolean", Reason: "STATIC", Variant: "on"},
+			{Key: "welcome", Value: "hi", ValueType: "string"},
+			{Key: "max-items", Value: float64(25), ValueType: "number"},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Expected Init to succeed with bootstrap, got: %v", err)
+	}
+
+	if provider.Status() != openfeature.StaleState {
+		t.Errorf("Expected status %q, got %q", openfeature.StaleState, provider.Status())
+	}
+
+	expectEvent(t, provider, openfeature.ProviderStale)
+
+	ctx := context.Background()
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+
+	boolResult := provider.BooleanEvaluation(ctx, "dark-mode", false, evalCtx)
+	if !boolResult.Value {
+		t.Error("Expected bootstrapped value true for dark-mode")
+	}
+	if boolResult.Reason != openfeature.CachedReason {
+		t.Errorf("Expected reason %q, got %q", openfeature.CachedReason, boolResult.Reason)
+	}
+	if boolResult.Variant != "on" {
+		t.Errorf("Expected variant 'on', got '%s'", boolResult.Variant)
+	}
+
+	if got := provider.StringEvaluation(ctx, "welcome", "", evalCtx).Value; got != "hi" {
+		t.Errorf("Expected 'hi', got '%s'", got)
+	}
+	if got := provider.IntEvaluation(ctx, "max-items", 0, evalCtx).Value; got != 25 {
+		t.Errorf("Expected 25, got %d", got)
+	}
+
+	flag := provider.EvaluateFlag("dark-mode", evalCtx)
+	if flag == nil || flag.Value != true {
+		t.Errorf("Expected EvaluateFlag to serve bootstrapped dark-mode, got %+v", flag)
+	}
+
+	if flags := provider.EvaluateAllFlags(evalCtx); len(flags) != 3 {
+		t.Errorf("Expected 3 bootstrapped flags, got %d", len(flags))
+	}
+}
+
+func TestInit_OfflineWithoutBootstrap_Fails(t *testing.T) {
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(unreachableURL()),
+		WithRealtime(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err == nil {
+		t.Fatal("Expected Init to fail without bootstrap")
+	}
+	if provider.Status() != openfeature.ErrorState {
+		t.Errorf("Expected status %q, got %q", openfeature.ErrorState, provider.Status())
+	}
+}
+
+func TestInit_InvalidApiKeyWithBootstrap_StillFails(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetInitFailure(401)
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithBootstrap([]FlagEvaluation{{Key: "dark-mode", Value: true}}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	err = provider.Init(openfeature.EvaluationContext{})
+	if !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey, got: %v", err)
+	}
+}
+
+func TestInit_OnlineWithBootstrap_IsReady(t *testing.T) {
+	server := httptest.NewServer(NewTestDispatcher())
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithBootstrap([]FlagEvaluation{{Key: "dark-mode", Value: true}}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if provider.Status() != openfeature.ReadyState {
+		t.Errorf("Expected status %q, got %q", openfeature.ReadyState, provider.Status())
+	}
+}
+
+func TestInit_StaleWithoutRealtime_ReadyAfterSuccessfulFetch(t *testing.T) {
+	var down int32 = 1
+	dispatcher := NewTestDispatcher()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&down) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		dispatcher.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithBootstrap([]FlagEvaluation{{Key: "dark-mode", Value: true}}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Expected Init to succeed with bootstrap, got: %v", err)
+	}
+	expectEvent(t, provider, openfeature.ProviderStale)
+
+	// Still unreachable
+	provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+	if provider.Status() != openfeature.StaleState {
+		t.Fatalf("Expected status %q, got %q", openfeature.StaleState, provider.Status())
+	}
+
+	atomic.StoreInt32(&down, 0)
+	provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+	if provider.Status() != openfeature.ReadyState {
+		t.Errorf("Expected status %q after a successful fetch, got %q", openfeature.ReadyState, provider.Status())
+	}
+	expectEvent(t, provider, openfeature.ProviderReady)
+}
+
+func TestInit_StaleWithoutRealtime_ClientReadyAfterSuccessfulFetch(t *testing.T) {
+	var down int32 = 1
+	dispatcher := NewTestDispatcher()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&down) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		dispatcher.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithBootstrap([]FlagEvaluation{{Key: "dark-mode", Value: true}}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	domain := fmt.Sprintf("stale-client-ready-after-fetch-%p", provider)
+	client := openfeature.NewClient(domain)
+	if err := openfeature.SetNamedProviderAndWait(domain, provider); err != nil {
+		t.Fatalf("Failed to set provider: %v", err)
+	}
+	waitForClientState(t, client, openfeature.StaleState)
+
+	atomic.StoreInt32(&down, 0)
+	provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+	waitForClientState(t, client, openfeature.ReadyState)
+}
+
+func TestInit_StaleWithRealtime_ClientReadyOnceSseConnects(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetInitFailure(http.StatusServiceUnavailable)
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithBootstrap([]FlagEvaluation{{Key: "dark-mode", Value: true}}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	domain := fmt.Sprintf("stale-client-ready-on-sse-%p", provider)
+	client := openfeature.NewClient(domain)
+	if err := openfeature.SetNamedProviderAndWait(domain, provider); err != nil {
+		t.Fatalf("Failed to set provider: %v", err)
+	}
+
+	// The connection may be established before Init returns
+	waitForClientState(t, client, openfeature.ReadyState)
+	time.Sleep(100 * time.Millisecond)
+	if state := client.State(); state != openfeature.ReadyState {
+		t.Errorf("Expected the client to stay READY, got %s", state)
+	}
+	if provider.Status() != openfeature.ReadyState {
+		t.Errorf("Expected status %q, got %q", openfeature.ReadyState, provider.Status())
+	}
+}
@@ -0,0 +1,60 @@
+package flipswitch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// WithBulkUnsupportedFallback handles servers that implement only single
+// flag evaluation and answer the bulk endpoint with 404. When enabled, a
+// bulk evaluation that gets a 404 evaluates the keys given with
+// WithBulkFallbackKeys one request at a time instead, and returns those that
+// exist. The provider remembers that bulk evaluation is unsupported and
+// skips the bulk request from then on. Disabled by default, in which case a
+// 404 fails the bulk evaluation like any other error status.
+func WithBulkUnsupportedFallback(enabled bool) Option {
+	return func(p *FlipswitchProvider) {
+		p.bulkUnsupportedFallback = enabled
+	}
+}
+
+// WithBulkFallbackKeys sets the flags evaluated individually in place of a
+// bulk evaluation when WithBulkUnsupportedFallback is enabled and the server
+// does not support bulk evaluation.
+func WithBulkFallbackKeys(keys ...string) Option {
+	return func(p *FlipswitchProvider) {
+		p.bulkFallbackKeys = keys
+	}
+}
+
+// isBulkUnsupported reports whether err is a 404 from the bulk endpoint.
+func isBulkUnsupported(err error) bool {
+	var se *HTTPStatusError
+	return errors.As(err, &se) && se.StatusCode == http.StatusNotFound
+}
+
+// fetchFlagsIndividually evaluates the bulk fallback keys with single flag
+// requests. Flags that do not exist are left out; any other failure fails
+// the whole evaluation, as it would for a bulk request.
+func (p *FlipswitchProvider) fetchFlagsIndividually(ctx context.Context, version string, evalCtx openfeature.FlattenedContext) ([]FlagEvaluation, error) {
+	sentContext := p.outgoingContext(evalCtx)
+	results := make([]FlagEvaluation, 0, len(p.bulkFallbackKeys))
+	for _, key := range p.bulkFallbackKeys {
+		statusCode, respBody, err := p.postFlagAt(ctx, key, version, sentContext)
+		if err != nil {
+			return nil, err
+		}
+		eval, err := parseFlagResponse(key, statusCode, respBody)
+		if errors.Is(err, ErrFlagNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *eval)
+	}
+	return results, nil
+}
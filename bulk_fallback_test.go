@@ -0,0 +1,80 @@
+package flipswitch
+
+import (
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// singleFlagOnlyServer answers bulk evaluation with 404 and serves flag-a
+// and flag-b individually.
+func singleFlagOnlyServer(bulkCalls *int32) *httptest.Server {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		atomic.AddInt32(bulkCalls, 1)
+		return 404, map[string]interface{}{}
+	})
+	dispatcher.SetFlagResponse("flag-a", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "flag-a", "value": true, "reason": "STATIC"}
+	})
+	dispatcher.SetFlagResponse("flag-b", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "flag-b", "value": "blue", "variant": "b"}
+	})
+	return httptest.NewServer(dispatcher)
+}
+
+func TestBulkUnsupportedFallback_EvaluatesKeysIndividually(t *testing.T) {
+	var bulkCalls int32
+	server := singleFlagOnlyServer(&bulkCalls)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithBulkUnsupportedFallback(true),
+		WithBulkFallbackKeys("flag-a", "flag-b", "missing"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	for i := 0; i < 2; i++ {
+		flags := provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+		if len(flags) != 2 {
+			t.Fatalf("Call %d: expected 2 flags, got %+v", i+1, flags)
+		}
+		if flags[0].Key != "flag-a" || !flags[0].AsBoolean() || flags[0].Reason != "STATIC" {
+			t.Errorf("Call %d: unexpected flag-a %+v", i+1, flags[0])
+		}
+		if flags[1].Key != "flag-b" || flags[1].AsString() != "blue" || flags[1].Variant != "b" {
+			t.Errorf("Call %d: unexpected flag-b %+v", i+1, flags[1])
+		}
+	}
+
+	if got := atomic.LoadInt32(&bulkCalls); got != 1 {
+		t.Errorf("Expected the bulk endpoint to be tried once, got %d", got)
+	}
+}
+
+func TestBulkUnsupportedFallback_DisabledByDefault(t *testing.T) {
+	var bulkCalls int32
+	server := singleFlagOnlyServer(&bulkCalls)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithBulkFallbackKeys("flag-a", "flag-b"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if flags := provider.EvaluateAllFlags(openfeature.FlattenedContext{}); len(flags) != 0 {
+		t.Errorf("Expected no flags without the fallback, got %+v", flags)
+	}
+}
@@ -0,0 +1,206 @@
+package flipswitch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// errBulkNotObject is returned when a bulk evaluation response is not a JSON
+// object.
+var errBulkNotObject = errors.New("bulk response is not a JSON object")
+
+// EvaluateAllFlagsStream evaluates all flags like EvaluateAllFlagsE, but
+// decodes the response incrementally and passes each flag to fn as soon as
+// it has been parsed, so the whole response is never held in memory at once.
+// Malformed items are skipped, as by EvaluateAllFlags. If fn returns an
+// error, no further flags are delivered and that error is returned. A
+// request or parse error is returned once the flags before it have been
+// delivered; there is no bootstrap fallback.
+//
+// With WithResponseVerification the response has to be read in full and
+// verified before the first flag is delivered.
+func (p *FlipswitchProvider) EvaluateAllFlagsStream(ctx context.Context, evalCtx openfeature.FlattenedContext, fn func(FlagEvaluation) error) error {
+	start := time.Now()
+	evalCtx = p.withBaggage(ctx, evalCtx)
+	source := p.fetchSource()
+
+	delivered, stopped := 0, false
+	err := p.streamAllFlags(ctx, evalCtx, func(flag FlagEvaluation) error {
+		if eval := p.envOverride(flag.Key); eval != nil {
+			flag = *eval
+		}
+		eval := p.transformFlag(&flag)
+		p.observeFlag(eval.Key, start, eval, source, nil)
+		delivered++
+		if err := fn(*eval); err != nil {
+			stopped = true
+			return err
+		}
+		return nil
+	})
+	if err != nil && !stopped {
+		p.logger.Errorw("Error evaluating all flags", errorFields(err)...)
+		if delivered == 0 {
+			p.observeAll(start, nil, EvaluationSourceDefault, err)
+		}
+	}
+	return err
+}
+
+// streamAllFlags performs the bulk evaluation for EvaluateAllFlagsStream.
+// In offline mode, and when the server does not support bulk evaluation, the
+// flags are fetched as usual and then delivered one by one.
+func (p *FlipswitchProvider) streamAllFlags(ctx context.Context, evalCtx openfeature.FlattenedContext, fn func(FlagEvaluation) error) error {
+	if p.offlineMode || p.bulkUnsupported.Load() {
+		return p.deliverAllFlags(ctx, evalCtx, fn)
+	}
+
+	resp, err := p.sendBulkRequest(ctx, "", evalCtx)
+	if p.bulkUnsupportedFallback && isBulkUnsupported(err) {
+		p.logger.Infow("Bulk evaluation is not supported by the server, evaluating flags individually")
+		p.bulkUnsupported.Store(true)
+		return p.deliverAllFlags(ctx, evalCtx, fn)
+	}
+	if err != nil {
+		p.recordError(OperationBulkEvaluation, "", err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	if p.verificationKey != nil {
+		data, err := io.ReadAll(resp.Body)
+		if err == nil {
+			err = p.verifyResponse(resp, data)
+		} else {
+			err = fmt.Errorf("reading response: %w", err)
+		}
+		if err != nil {
+			p.recordError(OperationBulkEvaluation, "", err)
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	stopped := false
+	err = decodeBulkStream(body, func(flag FlagEvaluation) error {
+		if err := fn(flag); err != nil {
+			stopped = true
+			return err
+		}
+		return nil
+	})
+	if err != nil && !stopped {
+		p.recordError(OperationBulkEvaluation, "", err)
+	}
+	return err
+}
+
+// deliverAllFlags fetches all flags with fetchAllFlags and passes them to fn
+// in order.
+func (p *FlipswitchProvider) deliverAllFlags(ctx context.Context, evalCtx openfeature.FlattenedContext, fn func(FlagEvaluation) error) error {
+	flags, err := p.fetchAllFlags(ctx, evalCtx)
+	if err != nil {
+		return err
+	}
+	for _, flag := range flags {
+		if err := fn(flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeBulkStream reads a bulk evaluation response, {"flags": [...]}, from
+// r and passes each well-formed item of the flags array to fn as it is
+// decoded. Other members, and a flags member that is not an array, are
+// skipped, as parseBulkResponse ignores them.
+func decodeBulkStream(r io.Reader, fn func(FlagEvaluation) error) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("parsing response: %w", errBulkNotObject)
+	}
+
+	for dec.More() {
+		name, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+		if name != "flags" {
+			if err := skipValue(dec); err != nil {
+				return fmt.Errorf("parsing response: %w", err)
+			}
+			continue
+		}
+
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+		if tok != json.Delim('[') {
+			if err := skipRest(dec, tok); err != nil {
+				return fmt.Errorf("parsing response: %w", err)
+			}
+			continue
+		}
+		for dec.More() {
+			var item interface{}
+			if err := dec.Decode(&item); err != nil {
+				return fmt.Errorf("parsing response: %w", err)
+			}
+			if eval, ok := bulkEntry(item); ok {
+				if err := fn(eval); err != nil {
+					return err
+				}
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	return nil
+}
+
+// skipValue consumes the next JSON value from dec.
+func skipValue(dec *json.Decoder) error {
+	var raw json.RawMessage
+	return dec.Decode(&raw)
+}
+
+// skipRest consumes the remainder of the value that starts with tok, which
+// has already been read from dec.
+func skipRest(dec *json.Decoder, tok json.Token) error {
+	if delim, ok := tok.(json.Delim); !ok || (delim != '{' && delim != '[') {
+		return nil
+	}
+	for depth := 1; depth > 0; {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package flipswitch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// largeBulkResponse returns a bulk response with n flags, preceded by
+// members other than flags and interleaved with malformed items.
+func largeBulkResponse(n int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`{"metadata":{"version":"v1","tags":["a","b"]},"flags":[`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if i%100 == 50 {
+			buf.WriteString(`"not-an-object",{"value":true},`)
+		}
+		fmt.Fprintf(&buf, `{"key":"flag-%d","value":%d,"reason":"STATIC","variant":"v%d"}`, i, i, i%3)
+	}
+	buf.WriteString(`],"trailer":null}`)
+	return buf.Bytes()
+}
+
+func bulkBodyServer(body []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+}
+
+func TestEvaluateAllFlagsStream_DeliversEveryFlag(t *testing.T) {
+	const n = 20000
+	server := bulkBodyServer(largeBulkResponse(n))
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	count := 0
+	err = provider.EvaluateAllFlagsStream(context.Background(), openfeature.FlattenedContext{}, func(flag FlagEvaluation) error {
+		if want := fmt.Sprintf("flag-%d", count); flag.Key != want {
+			return fmt.Errorf("flag %d: got key %q, want %q", count, flag.Key, want)
+		}
+		if flag.AsInt() != count || flag.Reason != "STATIC" {
+			return fmt.Errorf("flag %d: unexpected evaluation %+v", count, flag)
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count != n {
+		t.Errorf("Expected %d flags, got %d", n, count)
+	}
+}
+
+func TestEvaluateAllFlagsStream_CallbackErrorStops(t *testing.T) {
+	server := bulkBodyServer(largeBulkResponse(100))
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	errStop := errors.New("stop")
+	count := 0
+	err = provider.EvaluateAllFlagsStream(context.Background(), openfeature.FlattenedContext{}, func(FlagEvaluation) error {
+		count++
+		if count == 3 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected delivery to stop after 3 flags, got %d", count)
+	}
+	if history := provider.RecentErrors(); len(history) != 0 {
+		t.Errorf("Expected a callback error not to be recorded, got %v", history)
+	}
+}
+
+func TestEvaluateAllFlagsStream_InvalidAPIKey(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetInitFailure(http.StatusUnauthorized)
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	err = provider.EvaluateAllFlagsStream(context.Background(), openfeature.FlattenedContext{}, func(FlagEvaluation) error {
+		t.Error("Expected no flags to be delivered")
+		return nil
+	})
+	if !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey, got %v", err)
+	}
+}
+
+func TestDecodeBulkStream_MatchesParseBulkResponse(t *testing.T) {
+	bodies := []string{
+		`{"flags":[{"key":"a","value":true},{"key":"b","value":"x","reason":"DEFAULT"}]}`,
+		`{"flags":[1,"two",null,{"nokey":true},{"key":3},{"key":"ok","value":{"nested":[1,2]}}]}`,
+		`{"other":{"flags":[{"key":"hidden"}]},"flags":[{"key":"shown","value":1.5}]}`,
+		`{"flags":{"key":"not-an-array"}}`,
+		`{"flags":"nope"}`,
+		`{"flags":[]}`,
+		`{}`,
+		`null`,
+	}
+	for _, body := range bodies {
+		want, err := parseBulkResponse([]byte(body))
+		if err != nil {
+			t.Fatalf("parseBulkResponse(%s): %v", body, err)
+		}
+		got := make([]FlagEvaluation, 0)
+		if err := decodeBulkStream(strings.NewReader(body), func(flag FlagEvaluation) error {
+			got = append(got, flag)
+			return nil
+		}); err != nil {
+			t.Errorf("decodeBulkStream(%s): %v", body, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("decodeBulkStream(%s) = %+v, want %+v", body, got, want)
+		}
+	}
+}
+
+func TestDecodeBulkStream_InvalidJSON(t *testing.T) {
+	for _, body := range []string{``, `[]`, `{"flags":[{"key":"a"}`, `{"flags":[{"key":}]}`} {
+		err := decodeBulkStream(strings.NewReader(body), func(FlagEvaluation) error { return nil })
+		if err == nil {
+			t.Errorf("decodeBulkStream(%q): expected an error", body)
+		}
+	}
+}
+
+func BenchmarkBulkDecoding(b *testing.B) {
+	body := largeBulkResponse(5000)
+	for _, bc := range []struct {
+		name   string
+		decode func() error
+	}{
+		{"buffered", func() error {
+			_, err := parseBulkResponse(body)
+			return err
+		}},
+		{"stream", func() error {
+			return decodeBulkStream(bytes.NewReader(body), func(FlagEvaluation) error { return nil })
+		}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(body)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := bc.decode(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package flipswitch
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// cacheEntry is a cached evaluation and the time it stops being served.
+// expires is derived from time.Now, so it carries a monotonic clock reading
+// and comparisons against it are unaffected by wall clock changes.
+type cacheEntry struct {
+	eval    FlagEvaluation
+	expires time.Time
+	// elem is the entry's position in the cache's recency list
+	elem *list.Element
+}
+
+// cacheKey identifies a cache entry in the recency list.
+type cacheKey struct {
+	flagKey string
+	ctxHash string
+}
+
+// evaluationCache holds recent single flag evaluations keyed by flag key and
+// context hash. A nil cache stores nothing.
+type evaluationCache struct {
+	ttl     time.Duration
+	entries map[string]map[string]cacheEntry // flag key -> context hash -> entry
+	// recency orders entries from most to least recently used; when it
+	// grows past maxEntries the least recently used entry is evicted
+	recency    *list.List
+	maxEntries int
+	// generation is bumped on every invalidation so that a fetch started
+	// before the invalidation cannot store its now stale result.
+	generation uint64
+	// now returns the current time; replaced in tests
+	now func() time.Time
+	mu  sync.Mutex
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func newEvaluationCache(ttl time.Duration) *evaluationCache {
+	return &evaluationCache{
+		ttl:     ttl,
+		entries: make(map[string]map[string]cacheEntry),
+		recency: list.New(),
+		now:     time.Now,
+	}
+}
+
+// WithCache caches evaluation results for ttl, keyed by flag key and
+// evaluation context. The cache is shared by EvaluateFlag and the typed
+// OpenFeature evaluations. Entries are invalidated when an SSE event reports
+// the flag changed. Caching is disabled by default.
+func WithCache(ttl time.Duration) Option {
+	return func(p *FlipswitchProvider) {
+		if ttl <= 0 {
+			p.cache = nil
+			return
+		}
+		p.cache = newEvaluationCache(ttl)
+	}
+}
+
+// WithCacheMaxEntries caps the number of evaluations held by the cache set
+// with WithCache. Every flag and context pair is a separate entry, so with
+// many distinct users the cache can otherwise grow without limit. When the
+// cap is reached the least recently used entry is evicted. Zero, the
+// default, means no cap. The cap also applies to the last-known values kept
+// by WithServeStaleOnError.
+func WithCacheMaxEntries(n int) Option {
+	return func(p *FlipswitchProvider) {
+		p.cacheMaxEntries = n
+	}
+}
+
+// get returns the cached evaluation for flagKey and context hash, if one
+// exists and has not expired, along with the current generation.
+func (c *evaluationCache) get(flagKey, ctxHash string) (FlagEvaluation, uint64, bool) {
+	if c == nil {
+		return FlagEvaluation{}, 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[flagKey][ctxHash]
+	if !ok {
+		c.misses.Add(1)
+		return FlagEvaluation{}, c.generation, false
+	}
+	if !c.now().Before(entry.expires) {
+		c.remove(flagKey, ctxHash, entry)
+		c.misses.Add(1)
+		return FlagEvaluation{}, c.generation, false
+	}
+	c.recency.MoveToFront(entry.elem)
+	c.hits.Add(1)
+	return entry.eval, c.generation, true
+}
+
+// currentGeneration returns the generation to pass to set for a fetch that
+// starts now.
+func (c *evaluationCache) currentGeneration() uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// set stores eval unless the cache was invalidated since generation was read.
+func (c *evaluationCache) set(flagKey, ctxHash string, eval FlagEvaluation, generation uint64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	byContext, ok := c.entries[flagKey]
+	if !ok {
+		byContext = make(map[string]cacheEntry)
+		c.entries[flagKey] = byContext
+	}
+	entry, ok := byContext[ctxHash]
+	if ok {
+		c.recency.MoveToFront(entry.elem)
+	} else {
+		entry.elem = c.recency.PushFront(cacheKey{flagKey: flagKey, ctxHash: ctxHash})
+	}
+	entry.eval = eval
+	entry.expires = c.now().Add(c.ttl)
+	byContext[ctxHash] = entry
+
+	for c.maxEntries > 0 && c.recency.Len() > c.maxEntries {
+		oldest := c.recency.Back().Value.(cacheKey)
+		c.remove(oldest.flagKey, oldest.ctxHash, c.entries[oldest.flagKey][oldest.ctxHash])
+	}
+}
+
+// remove deletes an entry. The caller must hold c.mu.
+func (c *evaluationCache) remove(flagKey, ctxHash string, entry cacheEntry) {
+	c.recency.Remove(entry.elem)
+	delete(c.entries[flagKey], ctxHash)
+	if len(c.entries[flagKey]) == 0 {
+		delete(c.entries, flagKey)
+	}
+}
+
+// len returns the number of entries held, including expired ones not yet
+// removed.
+func (c *evaluationCache) len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.recency.Len()
+}
+
+// invalidate drops every entry for flagKey, or all entries if flagKey is
+// empty.
+func (c *evaluationCache) invalidate(flagKey string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	if flagKey == "" {
+		c.entries = make(map[string]map[string]cacheEntry)
+		c.recency.Init()
+		return
+	}
+	for _, entry := range c.entries[flagKey] {
+		c.recency.Remove(entry.elem)
+	}
+	delete(c.entries, flagKey)
+}
+
+// CacheStats returns the number of evaluations served from the cache, the
+// number of cache lookups that had to go to the server, and the hit ratio
+// hits / (hits + misses), counted since the provider was created. The ratio
+// is 0 before the first lookup. All values are 0 unless WithCache is set.
+func (p *FlipswitchProvider) CacheStats() (hits, misses uint64, ratio float64) {
+	if p.cache == nil {
+		return 0, 0, 0
+	}
+	hits = p.cache.hits.Load()
+	misses = p.cache.misses.Load()
+	if total := hits + misses; total > 0 {
+		ratio = float64(hits) / float64(total)
+	}
+	return hits, misses, ratio
+}
+
+// InvalidateCache drops the cached evaluations of flagKey for every context,
+// so the next EvaluateFlag call fetches it from the server. An empty flagKey
+// invalidates all flags. It has no effect unless WithCache is set.
+func (p *FlipswitchProvider) InvalidateCache(flagKey string) {
+	p.cache.invalidate(flagKey)
+}
+
+// InvalidateAllCache drops every cached evaluation.
+func (p *FlipswitchProvider) InvalidateAllCache() {
+	p.cache.invalidate("")
+}
+
+// ClearCache drops every cached evaluation and resets the counters reported
+// by CacheStats, returning the cache to the state it had when the provider
+// was created. It has no effect unless WithCache is set.
+func (p *FlipswitchProvider) ClearCache() {
+	if p.cache == nil {
+		return
+	}
+	p.cache.invalidate("")
+	p.cache.hits.Store(0)
+	p.cache.misses.Store(0)
+}
+
+// invalidateCacheFor drops the cache entries and initial flag seeds affected
+// by a flag change event.
+func (p *FlipswitchProvider) invalidateCacheFor(event FlagChangeEvent) {
+	switch {
+	case event.FlagKey != "":
+		p.cache.invalidate(event.FlagKey)
+		p.initialFlags.drop(event.FlagKey)
+	case len(event.AffectedKeys) > 0:
+		for _, key := range event.AffectedKeys {
+			p.cache.invalidate(key)
+			p.initialFlags.drop(key)
+		}
+	default:
+		p.cache.invalidate("")
+		p.initialFlags.drop("")
+	}
+}
+
+// cachedEvaluation returns a copy of the cached evaluation for flagKey and
+// evalCtx, if any, the context hash and the cache generation to store a
+// result under.
+func (p *FlipswitchProvider) cachedEvaluation(flagKey string, evalCtx openfeature.FlattenedContext) (*FlagEvaluation, string, uint64) {
+	if p.cache == nil && p.lastKnown == nil {
+		return nil, "", 0
+	}
+	ctxHash := contextHash(evalCtx)
+	if p.cache == nil {
+		return nil, ctxHash, 0
+	}
+	eval, generation, ok := p.cache.get(flagKey, ctxHash)
+	p.metrics.observeCache(ok)
+	if !ok {
+		return nil, ctxHash, generation
+	}
+	return &eval, ctxHash, generation
+}
+
+// sharedCacheLookup lets the OFREP-backed typed evaluations share the cache
+// with EvaluateFlag, so both paths serve the same value for the same flag and
+// context. A value remembered by the request-scoped cache on ctx takes
+// precedence. It returns the cached evaluation, if any, and a function that
+// stores a successful resolution for later calls on either path.
+func (p *FlipswitchProvider) sharedCacheLookup(ctx context.Context, flag string, evalCtx openfeature.FlattenedContext) (*FlagEvaluation, func(value interface{}, detail openfeature.ProviderResolutionDetail)) {
+	requests := requestCacheFrom(ctx)
+	if eval := requests.get(flag, evalCtx); eval != nil {
+		return eval, func(interface{}, openfeature.ProviderResolutionDetail) {}
+	}
+
+	cached, ctxHash, generation := p.cachedEvaluation(flag, evalCtx)
+	if cached == nil {
+		cached = p.seededFlag(flag)
+	}
+	if cached != nil {
+		requests.set(flag, evalCtx, *cached)
+	}
+	store := func(value interface{}, detail openfeature.ProviderResolutionDetail) {
+		if (p.cache == nil && p.lastKnown == nil && requests == nil) || detail.Reason == openfeature.ErrorReason || detail.ResolutionDetail().ErrorCode != "" {
+			return
+		}
+		// Store numbers the way the direct path decodes them from JSON
+		switch v := value.(type) {
+		case int64:
+			value = float64(v)
+		case int:
+			value = float64(v)
+		}
+		eval := FlagEvaluation{
+			Key:       flag,
+			Value:     value,
+			ValueType: inferType(value),
+			Reason:    string(detail.Reason),
+			Variant:   detail.Variant,
+		}
+		p.cache.set(flag, ctxHash, eval, generation)
+		p.lastKnown.set(flag, ctxHash, eval)
+		requests.set(flag, evalCtx, eval)
+	}
+	return cached, store
+}
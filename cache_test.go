@@ -0,0 +1,483 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// countingFlag returns a flag response func that counts every request.
+func countingFlag(flagKey string, calls *int32) func() (int, map[string]interface{}) {
+	return func() (int, map[string]interface{}) {
+		atomic.AddInt32(calls, 1)
+		return 200, map[string]interface{}{"key": flagKey, "value": true}
+	}
+}
+
+// createCachingProvider creates a provider caching evaluations for ttl, with
+// realtime disabled.
+func createCachingProvider(t *testing.T, server *httptest.Server, ttl time.Duration) *FlipswitchProvider {
+	t.Helper()
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithCache(ttl),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	return provider
+}
+
+func TestCache_HitAvoidsRequest(t *testing.T) {
+	var calls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("flag-a", countingFlag("flag-a", &calls))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createCachingProvider(t, server, time.Minute)
+	defer provider.Shutdown()
+
+	ctx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	for i := 0; i < 3; i++ {
+		if result := provider.EvaluateFlag("flag-a", ctx); result == nil || result.Value != true {
+			t.Fatalf("Expected flag-a to evaluate to true, got %+v", result)
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+
+	// A different context is a separate entry
+	provider.EvaluateFlag("flag-a", openfeature.FlattenedContext{"targetingKey": "user-2"})
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 requests, got %d", got)
+	}
+}
+
+func TestCache_DisabledByDefault(t *testing.T) {
+	var calls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("flag-a", countingFlag("flag-a", &calls))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("flag-a", openfeature.FlattenedContext{})
+	provider.EvaluateFlag("flag-a", openfeature.FlattenedContext{})
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 requests without a cache, got %d", got)
+	}
+}
+
+func TestCache_ExpiresAfterTTL(t *testing.T) {
+	var calls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("flag-a", countingFlag("flag-a", &calls))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createCachingProvider(t, server, 20*time.Millisecond)
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("flag-a", openfeature.FlattenedContext{})
+	time.Sleep(40 * time.Millisecond)
+	provider.EvaluateFlag("flag-a", openfeature.FlattenedContext{})
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected expired entry to be re-fetched, got %d requests", got)
+	}
+}
+
+func TestInvalidateCache_OnlyRefetchesInvalidatedKey(t *testing.T) {
+	var callsA, callsB int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("flag-a", countingFlag("flag-a", &callsA))
+	dispatcher.SetFlagResponse("flag-b", countingFlag("flag-b", &callsB))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createCachingProvider(t, server, time.Minute)
+	defer provider.Shutdown()
+
+	ctx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	provider.EvaluateFlag("flag-a", ctx)
+	provider.EvaluateFlag("flag-b", ctx)
+
+	provider.InvalidateCache("flag-a")
+
+	provider.EvaluateFlag("flag-a", ctx)
+	provider.EvaluateFlag("flag-b", ctx)
+
+	if got := atomic.LoadInt32(&callsA); got != 2 {
+		t.Errorf("Expected flag-a to be re-fetched (2 requests), got %d", got)
+	}
+	if got := atomic.LoadInt32(&callsB); got != 1 {
+		t.Errorf("Expected flag-b to stay cached (1 request), got %d", got)
+	}
+}
+
+func TestInvalidateAllCache_RefetchesEveryKey(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		invalidate func(p *FlipswitchProvider)
+	}{
+		{"InvalidateAllCache", func(p *FlipswitchProvider) { p.InvalidateAllCache() }},
+		{"InvalidateCacheEmptyKey", func(p *FlipswitchProvider) { p.InvalidateCache("") }},
+		{"ClearCache", func(p *FlipswitchProvider) { p.ClearCache() }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var callsA, callsB int32
+			dispatcher := NewTestDispatcher()
+			dispatcher.SetFlagResponse("flag-a", countingFlag("flag-a", &callsA))
+			dispatcher.SetFlagResponse("flag-b", countingFlag("flag-b", &callsB))
+			server := httptest.NewServer(dispatcher)
+			defer server.Close()
+
+			provider := createCachingProvider(t, server, time.Minute)
+			defer provider.Shutdown()
+
+			provider.EvaluateFlag("flag-a", openfeature.FlattenedContext{})
+			provider.EvaluateFlag("flag-b", openfeature.FlattenedContext{})
+
+			tc.invalidate(provider)
+
+			provider.EvaluateFlag("flag-a", openfeature.FlattenedContext{})
+			provider.EvaluateFlag("flag-b", openfeature.FlattenedContext{})
+
+			if atomic.LoadInt32(&callsA) != 2 || atomic.LoadInt32(&callsB) != 2 {
+				t.Errorf("Expected both flags to be re-fetched, got %d and %d requests", callsA, callsB)
+			}
+		})
+	}
+}
+
+func TestCache_FlagChangeEventInvalidatesKey(t *testing.T) {
+	var callsA, callsB int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("flag-a", countingFlag("flag-a", &callsA))
+	dispatcher.SetFlagResponse("flag-b", countingFlag("flag-b", &callsB))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createCachingProvider(t, server, time.Minute)
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("flag-a", openfeature.FlattenedContext{})
+	provider.EvaluateFlag("flag-b", openfeature.FlattenedContext{})
+
+	provider.handleFlagChange(FlagChangeEvent{FlagKey: "flag-b"})
+
+	provider.EvaluateFlag("flag-a", openfeature.FlattenedContext{})
+	provider.EvaluateFlag("flag-b", openfeature.FlattenedContext{})
+
+	if got := atomic.LoadInt32(&callsA); got != 1 {
+		t.Errorf("Expected flag-a to stay cached (1 request), got %d", got)
+	}
+	if got := atomic.LoadInt32(&callsB); got != 2 {
+		t.Errorf("Expected flag-b to be re-fetched (2 requests), got %d", got)
+	}
+}
+
+func TestCache_InvalidationDuringFetchIsNotOverwritten(t *testing.T) {
+	cache := newEvaluationCache(time.Minute)
+
+	_, generation, _ := cache.get("flag-a", "ctx")
+	cache.invalidate("flag-a")
+	cache.set("flag-a", "ctx", FlagEvaluation{Key: "flag-a", Value: true}, generation)
+
+	if _, _, ok := cache.get("flag-a", "ctx"); ok {
+		t.Error("Expected result fetched before invalidation not to be cached")
+	}
+}
+
+func TestCache_ExpiryUsesMonotonicClock(t *testing.T) {
+	cache := newEvaluationCache(time.Minute)
+	cache.set("flag-a", "ctx", FlagEvaluation{Key: "flag-a"}, cache.currentGeneration())
+
+	entry := cache.entries["flag-a"]["ctx"]
+	// Times carrying a monotonic reading print it as "m=±<seconds>"
+	if !strings.Contains(entry.expires.String(), "m=") {
+		t.Errorf("Expected expiry to carry a monotonic reading, got %s", entry.expires)
+	}
+}
+
+func TestCache_MonotonicTimesExpireAfterTTL(t *testing.T) {
+	// Times derived from time.Now keep their monotonic reading, as the
+	// cache's own do, so a wall clock step cannot move these comparisons
+	start := time.Now()
+	now := start
+	cache := newEvaluationCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.set("flag-a", "ctx", FlagEvaluation{Key: "flag-a", Value: true}, cache.currentGeneration())
+
+	now = start.Add(30 * time.Second)
+	if _, _, ok := cache.get("flag-a", "ctx"); !ok {
+		t.Error("Expected entry to remain fresh within its TTL")
+	}
+
+	now = start.Add(time.Minute)
+	if _, _, ok := cache.get("flag-a", "ctx"); ok {
+		t.Error("Expected entry to expire once its TTL has passed")
+	}
+}
+
+// switchingFlag returns a flag response func serving value until it is
+// changed through the returned setter.
+func switchingFlag(flagKey string, value interface{}) (func() (int, map[string]interface{}), func(interface{})) {
+	var mu sync.Mutex
+	fn := func() (int, map[string]interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		return 200, map[string]interface{}{"key": flagKey, "value": value, "reason": "TARGETING_MATCH"}
+	}
+	set := func(v interface{}) {
+		mu.Lock()
+		value = v
+		mu.Unlock()
+	}
+	return fn, set
+}
+
+func TestCache_SharedBetweenDirectAndOfrepPaths(t *testing.T) {
+	fn, setValue := switchingFlag("dark-mode", true)
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("dark-mode", fn)
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createCachingProvider(t, server, time.Minute)
+	defer provider.Shutdown()
+
+	ctx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+
+	// Prime through the direct path, then change the flag on the server
+	direct := provider.EvaluateFlag("dark-mode", ctx)
+	setValue(false)
+
+	result := provider.BooleanEvaluation(context.Background(), "dark-mode", false, ctx)
+	if direct == nil || result.Value != direct.Value {
+		t.Errorf("Expected OFREP path to match direct value %+v, got %v", direct, result.Value)
+	}
+	if result.Reason != openfeature.CachedReason {
+		t.Errorf("Expected reason %s, got %s", openfeature.CachedReason, result.Reason)
+	}
+}
+
+func TestCache_OfrepResultServedToDirectPath(t *testing.T) {
+	var calls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("max-items", func() (int, map[string]interface{}) {
+		atomic.AddInt32(&calls, 1)
+		return 200, map[string]interface{}{"key": "max-items", "value": 10, "variant": "ten"}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createCachingProvider(t, server, time.Minute)
+	defer provider.Shutdown()
+
+	ctx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	typed := provider.IntEvaluation(context.Background(), "max-items", 0, ctx)
+	if typed.Value != 10 {
+		t.Fatalf("Expected 10, got %d", typed.Value)
+	}
+
+	direct := provider.EvaluateFlag("max-items", ctx)
+	if direct == nil || direct.AsInt() != 10 || direct.Variant != "ten" {
+		t.Errorf("Expected direct path to serve the cached OFREP result, got %+v", direct)
+	}
+	// Numbers are stored as the direct path decodes them from JSON
+	if _, ok := direct.Value.(float64); !ok {
+		t.Errorf("Expected cached number as float64, got %T", direct.Value)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 request across both paths, got %d", got)
+	}
+}
+
+func TestCacheStats_CountsHitsAndMisses(t *testing.T) {
+	var calls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", countingFlag("my-flag", &calls))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createCachingProvider(t, server, time.Minute)
+	defer provider.Shutdown()
+
+	if hits, misses, ratio := provider.CacheStats(); hits != 0 || misses != 0 || ratio != 0 {
+		t.Errorf("Expected zero stats before any lookup, got %d, %d, %v", hits, misses, ratio)
+	}
+
+	userA := openfeature.FlattenedContext{"targetingKey": "user-a"}
+	userB := openfeature.FlattenedContext{"targetingKey": "user-b"}
+	provider.EvaluateFlag("my-flag", userA)                                   // miss
+	provider.EvaluateFlag("my-flag", userA)                                   // hit
+	provider.EvaluateFlag("my-flag", userA)                                   // hit
+	provider.EvaluateFlag("my-flag", userB)                                   // miss
+	provider.BooleanEvaluation(context.Background(), "my-flag", false, userB) // hit
+	provider.InvalidateCache("my-flag")
+	provider.EvaluateFlag("my-flag", userA) // miss
+
+	hits, misses, ratio := provider.CacheStats()
+	if hits != 3 || misses != 3 {
+		t.Errorf("Expected 3 hits and 3 misses, got %d and %d", hits, misses)
+	}
+	if ratio != 0.5 {
+		t.Errorf("Expected ratio 0.5, got %v", ratio)
+	}
+	if got := atomic.LoadInt32(&calls); int(got) != int(misses) {
+		t.Errorf("Expected one request per miss, got %d requests", got)
+	}
+}
+
+func TestCacheStats_ZeroWithoutCache(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", countingFlag("my-flag", new(int32)))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	if hits, misses, ratio := provider.CacheStats(); hits != 0 || misses != 0 || ratio != 0 {
+		t.Errorf("Expected zero stats without a cache, got %d, %d, %v", hits, misses, ratio)
+	}
+}
+
+func TestClearCache_ResetsStats(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", countingFlag("my-flag", new(int32)))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createCachingProvider(t, server, time.Minute)
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+
+	provider.ClearCache()
+	if hits, misses, ratio := provider.CacheStats(); hits != 0 || misses != 0 || ratio != 0 {
+		t.Errorf("Expected stats to be reset, got %d, %d, %v", hits, misses, ratio)
+	}
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	if hits, misses, _ := provider.CacheStats(); hits != 0 || misses != 1 {
+		t.Errorf("Expected the cleared flag to miss, got %d hits, %d misses", hits, misses)
+	}
+}
+
+func TestCache_MaxEntriesIsNeverExceeded(t *testing.T) {
+	cache := newEvaluationCache(time.Minute)
+	cache.maxEntries = 3
+
+	for i := 0; i < 10; i++ {
+		ctxHash := intToString(i)
+		cache.set("flag-a", ctxHash, FlagEvaluation{Key: "flag-a"}, cache.currentGeneration())
+		if got := cache.len(); got > 3 {
+			t.Fatalf("Expected at most 3 entries, got %d", got)
+		}
+	}
+
+	// The three most recent entries are the ones kept
+	for i := 0; i < 10; i++ {
+		_, _, ok := cache.get("flag-a", intToString(i))
+		if want := i >= 7; ok != want {
+			t.Errorf("Entry %d: expected cached=%v, got %v", i, want, ok)
+		}
+	}
+}
+
+func TestCache_AccessedEntrySurvivesEviction(t *testing.T) {
+	cache := newEvaluationCache(time.Minute)
+	cache.maxEntries = 2
+
+	cache.set("flag-a", "ctx", FlagEvaluation{Key: "flag-a"}, cache.currentGeneration())
+	cache.set("flag-b", "ctx", FlagEvaluation{Key: "flag-b"}, cache.currentGeneration())
+
+	// Touch flag-a so that flag-b is now the least recently used
+	if _, _, ok := cache.get("flag-a", "ctx"); !ok {
+		t.Fatal("Expected flag-a to be cached")
+	}
+	cache.set("flag-c", "ctx", FlagEvaluation{Key: "flag-c"}, cache.currentGeneration())
+
+	if _, _, ok := cache.get("flag-a", "ctx"); !ok {
+		t.Error("Expected recently used flag-a to survive eviction")
+	}
+	if _, _, ok := cache.get("flag-b", "ctx"); ok {
+		t.Error("Expected least recently used flag-b to be evicted")
+	}
+	if _, _, ok := cache.get("flag-c", "ctx"); !ok {
+		t.Error("Expected newly added flag-c to be cached")
+	}
+}
+
+func TestCache_InvalidateKeepsRecencyInSync(t *testing.T) {
+	cache := newEvaluationCache(time.Minute)
+	cache.maxEntries = 2
+
+	cache.set("flag-a", "ctx-1", FlagEvaluation{Key: "flag-a"}, cache.currentGeneration())
+	cache.set("flag-a", "ctx-2", FlagEvaluation{Key: "flag-a"}, cache.currentGeneration())
+	cache.invalidate("flag-a")
+	if got := cache.len(); got != 0 {
+		t.Fatalf("Expected an empty cache after invalidation, got %d entries", got)
+	}
+
+	cache.set("flag-b", "ctx", FlagEvaluation{Key: "flag-b"}, cache.currentGeneration())
+	cache.set("flag-c", "ctx", FlagEvaluation{Key: "flag-c"}, cache.currentGeneration())
+	if _, _, ok := cache.get("flag-b", "ctx"); !ok {
+		t.Error("Expected invalidated entries not to count towards the cap")
+	}
+}
+
+func TestWithCacheMaxEntries_BoundsProviderCache(t *testing.T) {
+	var calls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", countingFlag("my-flag", &calls))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	// The cap may be given before the cache itself
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithCacheMaxEntries(5),
+		WithCache(time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	for i := 0; i < 20; i++ {
+		provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{"targetingKey": "user-" + intToString(i)})
+	}
+	if got := provider.cache.len(); got != 5 {
+		t.Errorf("Expected the cache to hold 5 entries, got %d", got)
+	}
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{"targetingKey": "user-19"})
+	if got := atomic.LoadInt32(&calls); got != 20 {
+		t.Errorf("Expected the most recent context to be served from the cache, got %d requests", got)
+	}
+}
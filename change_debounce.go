@@ -0,0 +1,104 @@
+package flipswitch
+
+import (
+	"sort"
+	"time"
+)
+
+// WithChangeDebounce coalesces flag change events received over SSE. The
+// first event opens a window of length d; the flags changed within it are
+// reported once when it closes. A single changed flag is reported as a
+// targeted event, several as one bulk event listing them in AffectedKeys,
+// and a bulk change without a known scope collapses the window into a plain
+// bulk event. Listeners, cache invalidation and re-evaluation all see the
+// coalesced event. Pending changes are dropped on Shutdown. Zero, the
+// default, reports every event as it arrives.
+func WithChangeDebounce(d time.Duration) Option {
+	return func(p *FlipswitchProvider) {
+		if d > 0 {
+			p.changeDebounce = d
+		}
+	}
+}
+
+// pendingChanges collects the flag changes received within a debounce
+// window.
+type pendingChanges struct {
+	keys      map[string]bool
+	bulk      bool
+	timestamp string
+}
+
+// add merges event into the pending changes.
+func (c *pendingChanges) add(event FlagChangeEvent) {
+	c.timestamp = event.Timestamp
+	switch {
+	case event.FlagKey != "":
+		c.keys[event.FlagKey] = true
+	case len(event.AffectedKeys) > 0:
+		for _, key := range event.AffectedKeys {
+			c.keys[key] = true
+		}
+	default:
+		c.bulk = true
+	}
+}
+
+// event returns the pending changes as one event.
+func (c *pendingChanges) event() FlagChangeEvent {
+	event := FlagChangeEvent{Timestamp: c.timestamp}
+	if c.bulk {
+		return event
+	}
+	keys := make([]string, 0, len(c.keys))
+	for key := range c.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if len(keys) == 1 {
+		event.FlagKey = keys[0]
+	} else {
+		event.AffectedKeys = keys
+	}
+	return event
+}
+
+// handleSseFlagChange handles a flag change received over SSE, holding it
+// back for the debounce window if WithChangeDebounce is set.
+func (p *FlipswitchProvider) handleSseFlagChange(event FlagChangeEvent) {
+	if p.changeDebounce <= 0 {
+		p.handleFlagChange(event)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pendingChanges == nil {
+		p.pendingChanges = &pendingChanges{keys: make(map[string]bool)}
+		p.changeTimer = p.clock.AfterFunc(p.changeDebounce, p.flushChanges)
+	}
+	p.pendingChanges.add(event)
+}
+
+// flushChanges reports the changes collected in the debounce window.
+func (p *FlipswitchProvider) flushChanges() {
+	p.mu.Lock()
+	pending := p.pendingChanges
+	p.pendingChanges, p.changeTimer = nil, nil
+	p.mu.Unlock()
+	if pending == nil {
+		return
+	}
+
+	p.handleFlagChange(pending.event())
+}
+
+// stopChangeDebounce drops the changes pending in the debounce window.
+func (p *FlipswitchProvider) stopChangeDebounce() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.changeTimer != nil {
+		p.changeTimer.Stop()
+	}
+	p.pendingChanges, p.changeTimer = nil, nil
+}
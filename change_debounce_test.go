@@ -0,0 +1,113 @@
+package flipswitch
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// createDebouncedProvider returns a provider coalescing changes within
+// window, and a function returning the events its listener has received.
+func createDebouncedProvider(t *testing.T, window time.Duration, opts ...Option) (*FlipswitchProvider, func() []FlagChangeEvent) {
+	t.Helper()
+	provider, err := NewProvider("test-api-key", append([]Option{WithRealtime(false), WithChangeDebounce(window)}, opts...)...)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	t.Cleanup(provider.Shutdown)
+
+	var mu sync.Mutex
+	var events []FlagChangeEvent
+	provider.AddFlagChangeListener(func(event FlagChangeEvent) {
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+	})
+	return provider, func() []FlagChangeEvent {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]FlagChangeEvent(nil), events...)
+	}
+}
+
+func TestWithChangeDebounce_CoalescesBurst(t *testing.T) {
+	clock := newFakeClock()
+	provider, events := createDebouncedProvider(t, 100*time.Millisecond, withClock(clock))
+
+	for _, key := range []string{"b", "a", "c", "a", "d"} {
+		provider.handleSseFlagChange(FlagChangeEvent{FlagKey: key, Timestamp: "2024-01-01T00:00:00Z"})
+		clock.Advance(2 * time.Millisecond)
+	}
+	if d := clock.nextScheduled(t); d != 100*time.Millisecond {
+		t.Fatalf("Expected a single 100ms window, got %v", d)
+	}
+	if got := events(); len(got) != 0 {
+		t.Fatalf("Expected no notification within the window, got %v", got)
+	}
+
+	clock.Advance(100 * time.Millisecond)
+	got := events()
+	if len(got) != 1 {
+		t.Fatalf("Expected a single consolidated notification, got %v", got)
+	}
+	want := FlagChangeEvent{Timestamp: "2024-01-01T00:00:00Z", AffectedKeys: []string{"a", "b", "c", "d"}}
+	if !reflect.DeepEqual(got[0], want) {
+		t.Errorf("Expected %+v, got %+v", want, got[0])
+	}
+}
+
+func TestWithChangeDebounce_SingleFlagStaysTargeted(t *testing.T) {
+	provider, events := createDebouncedProvider(t, 20*time.Millisecond)
+
+	provider.handleSseFlagChange(FlagChangeEvent{FlagKey: "theme"})
+	provider.handleSseFlagChange(FlagChangeEvent{FlagKey: "theme"})
+
+	time.Sleep(200 * time.Millisecond)
+	got := events()
+	if len(got) != 1 || got[0].FlagKey != "theme" || got[0].AffectedKeys != nil {
+		t.Errorf("Expected one targeted event for theme, got %v", got)
+	}
+}
+
+func TestWithChangeDebounce_BulkChangeCollapsesWindow(t *testing.T) {
+	provider, events := createDebouncedProvider(t, 20*time.Millisecond)
+
+	provider.handleSseFlagChange(FlagChangeEvent{FlagKey: "theme"})
+	provider.handleSseFlagChange(FlagChangeEvent{})
+	provider.handleSseFlagChange(FlagChangeEvent{AffectedKeys: []string{"banner"}})
+
+	time.Sleep(200 * time.Millisecond)
+	got := events()
+	if len(got) != 1 || got[0].FlagKey != "" || got[0].AffectedKeys != nil {
+		t.Errorf("Expected one unscoped bulk event, got %v", got)
+	}
+}
+
+func TestWithChangeDebounce_ShutdownDropsPendingChanges(t *testing.T) {
+	provider, events := createDebouncedProvider(t, 50*time.Millisecond)
+
+	provider.handleSseFlagChange(FlagChangeEvent{FlagKey: "theme"})
+	provider.Shutdown()
+
+	time.Sleep(200 * time.Millisecond)
+	if got := events(); len(got) != 0 {
+		t.Errorf("Expected pending changes to be dropped on shutdown, got %v", got)
+	}
+}
+
+func TestWithChangeDebounce_DisabledByDefault(t *testing.T) {
+	provider, err := NewProvider("test-api-key", WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	received := 0
+	provider.AddFlagChangeListener(func(FlagChangeEvent) { received++ })
+	provider.handleSseFlagChange(FlagChangeEvent{FlagKey: "a"})
+	provider.handleSseFlagChange(FlagChangeEvent{FlagKey: "b"})
+	if received != 2 {
+		t.Errorf("Expected events to be delivered immediately, got %d", received)
+	}
+}
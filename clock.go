@@ -0,0 +1,59 @@
+package flipswitch
+
+import "time"
+
+// clock tells the time and schedules work for the reconnect backoff, polling,
+// retries and debouncing. Tests replace the real clock with a fake one that
+// they advance by hand instead of sleeping.
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	AfterFunc(d time.Duration, f func()) timer
+	NewTicker(d time.Duration) ticker
+}
+
+// timer is the part of *time.Timer the SDK uses.
+type timer interface {
+	Stop() bool
+}
+
+// ticker is the part of *time.Ticker the SDK uses.
+type ticker interface {
+	Chan() <-chan time.Time
+	Stop()
+}
+
+// realClock is the clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (realClock) AfterFunc(d time.Duration, f func()) timer {
+	return time.AfterFunc(d, f)
+}
+
+func (realClock) NewTicker(d time.Duration) ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) Chan() <-chan time.Time { return t.C }
+
+// withClock replaces the provider's clock, including the one of its SSE
+// client. It is a test hook.
+func withClock(c clock) Option {
+	return func(p *FlipswitchProvider) {
+		p.clock = c
+	}
+}
+
+// withSseClock replaces the SSE client's clock.
+func withSseClock(c clock) SseOption {
+	return func(sc *SseClient) {
+		sc.clock = c
+	}
+}
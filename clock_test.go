@@ -0,0 +1,174 @@
+package flipswitch
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock that only moves when Advance is called. Every timer,
+// ticker and After channel it creates reports its duration on scheduled, so
+// tests can wait for the code under test to start waiting instead of
+// sleeping.
+type fakeClock struct {
+	mu        sync.Mutex
+	now       time.Time
+	waiters   []*fakeWaiter
+	scheduled chan time.Duration
+}
+
+// fakeWaiter is a pending After channel, AfterFunc callback or ticker.
+type fakeWaiter struct {
+	at      time.Time
+	period  time.Duration // non-zero for tickers
+	c       chan time.Time
+	f       func()
+	stopped bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		now:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		scheduled: make(chan time.Duration, 100),
+	}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) add(w *fakeWaiter, d time.Duration) *fakeWaiter {
+	c.mu.Lock()
+	w.at = c.now.Add(d)
+	c.waiters = append(c.waiters, w)
+	c.mu.Unlock()
+	c.scheduled <- d
+	return w
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	return c.add(&fakeWaiter{c: make(chan time.Time, 1)}, d).c
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) timer {
+	return &fakeTimer{clock: c, waiter: c.add(&fakeWaiter{f: f}, d)}
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) ticker {
+	return &fakeTicker{fakeTimer{clock: c, waiter: c.add(&fakeWaiter{period: d, c: make(chan time.Time, 1)}, d)}}
+}
+
+// Advance moves the clock forward by d, firing everything that falls due in
+// order. AfterFunc callbacks run on the calling goroutine.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	c.mu.Unlock()
+	for {
+		c.mu.Lock()
+		var next *fakeWaiter
+		for _, w := range c.waiters {
+			if !w.stopped && !w.at.After(end) && (next == nil || w.at.Before(next.at)) {
+				next = w
+			}
+		}
+		if next == nil {
+			c.now = end
+			c.mu.Unlock()
+			return
+		}
+		c.now = next.at
+		if next.period > 0 {
+			next.at = next.at.Add(next.period)
+		} else {
+			next.stopped = true
+		}
+		now := c.now
+		c.mu.Unlock()
+
+		if next.f != nil {
+			next.f()
+			continue
+		}
+		select {
+		case next.c <- now:
+		default: // Like time.Ticker, drop ticks nobody received
+		}
+	}
+}
+
+// nextScheduled returns the duration of the next timer, ticker or After
+// channel created, failing the test if none is created in time.
+func (c *fakeClock) nextScheduled(t *testing.T) time.Duration {
+	t.Helper()
+	select {
+	case d := <-c.scheduled:
+		return d
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the clock to be used")
+		return 0
+	}
+}
+
+// fakeTimer stops a fake AfterFunc callback.
+type fakeTimer struct {
+	clock  *fakeClock
+	waiter *fakeWaiter
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := !t.waiter.stopped
+	t.waiter.stopped = true
+	return active
+}
+
+// fakeTicker is a ticker of a fakeClock.
+type fakeTicker struct {
+	fakeTimer
+}
+
+func (t *fakeTicker) Stop() { t.fakeTimer.Stop() }
+
+func (t *fakeTicker) Chan() <-chan time.Time { return t.waiter.c }
+
+func TestFakeClock_FiresInOrder(t *testing.T) {
+	clock := newFakeClock()
+	var fired []string
+	clock.AfterFunc(30*time.Millisecond, func() { fired = append(fired, "func") })
+	after := clock.After(10 * time.Millisecond)
+	tick := clock.NewTicker(20 * time.Millisecond)
+	stopped := clock.AfterFunc(5*time.Millisecond, func() { fired = append(fired, "stopped") })
+	stopped.Stop()
+
+	clock.Advance(25 * time.Millisecond)
+	select {
+	case <-after:
+	default:
+		t.Error("Expected After to fire")
+	}
+	select {
+	case <-tick.Chan():
+	default:
+		t.Error("Expected the ticker to fire")
+	}
+	if len(fired) != 0 {
+		t.Errorf("Expected no callbacks yet, got %v", fired)
+	}
+
+	clock.Advance(25 * time.Millisecond)
+	if len(fired) != 1 || fired[0] != "func" {
+		t.Errorf("Expected the callback to run once, got %v", fired)
+	}
+	select {
+	case <-tick.Chan():
+	default:
+		t.Error("Expected the ticker to fire again")
+	}
+	if got := clock.Now(); !got.Equal(time.Date(2024, 1, 1, 0, 0, 0, 50*int(time.Millisecond), time.UTC)) {
+		t.Errorf("Expected the clock to have moved 50ms, got %v", got)
+	}
+}
@@ -0,0 +1,53 @@
+package flipswitch
+
+import "fmt"
+
+// connectionIDHeader carries the connection identifier used to correlate an
+// SDK instance's SSE sessions with server-side logs.
+const connectionIDHeader = "X-Flipswitch-Connection-ID"
+
+// WithConnectionID sets the identifier sent as X-Flipswitch-Connection-ID
+// instead of a generated UUID.
+func WithConnectionID(id string) Option {
+	return func(p *FlipswitchProvider) {
+		p.connectionID = id
+	}
+}
+
+// WithConnectionIDOnEvaluations also sends X-Flipswitch-Connection-ID on
+// evaluation requests, not only on the SSE connection.
+func WithConnectionIDOnEvaluations(enabled bool) Option {
+	return func(p *FlipswitchProvider) {
+		p.connectionIDOnEvaluations = enabled
+	}
+}
+
+// WithSseConnectionID sets the identifier the SSE client sends as
+// X-Flipswitch-Connection-ID instead of a generated UUID.
+func WithSseConnectionID(id string) SseOption {
+	return func(c *SseClient) {
+		c.connectionID = id
+	}
+}
+
+// ConnectionID returns the identifier sent as X-Flipswitch-Connection-ID. It
+// is generated once per provider, unless set with WithConnectionID, and stays
+// the same across SSE reconnects, including ReconnectSse.
+func (p *FlipswitchProvider) ConnectionID() string {
+	return p.connectionID
+}
+
+// ConnectionID returns the identifier the client sends as
+// X-Flipswitch-Connection-ID on every connection attempt.
+func (c *SseClient) ConnectionID() string {
+	return c.connectionID
+}
+
+// newUUID returns a random (version 4) UUID drawn from rng.
+func newUUID(rng *lockedRand) string {
+	hi, lo := rng.Uint64(), rng.Uint64()
+	hi = hi&^0xf000 | 0x4000           // version 4
+	lo = lo&^(0xc000<<48) | 0x8000<<48 // RFC 4122 variant
+	return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x",
+		hi>>32, (hi>>16)&0xffff, hi&0xffff, lo>>48, lo&0xffffffffffff)
+}
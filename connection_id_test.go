@@ -0,0 +1,152 @@
+package flipswitch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// connectionIDRecorder collects the X-Flipswitch-Connection-ID header of
+// every request, by path.
+type connectionIDRecorder struct {
+	mu  sync.Mutex
+	ids map[string][]string
+}
+
+func (r *connectionIDRecorder) record(req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ids == nil {
+		r.ids = make(map[string][]string)
+	}
+	r.ids[req.URL.Path] = append(r.ids[req.URL.Path], req.Header.Get(connectionIDHeader))
+}
+
+func (r *connectionIDRecorder) get(path string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.ids[path]...)
+}
+
+// waitForIDs waits until at least n requests to path were recorded.
+func (r *connectionIDRecorder) waitForIDs(t *testing.T, path string, n int) []string {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for {
+		if ids := r.get(path); len(ids) >= n {
+			return ids
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("timed out waiting for %d requests to %s", n, path)
+		default:
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestSseClient_ConnectionIDStableAcrossReconnects(t *testing.T) {
+	recorder := &connectionIDRecorder{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder.record(r)
+		if len(recorder.get(r.URL.Path)) == 1 {
+			// End the first connection so the client reconnects
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		serveSseKeepAlive(w, r)
+	}))
+	defer server.Close()
+
+	client := NewSseClient(server.URL, "test-api-key", nil, nil, nil)
+	client.Connect()
+	defer client.Close()
+
+	ids := recorder.waitForIDs(t, "/api/v1/flags/events", 2)
+	if !uuidPattern.MatchString(ids[0]) {
+		t.Errorf("Expected a UUID connection ID, got %q", ids[0])
+	}
+	if ids[1] != ids[0] {
+		t.Errorf("Expected the same connection ID after reconnecting, got %q then %q", ids[0], ids[1])
+	}
+	if client.ConnectionID() != ids[0] {
+		t.Errorf("Expected ConnectionID() %q to match the header, got %q", ids[0], client.ConnectionID())
+	}
+}
+
+func TestConnectionID_StableAcrossReconnectSse(t *testing.T) {
+	recorder := &connectionIDRecorder{}
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", countingFlag("my-flag", new(int32)))
+	dispatcher.SetSseHandler(serveSseKeepAlive)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder.record(r)
+		dispatcher.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	recorder.waitForIDs(t, "/api/v1/flags/events", 1)
+	provider.ReconnectSse()
+	ids := recorder.waitForIDs(t, "/api/v1/flags/events", 2)
+
+	for i, id := range ids {
+		if id != provider.ConnectionID() {
+			t.Errorf("Connection %d: expected ID %q, got %q", i+1, provider.ConnectionID(), id)
+		}
+	}
+
+	// Evaluation requests do not carry the ID by default
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	if ids := recorder.get("/ofrep/v1/evaluate/flags/my-flag"); len(ids) != 1 || ids[0] != "" {
+		t.Errorf("Expected no connection ID on evaluation requests, got %q", ids)
+	}
+}
+
+func TestConnectionID_OnEvaluations(t *testing.T) {
+	recorder := &connectionIDRecorder{}
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", countingFlag("my-flag", new(int32)))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder.record(r)
+		dispatcher.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithConnectionID("conn-123"),
+		WithConnectionIDOnEvaluations(true),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+
+	for _, path := range []string{"/ofrep/v1/evaluate/flags/my-flag", "/ofrep/v1/evaluate/flags"} {
+		if ids := recorder.get(path); len(ids) != 1 || ids[0] != "conn-123" {
+			t.Errorf("%s: expected connection ID conn-123, got %q", path, ids)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package flipswitch
+
+import "github.com/open-feature/go-sdk/openfeature"
+
+// contextFilter restricts which evaluation context attributes are sent to
+// the server. A nil filter lets everything through.
+type contextFilter struct {
+	allow map[string]bool // nil means every attribute is allowed
+	deny  map[string]bool
+}
+
+// WithContextAllowlist sends only the listed evaluation context attributes to
+// the server; all others stay in the process. The targeting key is always
+// sent. May be combined with WithContextDenylist, which then removes
+// attributes from the allowed set.
+func WithContextAllowlist(attributes []string) Option {
+	return func(p *FlipswitchProvider) {
+		p.ensureContextFilter().allow = attributeSet(attributes)
+	}
+}
+
+// WithContextDenylist never sends the listed evaluation context attributes,
+// such as "email" or "name", to the server. The targeting key is always sent,
+// even if listed.
+func WithContextDenylist(attributes []string) Option {
+	return func(p *FlipswitchProvider) {
+		p.ensureContextFilter().deny = attributeSet(attributes)
+	}
+}
+
+func (p *FlipswitchProvider) ensureContextFilter() *contextFilter {
+	if p.contextFilter == nil {
+		p.contextFilter = &contextFilter{}
+	}
+	return p.contextFilter
+}
+
+func attributeSet(attributes []string) map[string]bool {
+	set := make(map[string]bool, len(attributes))
+	for _, attribute := range attributes {
+		set[attribute] = true
+	}
+	return set
+}
+
+// apply returns the attributes of evalCtx that may be sent to the server.
+func (f *contextFilter) apply(evalCtx openfeature.FlattenedContext) openfeature.FlattenedContext {
+	if f == nil {
+		return evalCtx
+	}
+	filtered := make(openfeature.FlattenedContext, len(evalCtx))
+	for key, value := range evalCtx {
+		if key == openfeature.TargetingKey || f.permits(key) {
+			filtered[key] = value
+		}
+	}
+	return filtered
+}
+
+func (f *contextFilter) permits(key string) bool {
+	if f.allow != nil && !f.allow[key] {
+		return false
+	}
+	return !f.deny[key]
+}
+
+// sendableContext returns evalCtx as it may be sent to the server: with the
+// attributes the provider may not send removed and the targeting key hashed
+// if configured.
+func (p *FlipswitchProvider) sendableContext(evalCtx openfeature.FlattenedContext) openfeature.FlattenedContext {
+	return p.hashTargetingKey(p.contextFilter.apply(evalCtx))
+}
+
+// WithContextMapper replaces the evaluation context sent to the server with
+// the result of mapper, for example to rename "email" to "user.email" or to
+// strip attributes the server must not see. mapper receives the context after
+// WithContextAllowlist, WithContextDenylist and targeting key hashing have
+// been applied, and is used for every evaluation request. It must not modify
+// its argument. A panic in it is recovered and logged, and only the targeting
+// key is sent.
+func WithContextMapper(mapper func(openfeature.FlattenedContext) map[string]interface{}) Option {
+	return func(p *FlipswitchProvider) {
+		p.contextMapper = mapper
+	}
+}
+
+// outgoingContext returns the request body context for evalCtx.
+func (p *FlipswitchProvider) outgoingContext(evalCtx openfeature.FlattenedContext) map[string]interface{} {
+	sendable := p.sendableContext(evalCtx)
+	if p.contextMapper == nil {
+		return transformContext(sendable)
+	}
+	return p.mapContext(sendable)
+}
+
+// ofrepContext is outgoingContext for evaluations delegated to the OFREP
+// provider.
+func (p *FlipswitchProvider) ofrepContext(evalCtx openfeature.FlattenedContext) openfeature.FlattenedContext {
+	if p.contextMapper == nil {
+		return p.sendableContext(evalCtx)
+	}
+	return p.mapContext(p.sendableContext(evalCtx))
+}
+
+func (p *FlipswitchProvider) mapContext(evalCtx openfeature.FlattenedContext) (result map[string]interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Errorw("Error in context mapper", "panic", r)
+			result = map[string]interface{}{}
+			if targetingKey, ok := evalCtx[openfeature.TargetingKey]; ok {
+				result[openfeature.TargetingKey] = targetingKey
+			}
+		}
+	}()
+	return p.contextMapper(evalCtx)
+}
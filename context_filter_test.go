@@ -0,0 +1,197 @@
+package flipswitch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// contextRecordingServer records the "context" object of every evaluation
+// request body it serves.
+func contextRecordingServer() (*httptest.Server, func() []map[string]interface{}) {
+	var mu sync.Mutex
+	var sent []map[string]interface{}
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", countingFlag("my-flag", new(int32)))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var parsed struct {
+			Context map[string]interface{} `json:"context"`
+		}
+		json.Unmarshal(body, &parsed)
+		mu.Lock()
+		sent = append(sent, parsed.Context)
+		mu.Unlock()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		dispatcher.ServeHTTP(w, r)
+	}))
+	return server, func() []map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]map[string]interface{}(nil), sent...)
+	}
+}
+
+var piiContext = openfeature.FlattenedContext{
+	"targetingKey": "user-1",
+	"email":        "user@example.com",
+	"name":         "Jane",
+	"plan":         "pro",
+	"country":      "SE",
+}
+
+func evaluateEveryPath(provider *FlipswitchProvider) {
+	provider.EvaluateFlag("my-flag", piiContext)
+	provider.EvaluateAllFlags(piiContext)
+	provider.BooleanEvaluation(context.Background(), "my-flag", false, piiContext)
+}
+
+func TestContextDenylist_StripsDeniedAttributes(t *testing.T) {
+	server, sent := contextRecordingServer()
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithContextDenylist([]string{"email", "name", "targetingKey"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	evaluateEveryPath(provider)
+
+	want := map[string]interface{}{"targetingKey": "user-1", "plan": "pro", "country": "SE"}
+	bodies := sent()
+	if len(bodies) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(bodies))
+	}
+	for i, got := range bodies {
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Request %d: expected context %v, got %v", i+1, want, got)
+		}
+	}
+}
+
+func TestContextAllowlist_SendsOnlyAllowedAttributes(t *testing.T) {
+	server, sent := contextRecordingServer()
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithContextAllowlist([]string{"plan", "country"}),
+		WithContextDenylist([]string{"country"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	evaluateEveryPath(provider)
+
+	want := map[string]interface{}{"targetingKey": "user-1", "plan": "pro"}
+	for i, got := range sent() {
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Request %d: expected context %v, got %v", i+1, want, got)
+		}
+	}
+}
+
+func TestContextFilter_NoneByDefault(t *testing.T) {
+	server, sent := contextRecordingServer()
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("my-flag", piiContext)
+
+	bodies := sent()
+	if len(bodies) != 1 || !reflect.DeepEqual(bodies[0], map[string]interface{}(piiContext)) {
+		t.Errorf("Expected the full context to be sent, got %v", bodies)
+	}
+}
+
+func TestContextMapper_ReplacesSentContext(t *testing.T) {
+	server, sent := contextRecordingServer()
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithContextDenylist([]string{"name"}),
+		WithContextMapper(func(evalCtx openfeature.FlattenedContext) map[string]interface{} {
+			if _, ok := evalCtx["name"]; ok {
+				t.Error("Expected the mapper to receive the filtered context")
+			}
+			return map[string]interface{}{
+				"targetingKey": evalCtx["targetingKey"],
+				"user.email":   evalCtx["email"],
+				"plan":         evalCtx["plan"],
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	evaluateEveryPath(provider)
+
+	want := map[string]interface{}{"targetingKey": "user-1", "user.email": "user@example.com", "plan": "pro"}
+	bodies := sent()
+	if len(bodies) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(bodies))
+	}
+	for i, got := range bodies {
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Request %d: expected context %v, got %v", i+1, want, got)
+		}
+	}
+}
+
+func TestContextMapper_PanicSendsOnlyTargetingKey(t *testing.T) {
+	server, sent := contextRecordingServer()
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithLogger(logger),
+		WithContextMapper(func(openfeature.FlattenedContext) map[string]interface{} {
+			panic("boom")
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("my-flag", piiContext)
+
+	bodies := sent()
+	if len(bodies) != 1 || !reflect.DeepEqual(bodies[0], map[string]interface{}{"targetingKey": "user-1"}) {
+		t.Errorf("Expected only the targeting key to be sent, got %v", bodies)
+	}
+	if _, ok := logger.find("Error in context mapper"); !ok {
+		t.Error("Expected the panic to be logged")
+	}
+}
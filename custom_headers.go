@@ -0,0 +1,64 @@
+package flipswitch
+
+import (
+	"net/http"
+	"strings"
+)
+
+// reservedHeaders are set by the SDK itself and cannot be replaced with
+// WithHeaders. Headers starting with "X-Flipswitch-" are reserved as well.
+var reservedHeaders = map[string]bool{
+	"X-Api-Key":     true,
+	"Content-Type":  true,
+	"Accept":        true,
+	"Cache-Control": true,
+	"Last-Event-Id": true,
+}
+
+// WithHeaders adds static headers to every request the provider makes: flag
+// evaluations, the API key check made by Init and the SSE connection. Use it
+// for proxy credentials or request tagging. Headers the SDK sets itself, such
+// as X-API-Key and the X-Flipswitch-* headers, cannot be overridden and are
+// ignored with a warning. May be given several times; later values win.
+func WithHeaders(headers map[string]string) Option {
+	return func(p *FlipswitchProvider) {
+		if p.customHeaders == nil {
+			p.customHeaders = http.Header{}
+		}
+		for key, value := range headers {
+			p.customHeaders.Set(key, value)
+		}
+	}
+}
+
+// isReservedHeader reports whether the SDK sets key itself.
+func isReservedHeader(key string) bool {
+	key = http.CanonicalHeaderKey(key)
+	return reservedHeaders[key] || strings.HasPrefix(key, "X-Flipswitch-")
+}
+
+// dropReservedHeaders removes the headers WithHeaders may not set, logging
+// each one. Called once in NewProvider.
+func (p *FlipswitchProvider) dropReservedHeaders() {
+	for key := range p.customHeaders {
+		if isReservedHeader(key) {
+			p.logger.Warnw("Ignoring reserved custom header", "header", key)
+			delete(p.customHeaders, key)
+		}
+	}
+}
+
+// setCustomHeaders adds the headers given with WithHeaders to req. The value
+// slices are shared between requests and must not be modified.
+func (p *FlipswitchProvider) setCustomHeaders(req *http.Request) {
+	for key, values := range p.customHeaders {
+		req.Header[key] = values
+	}
+}
+
+// withSseHeaders sets extra headers sent on every connection attempt.
+func withSseHeaders(headers http.Header) SseOption {
+	return func(c *SseClient) {
+		c.headers = headers
+	}
+}
@@ -0,0 +1,114 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestWithHeaders_SentOnEveryRequestType(t *testing.T) {
+	connected := make(chan struct{}, 1)
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "my-flag", "value": true}
+	})
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"flags": []interface{}{}}
+	})
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case connected <- struct{}{}:
+		default:
+		}
+		serveSseKeepAlive(w, r)
+	})
+
+	var mu sync.Mutex
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r)
+		mu.Unlock()
+		dispatcher.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	// checkNew asserts that the requests made since the last check carry the
+	// custom headers and the provider's own API key
+	seen := 0
+	checkNew := func(step string) {
+		t.Helper()
+		mu.Lock()
+		defer mu.Unlock()
+		if len(requests) == seen {
+			t.Fatalf("%s: expected a request", step)
+		}
+		for _, r := range requests[seen:] {
+			if got := r.Header.Get("X-Proxy-Token"); got != "secret" {
+				t.Errorf("%s: expected X-Proxy-Token on %s, got %q", step, r.URL.Path, got)
+			}
+			if got := r.Header.Get("X-Tenant-ID"); got != "acme" {
+				t.Errorf("%s: expected X-Tenant-ID on %s, got %q", step, r.URL.Path, got)
+			}
+			if got := r.Header.Get("X-API-Key"); got != "test-api-key" {
+				t.Errorf("%s: expected the API key not to be overridden on %s, got %q", step, r.URL.Path, got)
+			}
+		}
+		seen = len(requests)
+	}
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithHeaders(map[string]string{"X-Proxy-Token": "secret", "X-API-Key": "override"}),
+		WithHeaders(map[string]string{"X-Tenant-ID": "acme"}),
+		WithLogger(&recordingLogger{}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for SSE connection")
+	}
+	checkNew("validateAPIKey and SSE")
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	checkNew("EvaluateFlag")
+
+	provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+	checkNew("EvaluateAllFlags")
+
+	provider.BooleanEvaluation(context.Background(), "my-flag", false, openfeature.FlattenedContext{})
+	checkNew("BooleanEvaluation")
+}
+
+func TestWithHeaders_WarnsAboutReservedHeaders(t *testing.T) {
+	logger := &recordingLogger{}
+	provider, err := NewProvider("test-api-key",
+		WithRealtime(false),
+		WithLogger(logger),
+		WithHeaders(map[string]string{"x-api-key": "override", "X-Flipswitch-SDK": "spoofed", "X-Proxy-Token": "secret"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if _, ok := logger.find("Ignoring reserved custom header"); !ok {
+		t.Error("Expected a warning for the reserved headers")
+	}
+	if len(provider.customHeaders) != 1 || provider.customHeaders.Get("X-Proxy-Token") != "secret" {
+		t.Errorf("Expected only X-Proxy-Token to be kept, got %v", provider.customHeaders)
+	}
+}
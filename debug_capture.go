@@ -0,0 +1,50 @@
+package flipswitch
+
+import "sync"
+
+// requestCapture holds the body of the most recent evaluation request. A nil
+// capture records nothing.
+type requestCapture struct {
+	mu   sync.Mutex
+	body []byte
+}
+
+// WithDebugCapture keeps a copy of the most recent direct evaluation request
+// body (single flag or bulk) for inspection with LastRequestBody. Disabled by
+// default to avoid the copy in production.
+func WithDebugCapture(enabled bool) Option {
+	return func(p *FlipswitchProvider) {
+		if enabled {
+			p.capture = &requestCapture{}
+		} else {
+			p.capture = nil
+		}
+	}
+}
+
+// record stores a copy of body.
+func (c *requestCapture) record(body []byte) {
+	if c == nil {
+		return
+	}
+	copied := append([]byte(nil), body...)
+	c.mu.Lock()
+	c.body = copied
+	c.mu.Unlock()
+}
+
+// LastRequestBody returns a copy of the JSON body of the most recent
+// EvaluateFlag or EvaluateAllFlags request. It returns nil unless
+// WithDebugCapture is enabled, or if no request has been made yet. The body
+// holds only the transformed evaluation context.
+func (p *FlipswitchProvider) LastRequestBody() []byte {
+	if p.capture == nil {
+		return nil
+	}
+	p.capture.mu.Lock()
+	defer p.capture.mu.Unlock()
+	if p.capture.body == nil {
+		return nil
+	}
+	return append([]byte(nil), p.capture.body...)
+}
@@ -0,0 +1,71 @@
+package flipswitch
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestDebugCapture_RecordsTransformedContext(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", countingFlag("my-flag", new(int32)))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithDebugCapture(true),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if body := provider.LastRequestBody(); body != nil {
+		t.Errorf("Expected nil before any request, got %s", body)
+	}
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1", "plan": "pro"}
+	provider.EvaluateFlag("my-flag", evalCtx)
+
+	var sent struct {
+		Context map[string]interface{} `json:"context"`
+	}
+	if err := json.Unmarshal(provider.LastRequestBody(), &sent); err != nil {
+		t.Fatalf("Failed to decode captured body: %v", err)
+	}
+	if want := transformContext(evalCtx); !reflect.DeepEqual(sent.Context, want) {
+		t.Errorf("Expected captured context %v, got %v", want, sent.Context)
+	}
+
+	provider.EvaluateAllFlags(openfeature.FlattenedContext{"targetingKey": "user-2"})
+	if err := json.Unmarshal(provider.LastRequestBody(), &sent); err != nil {
+		t.Fatalf("Failed to decode captured body: %v", err)
+	}
+	if sent.Context["targetingKey"] != "user-2" {
+		t.Errorf("Expected the bulk request to be captured, got %v", sent.Context)
+	}
+}
+
+func TestDebugCapture_DisabledByDefault(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", countingFlag("my-flag", new(int32)))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{"targetingKey": "user-1"})
+	if body := provider.LastRequestBody(); body != nil {
+		t.Errorf("Expected nil without WithDebugCapture, got %s", body)
+	}
+}
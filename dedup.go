@@ -0,0 +1,104 @@
+package flipswitch
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"strconv"
+	"sync"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// flightCall is an in-flight or completed evaluation shared by callers.
+type flightCall struct {
+	done   chan struct{}
+	eval   *FlagEvaluation
+	err    error
+	cancel context.CancelFunc
+
+	// Callers still waiting for the result, guarded by flightGroup.mu
+	callers int
+}
+
+// flightGroup collapses concurrent calls with the same key into a single
+// execution whose result is shared by every caller.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// do executes fn once for all concurrent callers using the same key. fn runs
+// on a context that carries the values of the first caller's ctx but not its
+// cancellation, so one caller giving up does not fail the others; a caller
+// whose ctx ends returns its error at once, and fn's context is cancelled
+// only when every caller has gone.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (*FlagEvaluation, error)) (*FlagEvaluation, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	call, ok := g.calls[key]
+	if !ok {
+		fnCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &flightCall{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = call
+		go func() {
+			defer close(call.done)
+			defer cancel()
+			call.eval, call.err = fn(fnCtx)
+			g.forget(key, call)
+		}()
+	}
+	call.callers++
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.eval, call.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.callers--
+		abandoned := call.callers == 0
+		g.mu.Unlock()
+		if abandoned {
+			// Later callers start a new execution instead of joining this one
+			g.forget(key, call)
+			call.cancel()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// forget removes call from the group unless a newer call replaced it.
+func (g *flightGroup) forget(key string, call *flightCall) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
+
+// evaluationKey identifies an evaluation by flag key and a hash of the
+// context sent to the server. It fails if the context cannot be encoded, in
+// which case the evaluation has no key to be shared under.
+func evaluationKey(flagKey string, evalCtx openfeature.FlattenedContext) (string, error) {
+	data, err := json.Marshal(transformContext(evalCtx))
+	if err != nil {
+		return "", err
+	}
+	return flagKey + "\x00" + hashData(data), nil
+}
+
+// contextHash returns a stable hash of the transformed evaluation context.
+// encoding/json sorts map keys, so equal contexts hash identically.
+func contextHash(evalCtx openfeature.FlattenedContext) string {
+	data, _ := json.Marshal(transformContext(evalCtx))
+	return hashData(data)
+}
+
+func hashData(data []byte) string {
+	h := fnv.New64a()
+	h.Write(data)
+	return strconv.FormatUint(h.Sum64(), 16)
+}
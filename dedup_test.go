@@ -0,0 +1,221 @@
+package flipswitch
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestEvaluateFlag_DeduplicatesConcurrentIdenticalCalls(t *testing.T) {
+	var requests int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("dark-mode", func() (int, map[string]interface{}) {
+		atomic.AddInt32(&requests, 1)
+		// Hold the response so every caller joins the in-flight request.
+		time.Sleep(200 * time.Millisecond)
+		return 200, map[string]interface{}{"key": "dark-mode", "value": true, "reason": "DEFAULT"}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	const callers = 50
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1", "plan": "pro"}
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	results := make([]*FlagEvaluation, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			results[i] = provider.EvaluateFlag("dark-mode", evalCtx)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected 1 HTTP request, got %d", got)
+	}
+	for i, result := range results {
+		if result == nil || result.Value != true {
+			t.Fatalf("Caller %d got unexpected result %+v", i, result)
+		}
+	}
+	if results[0] == results[1] {
+		t.Error("Expected each caller to receive its own copy of the result")
+	}
+}
+
+func TestEvaluateFlag_CancelledCallerDoesNotFailOthers(t *testing.T) {
+	var requests int32
+	received, release := make(chan struct{}, 1), make(chan struct{})
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("dark-mode", func() (int, map[string]interface{}) {
+		atomic.AddInt32(&requests, 1)
+		received <- struct{}{}
+		<-release
+		return 200, map[string]interface{}{"key": "dark-mode", "value": true, "reason": "DEFAULT"}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := provider.evaluateFlag(ctx, "dark-mode", evalCtx)
+		leaderErr <- err
+	}()
+	<-received
+
+	waiter := make(chan *FlagEvaluation, 1)
+	go func() { waiter <- provider.EvaluateFlag("dark-mode", evalCtx) }()
+	key, err := evaluationKey("dark-mode", evalCtx)
+	if err != nil {
+		t.Fatalf("Failed to build the evaluation key: %v", err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		provider.flights.mu.Lock()
+		joined := provider.flights.calls[key] != nil && provider.flights.calls[key].callers == 2
+		provider.flights.mu.Unlock()
+		if joined {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the second caller to join")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case err := <-leaderErr:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the cancelled caller to get context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the cancelled caller to return at once")
+	}
+
+	close(release)
+	select {
+	case result := <-waiter:
+		if result == nil || result.Value != true {
+			t.Errorf("Expected the other caller to get the value, got %+v", result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the other caller")
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected 1 HTTP request, got %d", got)
+	}
+}
+
+func TestEvaluateFlag_DoesNotDeduplicateDifferentContexts(t *testing.T) {
+	var requests int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("dark-mode", func() (int, map[string]interface{}) {
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(100 * time.Millisecond)
+		return 200, map[string]interface{}{"key": "dark-mode", "value": true}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	var wg sync.WaitGroup
+	for _, user := range []string{"user-1", "user-2"} {
+		wg.Add(1)
+		go func(user string) {
+			defer wg.Done()
+			provider.EvaluateFlag("dark-mode", openfeature.FlattenedContext{"targetingKey": user})
+		}(user)
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("Expected 2 HTTP requests for different contexts, got %d", got)
+	}
+}
+
+func TestEvaluationKey_StableForEqualContexts(t *testing.T) {
+	key := func(flagKey string, evalCtx openfeature.FlattenedContext) string {
+		t.Helper()
+		k, err := evaluationKey(flagKey, evalCtx)
+		if err != nil {
+			t.Fatalf("Failed to build the evaluation key: %v", err)
+		}
+		return k
+	}
+	a := key("flag", openfeature.FlattenedContext{"targetingKey": "u", "a": 1, "b": "x"})
+	b := key("flag", openfeature.FlattenedContext{"b": "x", "a": 1, "targetingKey": "u"})
+	if a != b {
+		t.Errorf("Expected equal keys for equal contexts, got %q and %q", a, b)
+	}
+	if a == key("other", openfeature.FlattenedContext{"targetingKey": "u", "a": 1, "b": "x"}) {
+		t.Error("Expected different keys for different flags")
+	}
+}
+
+func TestEvaluateFlag_DoesNotDeduplicateUnencodableContexts(t *testing.T) {
+	var requests int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("dark-mode", func() (int, map[string]interface{}) {
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(100 * time.Millisecond)
+		return 200, map[string]interface{}{"key": "dark-mode", "value": true}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	// encoding/json rejects NaN, so these contexts cannot be told apart
+	if _, err := evaluationKey("dark-mode", openfeature.FlattenedContext{"score": math.NaN()}); err == nil {
+		t.Fatal("Expected an error for a context that cannot be encoded")
+	}
+
+	var wg sync.WaitGroup
+	for _, user := range []string{"user-1", "user-2"} {
+		wg.Add(1)
+		go func(user string) {
+			defer wg.Done()
+			provider.EvaluateFlag("dark-mode", openfeature.FlattenedContext{"targetingKey": user, "score": math.NaN()})
+		}(user)
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("Expected 2 HTTP requests for contexts that cannot be encoded, got %d", got)
+	}
+}
@@ -0,0 +1,141 @@
+package flipswitch
+
+import (
+	"encoding/json"
+	"runtime"
+	"time"
+)
+
+// diagnostics is the document produced by DiagnosticsBundle.
+type diagnostics struct {
+	GeneratedAt  time.Time         `json:"generatedAt"`
+	SDK          diagnosticsSDK    `json:"sdk"`
+	Config       diagnosticsConfig `json:"config"`
+	Status       diagnosticsStatus `json:"status"`
+	SSE          diagnosticsSSE    `json:"sse"`
+	Cache        diagnosticsCache  `json:"cache"`
+	RecentErrors []ErrorRecord     `json:"recentErrors"`
+}
+
+type diagnosticsSDK struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"goVersion"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+type diagnosticsConfig struct {
+	BaseURL              string `json:"baseUrl"`
+	Region               string `json:"region,omitempty"`
+	APIKey               string `json:"apiKey"`
+	Realtime             bool   `json:"realtime"`
+	PollingFallback      bool   `json:"pollingFallback"`
+	PollingInterval      string `json:"pollingInterval"`
+	MaxSseRetries        int    `json:"maxSseRetries"`
+	ReadyAfterFirstSync  bool   `json:"readyAfterFirstSync"`
+	RequestTimeout       string `json:"requestTimeout"`
+	PerAttemptTimeout    string `json:"perAttemptTimeout"`
+	RetryMaxAttempts     int    `json:"retryMaxAttempts"`
+	MaxConcurrent        int    `json:"maxConcurrentEvaluations"`
+	ServeStaleOnError    bool   `json:"serveStaleOnError"`
+	Bootstrapped         bool   `json:"bootstrapped"`
+	TelemetryDisabled    bool   `json:"telemetryDisabled"`
+	ResponseVerification bool   `json:"responseVerification"`
+}
+
+type diagnosticsStatus struct {
+	Provider      string `json:"provider"`
+	Initialized   bool   `json:"initialized"`
+	PollingActive bool   `json:"pollingActive"`
+}
+
+type diagnosticsSSE struct {
+	Status       ConnectionStatus `json:"status"`
+	ConnectionID string           `json:"connectionId"`
+	RetryCount   int              `json:"retryCount"`
+	Reconnects   int              `json:"reconnects"`
+}
+
+type diagnosticsCache struct {
+	Enabled    bool    `json:"enabled"`
+	TTL        string  `json:"ttl,omitempty"`
+	MaxEntries int     `json:"maxEntries,omitempty"`
+	Entries    int     `json:"entries"`
+	Hits       uint64  `json:"hits"`
+	Misses     uint64  `json:"misses"`
+	HitRatio   float64 `json:"hitRatio"`
+}
+
+// DiagnosticsBundle returns a JSON document describing the provider's
+// configuration, state and recent connection errors, meant to be attached
+// to a support ticket. The API key is redacted to its last four characters.
+// It is safe to call at any time, including before Init and after Shutdown.
+func (p *FlipswitchProvider) DiagnosticsBundle() ([]byte, error) {
+	p.mu.RLock()
+	status := diagnosticsStatus{
+		Provider:      string(p.status),
+		Initialized:   p.initialized,
+		PollingActive: p.pollingActive,
+	}
+	sse := diagnosticsSSE{
+		ConnectionID: p.connectionID,
+		RetryCount:   p.sseRetryCount,
+		Reconnects:   p.sseReconnects,
+	}
+	p.mu.RUnlock()
+	sse.Status = p.GetSseStatus()
+
+	cache := diagnosticsCache{Enabled: p.cache != nil}
+	if p.cache != nil {
+		cache.TTL = p.cache.ttl.String()
+		cache.MaxEntries = p.cache.maxEntries
+		cache.Entries = p.cache.len()
+		cache.Hits, cache.Misses, cache.HitRatio = p.CacheStats()
+	}
+
+	recentErrors := p.RecentErrors()
+	if recentErrors == nil {
+		recentErrors = []ErrorRecord{}
+	}
+
+	return json.MarshalIndent(diagnostics{
+		GeneratedAt: time.Now().UTC(),
+		SDK: diagnosticsSDK{
+			Version:   sdkVersion,
+			GoVersion: runtime.Version(),
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+		},
+		Config: diagnosticsConfig{
+			BaseURL:              p.baseURL,
+			Region:               p.region,
+			APIKey:               redactAPIKey(p.apiKey),
+			Realtime:             p.enableRealtime,
+			PollingFallback:      p.enablePollingFallback,
+			PollingInterval:      p.pollingInterval.String(),
+			MaxSseRetries:        p.maxSseRetries,
+			ReadyAfterFirstSync:  p.readyAfterFirstSync,
+			RequestTimeout:       p.requestTimeout.String(),
+			PerAttemptTimeout:    p.perAttemptTimeout.String(),
+			RetryMaxAttempts:     p.retryMaxAttempts,
+			MaxConcurrent:        cap(p.limiter),
+			ServeStaleOnError:    p.lastKnown != nil,
+			Bootstrapped:         p.bootstrap.hasFlags(),
+			TelemetryDisabled:    p.telemetryDisabled,
+			ResponseVerification: p.verificationKey != nil,
+		},
+		Status:       status,
+		SSE:          sse,
+		Cache:        cache,
+		RecentErrors: recentErrors,
+	}, "", "  ")
+}
+
+// redactAPIKey keeps only the last four characters of key, and none of a key
+// too short for that to hide most of it.
+func redactAPIKey(key string) string {
+	if len(key) < 12 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
@@ -0,0 +1,137 @@
+package flipswitch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const diagnosticsTestKey = "fs_live_0123456789abcdef"
+
+func TestDiagnosticsBundle_ContainsSectionsWithoutAPIKey(t *testing.T) {
+	failures := make(chan struct{}, 10)
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", countingFlag("my-flag", new(int32)))
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		failures <- struct{}{}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		diagnosticsTestKey,
+		WithBaseURL(server.URL),
+		WithCache(time.Minute),
+		WithPollingFallback(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+
+	select {
+	case <-failures:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the SSE connection attempt")
+	}
+	var recentErrors []interface{}
+	var bundle []byte
+	var parsed map[string]interface{}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(recentErrors) == 0 && time.Now().Before(deadline) {
+		bundle, err = provider.DiagnosticsBundle()
+		if err != nil {
+			t.Fatalf("DiagnosticsBundle failed: %v", err)
+		}
+		if err := json.Unmarshal(bundle, &parsed); err != nil {
+			t.Fatalf("Expected valid JSON, got %v:\n%s", err, bundle)
+		}
+		recentErrors, _ = parsed["recentErrors"].([]interface{})
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, section := range []string{"generatedAt", "sdk", "config", "status", "sse", "cache", "recentErrors"} {
+		if _, ok := parsed[section]; !ok {
+			t.Errorf("Expected a %q section", section)
+		}
+	}
+	if strings.Contains(string(bundle), diagnosticsTestKey) {
+		t.Error("Expected the raw API key never to appear in the bundle")
+	}
+
+	config := parsed["config"].(map[string]interface{})
+	if config["apiKey"] != "****cdef" {
+		t.Errorf("Expected the redacted API key, got %v", config["apiKey"])
+	}
+	cache := parsed["cache"].(map[string]interface{})
+	if cache["hits"] != float64(1) || cache["misses"] != float64(1) {
+		t.Errorf("Expected cache stats, got %v", cache)
+	}
+	// The failing SSE connection makes the provider stale
+	if status := parsed["status"].(map[string]interface{}); status["provider"] != string(openfeature.StaleState) {
+		t.Errorf("Expected provider status STALE, got %v", status["provider"])
+	}
+	if len(recentErrors) == 0 {
+		t.Fatal("Expected the SSE connection error to be recorded")
+	}
+	if entry := recentErrors[0].(map[string]interface{}); entry["statusCode"] != float64(503) {
+		t.Errorf("Expected status code 503 in the error entry, got %v", entry)
+	}
+}
+
+func TestDiagnosticsBundle_SafeBeforeInitAndAfterShutdown(t *testing.T) {
+	provider, err := NewProvider(diagnosticsTestKey, WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	for _, stage := range []string{"before init", "after shutdown"} {
+		bundle, err := provider.DiagnosticsBundle()
+		if err != nil || !json.Valid(bundle) {
+			t.Errorf("Expected a valid bundle %s, got %v", stage, err)
+		}
+		provider.Shutdown()
+	}
+}
+
+func TestErrorRing_KeepsMostRecentInOrder(t *testing.T) {
+	ring := newErrorRing(3)
+	for i := 0; i < 5; i++ {
+		ring.add(OperationEvaluation, "my-flag", &HTTPStatusError{StatusCode: 500 + i})
+	}
+
+	entries := ring.list()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		if want := 502 + i; entry.StatusCode != want {
+			t.Errorf("Entry %d: expected status %d, got %d", i, want, entry.StatusCode)
+		}
+	}
+}
+
+func TestRedactAPIKey(t *testing.T) {
+	tests := map[string]string{
+		diagnosticsTestKey: "****cdef",
+		"short":            "****",
+		"":                 "****",
+	}
+	for key, want := range tests {
+		if got := redactAPIKey(key); got != want {
+			t.Errorf("redactAPIKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package flipswitch
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// defaultEnvOverridePrefix is used by WithEnvOverrides when no prefix is given.
+const defaultEnvOverridePrefix = "FLIPSWITCH_FLAG_"
+
+// envOverrides holds flag values read from environment variables. A nil
+// store overrides nothing.
+type envOverrides struct {
+	prefix string
+	values map[string]interface{}
+	mu     sync.RWMutex
+}
+
+// WithEnvOverrides overrides flags with environment variables named prefix
+// followed by the flag key, such as FLIPSWITCH_FLAG_dark_mode=true. The
+// variables are read by Init. Since variable names cannot contain hyphens,
+// an underscore in the name also matches a hyphen in the flag key, so the
+// example overrides both "dark_mode" and "dark-mode". Values are parsed as
+// JSON, so true, 42, 2.5, "text" and {"a":1} give a boolean, number, string
+// and object; anything that is not valid JSON is used as a plain string.
+//
+// Overridden flags resolve locally with reason STATIC and no request is made
+// for them. This is meant for local development; an empty prefix uses
+// FLIPSWITCH_FLAG_.
+func WithEnvOverrides(prefix string) Option {
+	return func(p *FlipswitchProvider) {
+		if prefix == "" {
+			prefix = defaultEnvOverridePrefix
+		}
+		p.envOverrides = &envOverrides{prefix: prefix}
+	}
+}
+
+// load reads the overrides from the environment, replacing any read before.
+func (o *envOverrides) load() {
+	if o == nil {
+		return
+	}
+	values := make(map[string]interface{})
+	for _, kv := range os.Environ() {
+		name, raw, _ := strings.Cut(kv, "=")
+		key, ok := strings.CutPrefix(name, o.prefix)
+		if !ok || key == "" {
+			continue
+		}
+		values[key] = parseEnvValue(raw)
+	}
+
+	o.mu.Lock()
+	o.values = values
+	o.mu.Unlock()
+}
+
+// parseEnvValue decodes raw as JSON, falling back to the raw string.
+func parseEnvValue(raw string) interface{} {
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil || value == nil {
+		return raw
+	}
+	return value
+}
+
+// get returns the overridden evaluation of flagKey, if there is one.
+func (o *envOverrides) get(flagKey string) (FlagEvaluation, bool) {
+	if o == nil {
+		return FlagEvaluation{}, false
+	}
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	value, ok := o.values[flagKey]
+	if !ok {
+		value, ok = o.values[strings.ReplaceAll(flagKey, "-", "_")]
+	}
+	if !ok {
+		return FlagEvaluation{}, false
+	}
+	return FlagEvaluation{
+		Key:       flagKey,
+		Value:     value,
+		ValueType: inferType(value),
+		Reason:    string(openfeature.StaticReason),
+	}, true
+}
+
+// apply replaces the server's evaluations of overridden flags in a bulk
+// result. Overrides only match keys the server returned, since the original
+// flag key of an underscored variable name is ambiguous.
+func (o *envOverrides) apply(flags []FlagEvaluation) []FlagEvaluation {
+	if o == nil {
+		return flags
+	}
+	for i, flag := range flags {
+		if eval, ok := o.get(flag.Key); ok {
+			flags[i] = eval
+		}
+	}
+	return flags
+}
+
+// envOverride returns a copy of the overridden evaluation of flagKey, or nil
+// if it is not overridden.
+func (p *FlipswitchProvider) envOverride(flagKey string) *FlagEvaluation {
+	eval, ok := p.envOverrides.get(flagKey)
+	if !ok {
+		return nil
+	}
+	return &eval
+}
+
+// overrideResolutionDetail is the resolution detail of an overridden flag.
+var overrideResolutionDetail = openfeature.ProviderResolutionDetail{Reason: openfeature.StaticReason}
@@ -0,0 +1,150 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// createEnvOverrideProvider sets overrides with the FSTEST_ prefix and returns
+// an initialized provider whose server serves different values for every
+// overridden flag. Single flag requests are counted in calls.
+func createEnvOverrideProvider(t *testing.T) (provider *FlipswitchProvider, calls *int32, cleanup func()) {
+	t.Helper()
+	t.Setenv("FSTEST_dark_mode", "true")
+	t.Setenv("FSTEST_limit", "42")
+	t.Setenv("FSTEST_ratio", "0.25")
+	t.Setenv("FSTEST_color", "blue")
+	t.Setenv("FSTEST_tuning", `{"batchSize":5}`)
+
+	calls = new(int32)
+	server := map[string]interface{}{
+		"dark-mode": false,
+		"limit":     7,
+		"ratio":     0.5,
+		"color":     "red",
+		"tuning":    map[string]interface{}{"batchSize": 100},
+		"other":     "server",
+	}
+	dispatcher := NewTestDispatcher()
+	var flags []interface{}
+	for key, value := range server {
+		key, value := key, value
+		dispatcher.SetFlagResponse(key, func() (int, map[string]interface{}) {
+			atomic.AddInt32(calls, 1)
+			return 200, map[string]interface{}{"key": key, "value": value, "reason": "TARGETING_MATCH"}
+		})
+		flags = append(flags, map[string]interface{}{"key": key, "value": value, "reason": "TARGETING_MATCH"})
+	}
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"flags": flags}
+	})
+	srv := httptest.NewServer(dispatcher)
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(srv.URL),
+		WithRealtime(false),
+		WithEnvOverrides("FSTEST_"),
+	)
+	if err != nil {
+		srv.Close()
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		srv.Close()
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	return provider, calls, func() {
+		provider.Shutdown()
+		srv.Close()
+	}
+}
+
+func TestEnvOverrides_EvaluateFlag(t *testing.T) {
+	provider, calls, cleanup := createEnvOverrideProvider(t)
+	defer cleanup()
+
+	result := provider.EvaluateFlag("dark-mode", openfeature.FlattenedContext{})
+	if result == nil || result.Value != true {
+		t.Fatalf("Expected dark-mode to be overridden to true, got %+v", result)
+	}
+	if result.Reason != string(openfeature.StaticReason) || result.ValueType != "boolean" {
+		t.Errorf("Expected a STATIC boolean, got reason %q, type %q", result.Reason, result.ValueType)
+	}
+	if got := atomic.LoadInt32(calls); got != 0 {
+		t.Errorf("Expected no requests for an overridden flag, got %d", got)
+	}
+
+	if result := provider.EvaluateFlag("other", openfeature.FlattenedContext{}); result == nil || result.Value != "server" {
+		t.Errorf("Expected flags without an override to come from the server, got %+v", result)
+	}
+}
+
+func TestEnvOverrides_TypedEvaluations(t *testing.T) {
+	provider, calls, cleanup := createEnvOverrideProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+	evalCtx := openfeature.FlattenedContext{}
+
+	if got := provider.BooleanEvaluation(ctx, "dark-mode", false, evalCtx); !got.Value || got.Reason != openfeature.StaticReason {
+		t.Errorf("Expected true with reason STATIC, got %+v", got)
+	}
+	if got := provider.IntEvaluation(ctx, "limit", 0, evalCtx); got.Value != 42 {
+		t.Errorf("Expected 42, got %d", got.Value)
+	}
+	if got := provider.FloatEvaluation(ctx, "ratio", 0, evalCtx); got.Value != 0.25 {
+		t.Errorf("Expected 0.25, got %v", got.Value)
+	}
+	if got := provider.StringEvaluation(ctx, "color", "", evalCtx); got.Value != "blue" {
+		t.Errorf("Expected blue, got %q", got.Value)
+	}
+	want := map[string]interface{}{"batchSize": float64(5)}
+	if got := provider.ObjectEvaluation(ctx, "tuning", nil, evalCtx); !reflect.DeepEqual(got.Value, want) {
+		t.Errorf("Expected %v, got %v", want, got.Value)
+	}
+	if got := atomic.LoadInt32(calls); got != 0 {
+		t.Errorf("Expected no requests for overridden flags, got %d", got)
+	}
+}
+
+func TestEnvOverrides_EvaluateAllFlags(t *testing.T) {
+	provider, _, cleanup := createEnvOverrideProvider(t)
+	defer cleanup()
+
+	values := make(map[string]interface{})
+	for _, flag := range provider.EvaluateAllFlags(openfeature.FlattenedContext{}) {
+		values[flag.Key] = flag.Value
+	}
+	if values["dark-mode"] != true || values["color"] != "blue" {
+		t.Errorf("Expected overrides to replace server values, got %v", values)
+	}
+	if values["other"] != "server" {
+		t.Errorf("Expected other flags to keep their server values, got %v", values["other"])
+	}
+}
+
+func TestParseEnvValue(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want interface{}
+	}{
+		{"true", true},
+		{"false", false},
+		{"42", float64(42)},
+		{"-2.5", -2.5},
+		{`"quoted"`, "quoted"},
+		{"plain text", "plain text"},
+		{"null", "null"},
+		{`[1,"a"]`, []interface{}{float64(1), "a"}},
+	}
+	for _, tt := range tests {
+		if got := parseEnvValue(tt.raw); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseEnvValue(%q) = %#v, want %#v", tt.raw, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package flipswitch
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaultErrorHistorySize is the number of recent errors kept for
+// RecentErrors and DiagnosticsBundle unless WithErrorHistorySize is given.
+const defaultErrorHistorySize = 10
+
+// Operations recorded in ErrorRecord.Operation.
+const (
+	// OperationSseConnection is a failure to open or read the SSE stream.
+	OperationSseConnection = "sse_connection"
+	// OperationEvaluation is a failed single flag evaluation.
+	OperationEvaluation = "evaluation"
+	// OperationBulkEvaluation is a failed bulk evaluation of all flags.
+	OperationBulkEvaluation = "bulk_evaluation"
+)
+
+// ErrorRecord is a connection or evaluation error kept in the provider's
+// error history.
+type ErrorRecord struct {
+	Time time.Time `json:"time"`
+
+	// Operation is what failed, one of the Operation constants.
+	Operation string `json:"operation"`
+
+	// FlagKey is the flag being evaluated, if any.
+	FlagKey string `json:"flagKey,omitempty"`
+
+	// StatusCode is the HTTP status of the response, or zero if the error
+	// did not come from a response.
+	StatusCode int `json:"statusCode,omitempty"`
+
+	Message string `json:"message"`
+}
+
+// WithErrorHistorySize sets how many recent connection and evaluation errors
+// are kept for RecentErrors and DiagnosticsBundle (default 10). Older errors
+// are dropped as new ones arrive; zero or less keeps none.
+func WithErrorHistorySize(n int) Option {
+	return func(p *FlipswitchProvider) {
+		p.errorHistorySize = n
+	}
+}
+
+// RecentErrors returns the most recent connection and evaluation errors,
+// oldest first. Flags that do not exist are not errors and are not recorded.
+func (p *FlipswitchProvider) RecentErrors() []ErrorRecord {
+	return p.errorHistory.list()
+}
+
+// recordError adds err from operation to the error history, unless it only
+// reports that the flag does not exist.
+func (p *FlipswitchProvider) recordError(operation, flagKey string, err error) {
+	if errors.Is(err, ErrFlagNotFound) {
+		return
+	}
+	p.errorHistory.add(operation, flagKey, err)
+}
+
+// errorRing keeps the most recent errors, overwriting the oldest once full.
+// A nil ring keeps nothing.
+type errorRing struct {
+	entries []ErrorRecord
+	next    int
+	full    bool
+	mu      sync.Mutex
+}
+
+func newErrorRing(size int) *errorRing {
+	if size <= 0 {
+		return nil
+	}
+	return &errorRing{entries: make([]ErrorRecord, size)}
+}
+
+// add records err from operation with the current time.
+func (r *errorRing) add(operation, flagKey string, err error) {
+	if r == nil || err == nil {
+		return
+	}
+	entry := ErrorRecord{
+		Time:      time.Now(),
+		Operation: operation,
+		FlagKey:   flagKey,
+		Message:   err.Error(),
+	}
+	var se *HTTPStatusError
+	var sse *SseStatusError
+	switch {
+	case errors.As(err, &se):
+		entry.StatusCode = se.StatusCode
+	case errors.As(err, &sse):
+		entry.StatusCode = sse.StatusCode
+	}
+
+	r.mu.Lock()
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mu.Unlock()
+}
+
+// list returns the recorded errors, oldest first.
+func (r *errorRing) list() []ErrorRecord {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]ErrorRecord(nil), r.entries[:r.next]...)
+	}
+	result := make([]ErrorRecord, 0, len(r.entries))
+	result = append(result, r.entries[r.next:]...)
+	return append(result, r.entries[:r.next]...)
+}
@@ -0,0 +1,93 @@
+package flipswitch
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// failingFlagsServer serves each of keys with the given status code.
+func failingFlagsServer(statusCode int, keys ...string) *httptest.Server {
+	dispatcher := NewTestDispatcher()
+	for _, key := range keys {
+		dispatcher.SetFlagResponse(key, func() (int, map[string]interface{}) {
+			return statusCode, map[string]interface{}{}
+		})
+	}
+	return httptest.NewServer(dispatcher)
+}
+
+func TestRecentErrors_KeepsMostRecentInOrder(t *testing.T) {
+	keys := []string{"flag-a", "flag-b", "flag-c", "flag-d", "flag-e"}
+	server := failingFlagsServer(503, keys...)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key", WithBaseURL(server.URL), WithRealtime(false), WithErrorHistorySize(3))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	for _, key := range keys {
+		provider.EvaluateFlag(key, openfeature.FlattenedContext{})
+	}
+
+	records := provider.RecentErrors()
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 errors, got %d: %+v", len(records), records)
+	}
+	for i, record := range records {
+		if want := keys[2+i]; record.FlagKey != want {
+			t.Errorf("Error %d: expected flag %q, got %q", i, want, record.FlagKey)
+		}
+		if record.Operation != OperationEvaluation || record.StatusCode != 503 || record.Message == "" {
+			t.Errorf("Error %d: unexpected record %+v", i, record)
+		}
+		if i > 0 && record.Time.Before(records[i-1].Time) {
+			t.Errorf("Error %d: recorded before the previous error", i)
+		}
+	}
+}
+
+func TestRecentErrors_RecordsBulkFailuresButNotMissingFlags(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		return 500, map[string]interface{}{}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("missing", openfeature.FlattenedContext{})
+	provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+
+	records := provider.RecentErrors()
+	if len(records) != 1 {
+		t.Fatalf("Expected only the bulk failure, got %+v", records)
+	}
+	if records[0].Operation != OperationBulkEvaluation || records[0].StatusCode != 500 {
+		t.Errorf("Unexpected record %+v", records[0])
+	}
+}
+
+func TestRecentErrors_DisabledWithZeroSize(t *testing.T) {
+	server := failingFlagsServer(503, "my-flag")
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key", WithBaseURL(server.URL), WithRealtime(false), WithErrorHistorySize(0))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	if records := provider.RecentErrors(); len(records) != 0 {
+		t.Errorf("Expected no errors to be kept, got %+v", records)
+	}
+}
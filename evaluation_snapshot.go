@@ -0,0 +1,107 @@
+package flipswitch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// redactedPlaceholder replaces the API key in captured request and response
+// bodies.
+const redactedPlaceholder = "[REDACTED]"
+
+// EvaluationSnapshot captures the exact inputs and outputs of a single flag
+// evaluation. It serializes to JSON for attaching to support tickets.
+type EvaluationSnapshot struct {
+	// FlagKey is the evaluated flag key.
+	FlagKey string `json:"flagKey"`
+
+	// SentContext is the transformed evaluation context sent to the server,
+	// as it was encoded in the request body, with the API key redacted.
+	SentContext map[string]interface{} `json:"sentContext"`
+
+	// StatusCode is the HTTP status returned by the server.
+	StatusCode int `json:"statusCode"`
+
+	// RawResponse is the response body, with the API key redacted.
+	RawResponse string `json:"rawResponse"`
+
+	// Evaluation is the result parsed from RawResponse, or nil if the
+	// evaluation failed.
+	Evaluation *FlagEvaluation `json:"evaluation,omitempty"`
+
+	// Error describes why the response could not be turned into a result.
+	Error string `json:"error,omitempty"`
+
+	// Timestamp is the RFC 3339 time at which the request was sent.
+	Timestamp string `json:"timestamp"`
+}
+
+// EvaluateFlagSnapshot evaluates a single flag and records the context that
+// was sent and the response that came back. The call always goes to the
+// server. A snapshot is returned whenever a response was received, even if
+// it was an error response; the error is only non-nil if the request itself
+// failed.
+func (p *FlipswitchProvider) EvaluateFlagSnapshot(flagKey string, evalCtx openfeature.FlattenedContext) (*EvaluationSnapshot, error) {
+	sentContext := p.outgoingContext(evalCtx)
+	snapshot := &EvaluationSnapshot{
+		FlagKey:     flagKey,
+		SentContext: p.redactContext(sentContext),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+	}
+
+	statusCode, respBody, err := p.postFlag(context.Background(), flagKey, sentContext)
+	var verr *ResponseVerificationError
+	if err != nil && !errors.As(err, &verr) {
+		return nil, err
+	}
+
+	respBody = p.redactBody(respBody)
+	snapshot.StatusCode = statusCode
+	snapshot.RawResponse = string(respBody)
+
+	if verr != nil {
+		snapshot.Error = verr.Error()
+		return snapshot, nil
+	}
+
+	eval, err := parseFlagResponse(flagKey, statusCode, respBody)
+	if err != nil {
+		snapshot.Error = err.Error()
+	} else {
+		snapshot.Evaluation = eval
+	}
+
+	return snapshot, nil
+}
+
+// redactBody replaces the API key in a JSON document with a placeholder,
+// both as is and as encoded in a JSON string.
+func (p *FlipswitchProvider) redactBody(data []byte) []byte {
+	if p.apiKey == "" {
+		return data
+	}
+	data = bytes.ReplaceAll(data, []byte(p.apiKey), []byte(redactedPlaceholder))
+	if encoded, err := json.Marshal(p.apiKey); err == nil {
+		data = bytes.ReplaceAll(data, encoded[1:len(encoded)-1], []byte(redactedPlaceholder))
+	}
+	return data
+}
+
+// redactContext returns sentContext as encoded in the request body, with
+// the API key redacted.
+func (p *FlipswitchProvider) redactContext(sentContext map[string]interface{}) map[string]interface{} {
+	data, err := json.Marshal(sentContext)
+	if err != nil {
+		return sentContext
+	}
+	var redacted map[string]interface{}
+	if err := json.Unmarshal(p.redactBody(data), &redacted); err != nil {
+		return sentContext
+	}
+	return redacted
+}
@@ -0,0 +1,172 @@
+package flipswitch
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestEvaluateFlagSnapshot_CapturesSentContextAndResult(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("dark-mode", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{
+			"key":     "dark-mode",
+			"value":   true,
+			"reason":  "TARGETING_MATCH",
+			"variant": "on",
+		}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	snapshot, err := provider.EvaluateFlagSnapshot("dark-mode", openfeature.FlattenedContext{
+		"targetingKey": "user-1",
+		"plan":         "pro",
+	})
+	if err != nil {
+		t.Fatalf("Expected snapshot, got error: %v", err)
+	}
+
+	if snapshot.FlagKey != "dark-mode" {
+		t.Errorf("Expected FlagKey 'dark-mode', got '%s'", snapshot.FlagKey)
+	}
+	if snapshot.SentContext["targetingKey"] != "user-1" || snapshot.SentContext["plan"] != "pro" {
+		t.Errorf("Unexpected sent context: %v", snapshot.SentContext)
+	}
+	if snapshot.StatusCode != 200 {
+		t.Errorf("Expected status 200, got %d", snapshot.StatusCode)
+	}
+	if !strings.Contains(snapshot.RawResponse, `"TARGETING_MATCH"`) {
+		t.Errorf("Expected raw response to contain the reason, got %s", snapshot.RawResponse)
+	}
+	if snapshot.Evaluation == nil || snapshot.Evaluation.Value != true || snapshot.Evaluation.Variant != "on" {
+		t.Errorf("Unexpected parsed evaluation: %+v", snapshot.Evaluation)
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("Expected snapshot to serialize, got: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	for _, field := range []string{"flagKey", "sentContext", "rawResponse", "evaluation", "timestamp"} {
+		if _, ok := decoded[field]; !ok {
+			t.Errorf("Expected JSON field %q in %s", field, data)
+		}
+	}
+}
+
+func TestEvaluateFlagSnapshot_RecordsErrorResponses(t *testing.T) {
+	server := httptest.NewServer(NewTestDispatcher())
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	snapshot, err := provider.EvaluateFlagSnapshot("missing", openfeature.FlattenedContext{"targetingKey": "user-1"})
+	if err != nil {
+		t.Fatalf("Expected snapshot for an error response, got error: %v", err)
+	}
+	if snapshot.StatusCode != 404 {
+		t.Errorf("Expected status 404, got %d", snapshot.StatusCode)
+	}
+	if snapshot.Evaluation != nil {
+		t.Errorf("Expected no evaluation, got %+v", snapshot.Evaluation)
+	}
+	if snapshot.Error == "" {
+		t.Error("Expected the error to be recorded")
+	}
+	if !strings.Contains(snapshot.RawResponse, "FLAG_NOT_FOUND") {
+		t.Errorf("Expected raw error response, got %s", snapshot.RawResponse)
+	}
+}
+
+func TestEvaluateFlagSnapshot_RedactsApiKey(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("echo", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "echo", "value": "test-api-key"}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	snapshot, err := provider.EvaluateFlagSnapshot("echo", openfeature.FlattenedContext{})
+	if err != nil {
+		t.Fatalf("Expected snapshot, got error: %v", err)
+	}
+	if strings.Contains(snapshot.RawResponse, "test-api-key") {
+		t.Errorf("Expected API key to be redacted, got %s", snapshot.RawResponse)
+	}
+}
+
+func TestEvaluateFlagSnapshot_NetworkError(t *testing.T) {
+	provider, err := NewProvider("test-api-key", WithBaseURL(unreachableURL()), WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if _, err := provider.EvaluateFlagSnapshot("dark-mode", openfeature.FlattenedContext{}); err == nil {
+		t.Error("Expected an error when the server is unreachable")
+	}
+}
+
+func TestEvaluateFlagSnapshot_RedactsApiKeyInBodies(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("echo", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{
+			"key":      "echo",
+			"value":    "key is test-api-key",
+			"metadata": map[string]interface{}{"note": "test-api-key"},
+		}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	snapshot, err := provider.EvaluateFlagSnapshot("echo", openfeature.FlattenedContext{
+		"targetingKey": "user-1",
+		"token":        "test-api-key",
+		"nested":       map[string]interface{}{"keys": []interface{}{"test-api-key"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected snapshot, got error: %v", err)
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("Expected snapshot to serialize, got: %v", err)
+	}
+	if strings.Contains(string(data), "test-api-key") {
+		t.Errorf("Expected the API key to be redacted everywhere, got %s", data)
+	}
+	if snapshot.SentContext["token"] != "[REDACTED]" || snapshot.SentContext["targetingKey"] != "user-1" {
+		t.Errorf("Expected the sent context to be redacted, got %v", snapshot.SentContext)
+	}
+	if snapshot.Evaluation == nil || snapshot.Evaluation.Value != "key is [REDACTED]" {
+		t.Errorf("Expected the parsed value to be redacted, got %+v", snapshot.Evaluation)
+	}
+}
@@ -0,0 +1,114 @@
+package flipswitch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// defaultEventBufferSize is the capacity of the OpenFeature event channel.
+const defaultEventBufferSize = 5
+
+// WithEventBufferSize sets how many OpenFeature events are buffered for a
+// slow consumer of EventChannel before further events are dropped. The
+// default is 5.
+func WithEventBufferSize(size int) Option {
+	return func(p *FlipswitchProvider) {
+		if size >= 0 {
+			p.eventBufferSize = size
+		}
+	}
+}
+
+// WithCatchUpOnReconnect makes the provider emit a single synthetic bulk
+// invalidation, as if a config-updated event had arrived, when the SSE
+// connection is re-established after flag change events were dropped
+// because the event buffer was full. Consumers that re-sync on bulk
+// invalidation then recover from the lost events, which cannot be replayed
+// individually. Disabled by default.
+func WithCatchUpOnReconnect(enabled bool) Option {
+	return func(p *FlipswitchProvider) {
+		p.catchUpOnReconnect = enabled
+	}
+}
+
+// catchUp emits a bulk invalidation if flag change events were dropped since
+// the last catch-up.
+func (p *FlipswitchProvider) catchUp() {
+	p.mu.Lock()
+	dropped := p.changesDropped
+	p.changesDropped = false
+	p.mu.Unlock()
+
+	if !dropped {
+		return
+	}
+	p.logger.Infow("Flag change events were dropped, emitting bulk invalidation")
+	p.handleFlagChange(FlagChangeEvent{Timestamp: p.clock.Now().UTC().Format(time.RFC3339)})
+}
+
+// transitionStatus moves the provider to status and emits eventType with
+// message if it is currently in one of the states in from, so that each
+// transition is reported once. It reports whether the transition happened.
+// The event is emitted under the lock, so that subscribers see the
+// transitions in the order they happened.
+func (p *FlipswitchProvider) transitionStatus(from []openfeature.State, status openfeature.State, eventType openfeature.EventType, message string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	matched := false
+	for _, state := range from {
+		matched = matched || p.status == state
+	}
+	if matched {
+		p.status = status
+		p.emitEvent(eventType, message)
+	}
+	return matched
+}
+
+// markInitStale reports a stale start once Init has returned, unless the
+// provider has recovered in the meantime.
+func (p *FlipswitchProvider) markInitStale() {
+	p.transitionStatus([]openfeature.State{openfeature.StaleState}, openfeature.StaleState,
+		openfeature.ProviderStale, "Flipswitch unreachable, serving bootstrapped flags")
+}
+
+// markSseStale marks a ready provider stale when its SSE connection drops,
+// since flag changes may be missed until it is re-established. The drop
+// caused by Shutdown closing the connection is ignored.
+func (p *FlipswitchProvider) markSseStale() {
+	if p.currentSseClient() == nil {
+		return
+	}
+	if p.transitionStatus([]openfeature.State{openfeature.ReadyState}, openfeature.StaleState,
+		openfeature.ProviderStale, "Flipswitch connection lost, flags may be outdated") {
+		p.logger.Warnw("SSE connection lost, provider is stale")
+	}
+}
+
+// markSseFailed puts the provider in the error state once the SSE connection
+// has failed maxSseRetries times in a row.
+func (p *FlipswitchProvider) markSseFailed(retries int) {
+	p.transitionStatus([]openfeature.State{openfeature.ReadyState, openfeature.StaleState}, openfeature.ErrorState,
+		openfeature.ProviderError, fmt.Sprintf("Flipswitch connection failed %d times", retries))
+}
+
+// markSseRestored marks a stale or errored provider ready again once its SSE
+// connection is re-established.
+func (p *FlipswitchProvider) markSseRestored() {
+	p.transitionStatus([]openfeature.State{openfeature.StaleState, openfeature.ErrorState}, openfeature.ReadyState,
+		openfeature.ProviderReady, "Flipswitch connection restored")
+}
+
+// markFetchRestored marks a provider that went stale at Init ready again
+// once a bulk evaluation succeeds. With realtime updates enabled, that is
+// left to the SSE connection, whose loss may be why the provider is stale.
+func (p *FlipswitchProvider) markFetchRestored() {
+	if p.enableRealtime {
+		return
+	}
+	p.transitionStatus([]openfeature.State{openfeature.StaleState}, openfeature.ReadyState,
+		openfeature.ProviderReady, "Flipswitch reachable again")
+}
@@ -0,0 +1,404 @@
+package flipswitch
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// outageServer serves an SSE stream that sends three flag updates and then
+// closes, simulating an outage. The next connection is held until reconnect
+// is closed, then sends a "marker" flag update and stays open.
+func outageServer(reconnect <-chan struct{}) *httptest.Server {
+	var connections int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&connections, 1) == 1 {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			for _, key := range []string{"flag-a", "flag-b", "flag-c"} {
+				fmt.Fprint(w, sseFrame("flag-updated", `{"flagKey":"`+key+`","timestamp":"2024-06-15T12:00:00Z"}`))
+			}
+			return
+		}
+
+		select {
+		case <-reconnect:
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, sseFrame("flag-updated", `{"flagKey":"marker","timestamp":"2024-06-15T12:00:01Z"}`))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	return httptest.NewServer(dispatcher)
+}
+
+// runOutage starts a provider against an outage server, waits until the
+// first connection's events were handled with only one fitting in the event
+// buffer, drains the buffer, lets the SSE client reconnect and returns the
+// first flag change event emitted afterwards.
+func runOutage(t *testing.T, opts ...Option) openfeature.Event {
+	t.Helper()
+	reconnect := make(chan struct{})
+	server := outageServer(reconnect)
+	defer server.Close()
+	// Release the held connection before the server shuts down
+	defer func() {
+		select {
+		case <-reconnect:
+		default:
+			close(reconnect)
+		}
+	}()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		append([]Option{
+			WithBaseURL(server.URL),
+			WithRealtime(true),
+			WithEventBufferSize(1),
+		}, opts...)...,
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	var changes int32
+	provider.AddFlagChangeListener(func(event FlagChangeEvent) {
+		atomic.AddInt32(&changes, 1)
+	})
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	deadline := time.After(5 * time.Second)
+	for atomic.LoadInt32(&changes) < 3 {
+		select {
+		case <-deadline:
+			t.Fatal("timed out waiting for flag updates")
+		default:
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// The slow consumer catches up on what was buffered
+	buffered := 0
+	for len(provider.EventChannel()) > 0 {
+		<-provider.EventChannel()
+		buffered++
+	}
+	if buffered != 1 {
+		t.Fatalf("Expected 1 buffered event, got %d", buffered)
+	}
+
+	close(reconnect)
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-provider.EventChannel():
+			// Skip the ProviderReady reporting the restored connection
+			if event.EventType == openfeature.ProviderConfigChange {
+				return event
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for an event after reconnect")
+			return openfeature.Event{}
+		}
+	}
+}
+
+func TestCatchUpOnReconnect_EmitsBulkInvalidation(t *testing.T) {
+	event := runOutage(t, WithCatchUpOnReconnect(true))
+
+	if event.EventType != openfeature.ProviderConfigChange {
+		t.Fatalf("Expected a config change event, got %v", event.EventType)
+	}
+	if len(event.FlagChanges) != 0 {
+		t.Errorf("Expected a bulk invalidation, got changes for %v", event.FlagChanges)
+	}
+}
+
+func TestCatchUpOnReconnect_DisabledByDefault(t *testing.T) {
+	event := runOutage(t)
+
+	if len(event.FlagChanges) != 1 || event.FlagChanges[0] != "marker" {
+		t.Errorf("Expected the next live event without a catch-up, got %+v", event)
+	}
+}
+
+// nextEvent returns the next event on the provider's event channel.
+func nextEvent(t *testing.T, provider *FlipswitchProvider) openfeature.Event {
+	t.Helper()
+	select {
+	case event := <-provider.EventChannel():
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a provider event")
+	}
+	return openfeature.Event{}
+}
+
+// expectEvent fails the test unless the next event on the provider's event
+// channel has the given type, and returns it.
+func expectEvent(t *testing.T, provider *FlipswitchProvider, eventType openfeature.EventType) openfeature.Event {
+	t.Helper()
+	event := nextEvent(t, provider)
+	if event.EventType != eventType {
+		t.Fatalf("Expected a %s event, got %+v", eventType, event)
+	}
+	return event
+}
+
+// waitForClientState fails the test unless the OpenFeature client reaches
+// state within a few seconds.
+func waitForClientState(t *testing.T, client *openfeature.Client, state openfeature.State) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for client.State() != state {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the client to be %s, got %s", state, client.State())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestProviderEvents_NoReadyEventOnInit(t *testing.T) {
+	server := httptest.NewServer(NewTestDispatcher())
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if provider.Status() != openfeature.ReadyState {
+		t.Errorf("Expected READY, got %s", provider.Status())
+	}
+	// The OpenFeature SDK emits ProviderReady itself
+	if n := len(provider.EventChannel()); n != 0 {
+		t.Errorf("Expected no events, got %d", n)
+	}
+}
+
+func TestProviderEvents_NoReadyEventOnOfflineInit(t *testing.T) {
+	provider, err := NewProvider("test-api-key", WithOfflineMode(true))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if n := len(provider.EventChannel()); n != 0 {
+		t.Errorf("Expected no events, got %d", n)
+	}
+}
+
+func TestProviderEvents_ReadyHandlerRunsOnce(t *testing.T) {
+	server := httptest.NewServer(NewTestDispatcher())
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	domain := "ready-handler-runs-once"
+	var calls int32
+	callback := func(openfeature.EventDetails) { atomic.AddInt32(&calls, 1) }
+	client := openfeature.NewClient(domain)
+	client.AddHandler(openfeature.ProviderReady, &callback)
+	defer client.RemoveHandler(openfeature.ProviderReady, &callback)
+
+	if err := openfeature.SetNamedProviderAndWait(domain, provider); err != nil {
+		t.Fatalf("Failed to set provider: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected the ready handler to run once, got %d", n)
+	}
+}
+
+func TestProviderEvents_ErrorOnInvalidAPIKey(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetInitFailure(401)
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err == nil {
+		t.Fatal("Expected Init to fail")
+	}
+	if provider.Status() != openfeature.ErrorState {
+		t.Errorf("Expected ERROR, got %s", provider.Status())
+	}
+	// The OpenFeature SDK emits ProviderError itself
+	if n := len(provider.EventChannel()); n != 0 {
+		t.Errorf("Expected no events, got %d", n)
+	}
+}
+
+func TestProviderEvents_ErrorHandlerRunsOnceOnFailedInit(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetInitFailure(401)
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	// A fresh domain, as the SDK runs a handler added to a domain already in
+	// the error state
+	domain := fmt.Sprintf("error-handler-runs-once-%p", provider)
+	var calls int32
+	callback := func(openfeature.EventDetails) { atomic.AddInt32(&calls, 1) }
+	client := openfeature.NewClient(domain)
+	client.AddHandler(openfeature.ProviderError, &callback)
+	defer client.RemoveHandler(openfeature.ProviderError, &callback)
+
+	if err := openfeature.SetNamedProviderAndWait(domain, provider); err == nil {
+		t.Fatal("Expected setting the provider to fail")
+	}
+	time.Sleep(200 * time.Millisecond)
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected the error handler to run once, got %d", n)
+	}
+	if state := client.State(); state != openfeature.ErrorState {
+		t.Errorf("Expected the client to be ERROR, got %s", state)
+	}
+}
+
+func TestProviderEvents_StaleInitLeavesClientStale(t *testing.T) {
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(unreachableURL()),
+		WithRealtime(false),
+		WithBootstrap([]FlagEvaluation{{Key: "dark-mode", Value: true}}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	domain := fmt.Sprintf("stale-init-leaves-client-stale-%p", provider)
+	client := openfeature.NewClient(domain)
+	if err := openfeature.SetNamedProviderAndWait(domain, provider); err != nil {
+		t.Fatalf("Failed to set provider: %v", err)
+	}
+
+	waitForClientState(t, client, openfeature.StaleState)
+	// The SDK's own ProviderReady must not override the stale start
+	time.Sleep(100 * time.Millisecond)
+	if state := client.State(); state != openfeature.StaleState {
+		t.Errorf("Expected the client to be STALE, got %s", state)
+	}
+}
+
+func TestProviderEvents_StaleOnDropThenReadyOnReconnect(t *testing.T) {
+	// The first connection stays open until drop is closed
+	drop := make(chan struct{})
+	var connections int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		if atomic.AddInt32(&connections, 1) == 1 {
+			select {
+			case <-drop:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		<-r.Context().Done()
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithSseRetryBounds(10*time.Millisecond, 10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	close(drop)
+	expectEvent(t, provider, openfeature.ProviderStale)
+	expectEvent(t, provider, openfeature.ProviderReady)
+	if provider.Status() != openfeature.ReadyState {
+		t.Errorf("Expected READY after reconnect, got %s", provider.Status())
+	}
+}
+
+func TestProviderEvents_ErrorPastRetryThreshold(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithMaxSseRetries(2),
+		WithSseRetryBounds(10*time.Millisecond, 10*time.Millisecond),
+		WithPollingFallback(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	// Failures before Init reports ready change nothing, so the provider may
+	// go from ready straight to error
+	event := nextEvent(t, provider)
+	if event.EventType == openfeature.ProviderStale {
+		event = expectEvent(t, provider, openfeature.ProviderError)
+	}
+	if event.EventType != openfeature.ProviderError {
+		t.Fatalf("Expected a %s event, got %+v", openfeature.ProviderError, event)
+	}
+	if provider.Status() != openfeature.ErrorState {
+		t.Errorf("Expected ERROR, got %s", provider.Status())
+	}
+
+	// Further failures are not reported again
+	time.Sleep(100 * time.Millisecond)
+	if n := len(provider.EventChannel()); n != 0 {
+		t.Errorf("Expected no further events, got %d", n)
+	}
+}
@@ -0,0 +1,44 @@
+package flipswitch
+
+import (
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// ReinitAfterFork discards the provider's network state and, if the provider
+// was initialized, opens a fresh SSE connection. Call it in a child process
+// that inherited the provider from its parent, as in pre-fork server models:
+// the inherited SSE stream and pooled HTTP connections belong to the parent
+// and must not be used by the child. Polling fallback is stopped and the SSE
+// retry count reset, so the child starts from a clean connection state; with
+// WithPollingMode, polling is restarted instead.
+// Configuration, listeners and cached values are kept. It does nothing to a
+// provider in offline mode or one that has gone fatal.
+func (p *FlipswitchProvider) ReinitAfterFork() {
+	if p.offlineMode {
+		return
+	}
+
+	p.stopPolling()
+
+	p.mu.Lock()
+	client := p.sseClient
+	p.sseClient = nil
+	p.sseRetryCount = 0
+	p.sseConnectStarted = false
+	active := p.initialized && p.status != openfeature.FatalState
+	reconnect := active && p.enableRealtime
+	p.mu.Unlock()
+
+	if client != nil {
+		client.Close()
+	}
+	p.httpClient.CloseIdleConnections()
+
+	if reconnect {
+		p.startSseConnection()
+	}
+	if active && p.pollingMode {
+		p.startPolling()
+	}
+	p.logger.Infow("Provider re-initialized after fork", "sse", reconnect)
+}
@@ -0,0 +1,87 @@
+package flipswitch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestReinitAfterFork_OpensFreshSseConnection(t *testing.T) {
+	connected := make(chan string, 4)
+	closed := make(chan string, 4)
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		connected <- r.RemoteAddr
+		serveSseKeepAlive(w, r)
+		closed <- r.RemoteAddr
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	waitFor := func(ch <-chan string, what string) string {
+		t.Helper()
+		select {
+		case addr := <-ch:
+			return addr
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s", what)
+			return ""
+		}
+	}
+
+	inherited := waitFor(connected, "the first SSE connection")
+	inheritedClient := provider.currentSseClient()
+
+	provider.ReinitAfterFork()
+
+	if got := waitFor(closed, "the inherited connection to close"); got != inherited {
+		t.Errorf("Expected the inherited connection %s to close, got %s", inherited, got)
+	}
+	if fresh := waitFor(connected, "a fresh SSE connection"); fresh == inherited {
+		t.Errorf("Expected a new connection, got the inherited %s again", fresh)
+	}
+	if client := provider.currentSseClient(); client == nil || client == inheritedClient {
+		t.Error("Expected a new SSE client")
+	}
+	if provider.Status() != openfeature.ReadyState {
+		t.Errorf("Expected the provider to stay READY, got %s", provider.Status())
+	}
+}
+
+func TestReinitAfterFork_BeforeInitDoesNotConnect(t *testing.T) {
+	connected := make(chan struct{}, 1)
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		connected <- struct{}{}
+		serveSseKeepAlive(w, r)
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.ReinitAfterFork()
+
+	select {
+	case <-connected:
+		t.Error("Expected no SSE connection before Init")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,80 @@
+package flipswitch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// healthSummary is the JSON body served by HealthHandler.
+type healthSummary struct {
+	Healthy       bool             `json:"healthy"`
+	Status        string           `json:"status"`
+	SSE           ConnectionStatus `json:"sse"`
+	PollingActive bool             `json:"pollingActive"`
+	CacheEnabled  bool             `json:"cacheEnabled"`
+}
+
+// HealthHandler returns an http.Handler that reports the provider's health
+// as JSON, ready to mount on an existing server. It responds 200 when the
+// provider is ready, or stale while it can still serve cached values (with
+// WithCache or WithServeStaleOnError), and 503 otherwise. Unlike Ready, it
+// makes no request to Flipswitch, so it is cheap enough for liveness probes.
+func (p *FlipswitchProvider) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		summary := p.health()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if summary.Healthy {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(summary)
+	})
+}
+
+// health summarizes the provider's current state.
+func (p *FlipswitchProvider) health() healthSummary {
+	p.mu.RLock()
+	status := p.status
+	pollingActive := p.pollingActive
+	p.mu.RUnlock()
+
+	cacheEnabled := p.cache != nil || p.lastKnown != nil
+	return healthSummary{
+		Healthy:       status == openfeature.ReadyState || (status == openfeature.StaleState && cacheEnabled),
+		Status:        string(status),
+		SSE:           p.GetSseStatus(),
+		PollingActive: pollingActive,
+		CacheEnabled:  cacheEnabled,
+	}
+}
+
+// Ping checks that Flipswitch is reachable and accepts the API key, with the
+// same lightweight request Init makes. It returns ErrInvalidAPIKey if the key
+// is rejected, and an error wrapping the cause, such as an *HTTPStatusError,
+// for any other failure or non-2xx response. It changes no provider state and
+// does not need Init, so it may be called at any time, concurrently, for
+// example from a readiness probe. In offline mode it makes no request and
+// returns nil.
+func (p *FlipswitchProvider) Ping(ctx context.Context) error {
+	if p.offlineMode {
+		return nil
+	}
+	statusCode, err := p.checkAPIKey(ctx)
+	if errors.Is(err, ErrInvalidAPIKey) {
+		return ErrInvalidAPIKey
+	}
+	if err == nil && (statusCode < 200 || statusCode > 299) {
+		err = &HTTPStatusError{StatusCode: statusCode}
+	}
+	if err != nil {
+		return fmt.Errorf("flipswitch ping failed: %w", err)
+	}
+	return nil
+}
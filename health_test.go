@@ -0,0 +1,167 @@
+package flipswitch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestHealthHandler_ReportsEachState(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		status  openfeature.State
+		code    int
+		healthy bool
+	}{
+		{"ready", nil, openfeature.ReadyState, http.StatusOK, true},
+		{"stale with cache", []Option{WithCache(time.Minute)}, openfeature.StaleState, http.StatusOK, true},
+		{"stale with last-known values", []Option{WithServeStaleOnError(true)}, openfeature.StaleState, http.StatusOK, true},
+		{"stale without cache", nil, openfeature.StaleState, http.StatusServiceUnavailable, false},
+		{"not ready", nil, openfeature.NotReadyState, http.StatusServiceUnavailable, false},
+		{"error", nil, openfeature.ErrorState, http.StatusServiceUnavailable, false},
+		{"fatal", []Option{WithCache(time.Minute)}, openfeature.FatalState, http.StatusServiceUnavailable, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewProvider("test-api-key", append([]Option{WithRealtime(false)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("Failed to create provider: %v", err)
+			}
+			defer provider.Shutdown()
+			provider.setStatus(tt.status)
+
+			rec := httptest.NewRecorder()
+			provider.HealthHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+
+			if rec.Code != tt.code {
+				t.Errorf("Expected status %d, got %d", tt.code, rec.Code)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Expected JSON content type, got %q", ct)
+			}
+			var body struct {
+				Healthy       bool   `json:"healthy"`
+				Status        string `json:"status"`
+				SSE           string `json:"sse"`
+				PollingActive bool   `json:"pollingActive"`
+				CacheEnabled  bool   `json:"cacheEnabled"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Expected a JSON body, got %q: %v", rec.Body.String(), err)
+			}
+			if body.Healthy != tt.healthy || body.Status != string(tt.status) {
+				t.Errorf("Expected healthy=%v status=%s, got %+v", tt.healthy, tt.status, body)
+			}
+			if body.SSE != string(StatusDisconnected) || body.PollingActive {
+				t.Errorf("Expected a disconnected SSE and no polling, got %+v", body)
+			}
+			if body.CacheEnabled != (len(tt.opts) > 0) {
+				t.Errorf("Expected cacheEnabled=%v, got %v", len(tt.opts) > 0, body.CacheEnabled)
+			}
+		})
+	}
+}
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		check      func(error) bool
+	}{
+		{"success", http.StatusOK, func(err error) bool { return err == nil }},
+		{"unauthorized", http.StatusUnauthorized, func(err error) bool { return err == ErrInvalidAPIKey }},
+		{"forbidden", http.StatusForbidden, func(err error) bool { return err == ErrInvalidAPIKey }},
+		{"server error", http.StatusServiceUnavailable, func(err error) bool {
+			var statusErr *HTTPStatusError
+			return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusServiceUnavailable
+		}},
+		{"client error", http.StatusNotFound, func(err error) bool {
+			var statusErr *HTTPStatusError
+			return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			provider, err := createTestProvider(server)
+			if err != nil {
+				t.Fatalf("Failed to create provider: %v", err)
+			}
+			defer provider.Shutdown()
+
+			if err := provider.Ping(context.Background()); !tt.check(err) {
+				t.Errorf("Unexpected result: %v", err)
+			}
+			if atomic.LoadInt32(&requests) != 1 {
+				t.Errorf("Expected one request, got %d", requests)
+			}
+			if provider.Status() != openfeature.NotReadyState {
+				t.Errorf("Expected the provider state to be unchanged, got %s", provider.Status())
+			}
+		})
+	}
+}
+
+func TestPing_Concurrent(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := provider.Ping(context.Background()); err != nil {
+				t.Errorf("Ping failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&requests); got != 10 {
+		t.Errorf("Expected 10 requests, got %d", got)
+	}
+}
+
+func TestPing_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	err = provider.Ping(context.Background())
+	if err == nil || errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected a connection error, got %v", err)
+	}
+}
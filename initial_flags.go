@@ -0,0 +1,151 @@
+package flipswitch
+
+import (
+	"fmt"
+	"sync"
+)
+
+// seedStore holds the flags supplied with WithInitialFlags that have not yet
+// been replaced by a successful fetch or a change. Seeds are context-independent. A nil store
+// holds nothing.
+type seedStore struct {
+	flags map[string]FlagEvaluation
+	mu    sync.RWMutex
+}
+
+func newSeedStore(flags []FlagEvaluation) *seedStore {
+	s := &seedStore{flags: make(map[string]FlagEvaluation, len(flags))}
+	for _, flag := range flags {
+		s.flags[flag.Key] = flag
+	}
+	return s
+}
+
+func (s *seedStore) get(flagKey string) (FlagEvaluation, bool) {
+	if s == nil {
+		return FlagEvaluation{}, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	eval, ok := s.flags[flagKey]
+	return eval, ok
+}
+
+// drop removes the seed of flagKey, or every seed if flagKey is empty.
+func (s *seedStore) drop(flagKey string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if flagKey == "" {
+		clear(s.flags)
+		return
+	}
+	delete(s.flags, flagKey)
+}
+
+func (s *seedStore) all() []FlagEvaluation {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]FlagEvaluation, 0, len(s.flags))
+	for _, flag := range s.flags {
+		result = append(result, flag)
+	}
+	return result
+}
+
+// WithInitialFlags seeds the provider with flag values, typically a bulk
+// response saved by an earlier run, so evaluations are answered immediately
+// without waiting for a request. A seeded flag is served for every context;
+// the first time one is served after Init, all flags are evaluated once in
+// the background, for the evaluation context passed to Init (or set with
+// SetRefreshContext). Once a fetch of a flag succeeds, or
+// an SSE or polling change for it arrives, its seed is dropped and the flag
+// is evaluated live as usual. Seeded flags also act as
+// WithBootstrap values for flags that have no bootstrap value of their own,
+// so evaluations do not fail if the server cannot be reached.
+func WithInitialFlags(flags []FlagEvaluation) Option {
+	return func(p *FlipswitchProvider) {
+		p.initialFlags = newSeedStore(flags)
+		p.initialFlagsJSON = nil
+	}
+}
+
+// WithInitialFlagsJSON is WithInitialFlags for a raw bulk evaluation
+// response, {"flags": [...]}, which is parsed exactly like a live response.
+// NewProvider returns an error if data cannot be parsed.
+func WithInitialFlagsJSON(data []byte) Option {
+	return func(p *FlipswitchProvider) {
+		p.initialFlags = nil
+		p.initialFlagsJSON = data
+	}
+}
+
+// loadInitialFlags parses the response set with WithInitialFlagsJSON, if
+// any, and adds the seeded flags to the bootstrap flags they do not override.
+func (p *FlipswitchProvider) loadInitialFlags() error {
+	if p.initialFlagsJSON != nil {
+		flags, err := parseBulkResponse(p.initialFlagsJSON)
+		if err != nil {
+			return fmt.Errorf("initial flags: %w", err)
+		}
+		p.initialFlags = newSeedStore(flags)
+		p.initialFlagsJSON = nil
+	}
+
+	seeds := p.initialFlags.all()
+	if len(seeds) == 0 {
+		return nil
+	}
+	if p.bootstrap == nil {
+		p.bootstrap = newBootstrapStore(nil)
+	}
+	for _, flag := range seeds {
+		if _, ok := p.bootstrap.get(flag.Key); !ok {
+			p.bootstrap.put(flag)
+		}
+	}
+	return nil
+}
+
+// seededFlag returns a copy of the seed of flagKey, or nil if there is none
+// or it has been replaced. The seed is dropped once a fetch of the flag
+// succeeds, so later evaluations are live.
+func (p *FlipswitchProvider) seededFlag(flagKey string) *FlagEvaluation {
+	eval, ok := p.initialFlags.get(flagKey)
+	if !ok {
+		return nil
+	}
+	p.refreshSeeds()
+	return &eval
+}
+
+// refreshSeeds replaces the seeds with a bulk evaluation for the refresh
+// context in the background, the first time a seed is served after Init.
+// Shutdown cancels it. Seeds it does not replace, for example because it
+// fails, are left to later fetches, polls and changes.
+func (p *FlipswitchProvider) refreshSeeds() {
+	p.mu.RLock()
+	parent, evalCtx := p.backgroundCtx, p.refreshContext
+	p.mu.RUnlock()
+	if parent == nil || !p.seedRefreshed.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		if _, err := p.fetchAllFlags(parent, evalCtx); err != nil {
+			p.logger.Warnw("Failed to refresh the initial flags", errorFields(err)...)
+		}
+	}()
+}
+
+// dropSeeds drops the seeds of the flags a fetch returned.
+func (p *FlipswitchProvider) dropSeeds(flags []FlagEvaluation) {
+	for _, flag := range flags {
+		p.initialFlags.drop(flag.Key)
+	}
+}
@@ -0,0 +1,273 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// initialFlags are the seeds used by the tests below. The live values
+// served by countingFlag differ, so the two can be told apart.
+var initialFlags = []FlagEvaluation{
+	{Key: "dark-mode", Value: false, ValueType: "boolean", Reason: "TARGETING_MATCH", Variant: "off"},
+	{Key: "welcome", Value: "hi", ValueType: "string"},
+}
+
+// waitForSeedDropped waits until the seed of flagKey has been replaced.
+func waitForSeedDropped(t *testing.T, provider *FlipswitchProvider, flagKey string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, ok := provider.initialFlags.get(flagKey); !ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the seed of %s to be replaced", flagKey)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// seedRefreshServer serves dark-mode as true, unlike its seed, in bulk
+// evaluations, which are counted in calls once the provider is initialized.
+func seedRefreshServer(calls *int32) *httptest.Server {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		atomic.AddInt32(calls, 1)
+		return 200, map[string]interface{}{"flags": []interface{}{
+			map[string]interface{}{"key": "dark-mode", "value": true, "reason": "TARGETING_MATCH"},
+		}}
+	})
+	dispatcher.SetFlagResponse("dark-mode", countingFlag("dark-mode", new(int32)))
+	return httptest.NewServer(dispatcher)
+}
+
+func TestWithInitialFlags_ServedUntilFetched(t *testing.T) {
+	var calls int32
+	server := seedRefreshServer(&calls)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithPollingFallback(false),
+		WithInitialFlags(initialFlags),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	atomic.StoreInt32(&calls, 0)
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+
+	// The seed is answered at once, while the flags are fetched in the background
+	flag := provider.EvaluateFlag("dark-mode", evalCtx)
+	if flag == nil || flag.Value != false || flag.Variant != "off" {
+		t.Errorf("Expected the seeded dark-mode, got %+v", flag)
+	}
+	waitForSeedDropped(t, provider, "dark-mode")
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected the seeded flags to be fetched once, got %d requests", n)
+	}
+
+	// From then on the flag is evaluated live, for every context
+	if flag := provider.EvaluateFlag("dark-mode", evalCtx); flag == nil || flag.Value != true {
+		t.Errorf("Expected the live dark-mode once fetched, got %+v", flag)
+	}
+	result := provider.BooleanEvaluation(context.Background(), "dark-mode", false, openfeature.FlattenedContext{"targetingKey": "user-2"})
+	if !result.Value || result.Reason == openfeature.CachedReason {
+		t.Errorf("Expected the live value for another context, got %v (%s)", result.Value, result.Reason)
+	}
+}
+
+func TestWithInitialFlags_RefreshedOnce(t *testing.T) {
+	var calls int32
+	server := seedRefreshServer(&calls)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithPollingFallback(false),
+		WithInitialFlags(initialFlags),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	// Before Init there is nothing to tie a refresh to
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	provider.EvaluateFlag("welcome", evalCtx)
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Fatalf("Expected no refresh before Init, got %d requests", n)
+	}
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	atomic.StoreInt32(&calls, 0)
+
+	// welcome is absent from the response, so it stays seeded
+	for i := 0; i < 20; i++ {
+		if flag := provider.EvaluateFlag("welcome", evalCtx); flag == nil || flag.Value != "hi" {
+			t.Fatalf("Expected the seeded welcome, got %+v", flag)
+		}
+	}
+	waitForSeedDropped(t, provider, "dark-mode")
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected a single refresh, got %d requests", n)
+	}
+}
+
+func TestWithInitialFlags_TypedEvaluationServesSeed(t *testing.T) {
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(unreachableURL()),
+		WithRealtime(false),
+		WithInitialFlags(initialFlags),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	result := provider.BooleanEvaluation(context.Background(), "dark-mode", true, evalCtx)
+	if result.Value || result.Reason != openfeature.CachedReason {
+		t.Errorf("Expected seeded value false with reason CACHED, got %v (%s)", result.Value, result.Reason)
+	}
+	if got := provider.StringEvaluation(context.Background(), "welcome", "", evalCtx).Value; got != "hi" {
+		t.Errorf("Expected seeded 'hi', got %q", got)
+	}
+}
+
+func TestWithInitialFlags_BulkFetchReplacesSeeds(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"flags": []interface{}{
+			map[string]interface{}{"key": "dark-mode", "value": true},
+		}}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithInitialFlags(initialFlags),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+
+	if _, ok := provider.initialFlags.get("dark-mode"); ok {
+		t.Error("Expected the fetched dark-mode to replace its seed")
+	}
+	if _, ok := provider.initialFlags.get("welcome"); !ok {
+		t.Error("Expected welcome, absent from the response, to stay seeded")
+	}
+}
+
+func TestWithInitialFlags_ChangeReplacesSeed(t *testing.T) {
+	var calls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("dark-mode", countingFlag("dark-mode", &calls))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithInitialFlags(initialFlags),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.handleFlagChange(FlagChangeEvent{FlagKey: "dark-mode"})
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	flag := provider.EvaluateFlag("dark-mode", evalCtx)
+	if flag == nil || flag.Value != true {
+		t.Errorf("Expected the live dark-mode after the change, got %+v", flag)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected 1 request after the change, got %d", n)
+	}
+
+	if flag := provider.EvaluateFlag("welcome", evalCtx); flag == nil || flag.Value != "hi" {
+		t.Errorf("Expected the unchanged welcome to stay seeded, got %+v", flag)
+	}
+}
+
+func TestWithInitialFlags_FallsBackToSeedWhenUnreachable(t *testing.T) {
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(unreachableURL()),
+		WithRealtime(false),
+		WithInitialFlags(initialFlags),
+		WithBootstrap([]FlagEvaluation{{Key: "welcome", Value: "bootstrapped", ValueType: "string"}}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Expected Init to succeed with initial flags, got: %v", err)
+	}
+	if provider.Status() != openfeature.StaleState {
+		t.Errorf("Expected status %q, got %q", openfeature.StaleState, provider.Status())
+	}
+
+	// A bulk change replaces every seed, but the server cannot be reached
+	provider.handleFlagChange(FlagChangeEvent{})
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	if flag := provider.EvaluateFlag("dark-mode", evalCtx); flag == nil || flag.Value != false {
+		t.Errorf("Expected the seeded dark-mode as fallback, got %+v", flag)
+	}
+	if got := provider.StringEvaluation(context.Background(), "welcome", "", evalCtx).Value; got != "bootstrapped" {
+		t.Errorf("Expected the WithBootstrap value to take precedence, got %q", got)
+	}
+}
+
+func TestWithInitialFlagsJSON_ParsesBulkResponse(t *testing.T) {
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(unreachableURL()),
+		WithRealtime(false),
+		WithInitialFlagsJSON([]byte(`{"flags":[{"key":"max-items","value":25,"variant":"default"}]}`)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	result := provider.IntEvaluation(context.Background(), "max-items", 0, openfeature.FlattenedContext{})
+	if result.Value != 25 || result.Variant != "default" {
+		t.Errorf("Expected seeded 25 (default), got %d (%s)", result.Value, result.Variant)
+	}
+}
+
+func TestWithInitialFlagsJSON_InvalidFailsNewProvider(t *testing.T) {
+	_, err := NewProvider("test-api-key",
+		WithRealtime(false),
+		WithInitialFlagsJSON([]byte(`{"flags":`)),
+	)
+	if err == nil {
+		t.Error("Expected NewProvider to fail on an unparsable initial flags response")
+	}
+}
@@ -0,0 +1,63 @@
+package flipswitch
+
+import (
+	"sort"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// InMemoryProvider is a FlipswitchProvider that serves flags from memory,
+// for unit tests of code that evaluates flags. It makes no requests: Init
+// always succeeds, and every evaluation method, typed or direct, reads the
+// flags given to NewInMemoryProvider and SetFlag.
+type InMemoryProvider struct {
+	*FlipswitchProvider
+}
+
+// NewInMemoryProvider returns an InMemoryProvider serving flags, keyed by
+// flag key. Each flag's ValueType is inferred from its value as it would be
+// for a server response without type metadata, and its reason is STATIC.
+func NewInMemoryProvider(flags map[string]interface{}) *InMemoryProvider {
+	keys := make([]string, 0, len(flags))
+	for key := range flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	evals := make([]FlagEvaluation, 0, len(keys))
+	for _, key := range keys {
+		evals = append(evals, inMemoryFlag(key, flags[key]))
+	}
+
+	provider, err := NewProvider("in-memory",
+		WithOfflineMode(true),
+		WithRealtime(false),
+		WithBootstrap(evals),
+	)
+	if err != nil {
+		// None of the options above can fail
+		panic("flipswitch: creating in-memory provider: " + err.Error())
+	}
+	return &InMemoryProvider{FlipswitchProvider: provider}
+}
+
+// SetFlag sets the value of flag key, adding the flag if needed, and
+// notifies flag change listeners as an SSE flag-updated event would, so
+// real-time code paths can be tested.
+func (p *InMemoryProvider) SetFlag(key string, value interface{}) {
+	p.bootstrap.put(inMemoryFlag(key, value))
+	p.handleFlagChange(FlagChangeEvent{
+		FlagKey:   key,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// inMemoryFlag is the evaluation served for a flag set in memory.
+func inMemoryFlag(key string, value interface{}) FlagEvaluation {
+	return FlagEvaluation{
+		Key:       key,
+		Value:     value,
+		ValueType: getFlagType(map[string]interface{}{"value": value}),
+		Reason:    string(openfeature.StaticReason),
+	}
+}
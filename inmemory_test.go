@@ -0,0 +1,117 @@
+package flipswitch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestInMemoryProvider_ServesFlags(t *testing.T) {
+	provider := NewInMemoryProvider(map[string]interface{}{
+		"dark-mode": true,
+		"theme":     "blue",
+		"limit":     10,
+		"ratio":     0.5,
+		"config":    map[string]interface{}{"a": 1.0},
+	})
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Expected Init to succeed without a server, got %v", err)
+	}
+
+	ctx := context.Background()
+	if got := provider.BooleanEvaluation(ctx, "dark-mode", false, nil); got.Value != true {
+		t.Errorf("Expected true, got %+v", got)
+	}
+	if got := provider.StringEvaluation(ctx, "theme", "", nil); got.Value != "blue" {
+		t.Errorf("Expected blue, got %+v", got)
+	}
+	if got := provider.IntEvaluation(ctx, "limit", 0, nil); got.Value != 10 {
+		t.Errorf("Expected 10, got %+v", got)
+	}
+	if got := provider.FloatEvaluation(ctx, "ratio", 0, nil); got.Value != 0.5 {
+		t.Errorf("Expected 0.5, got %+v", got)
+	}
+	if got := provider.StringEvaluation(ctx, "limit", "fallback", nil); got.Value != "fallback" || got.ResolutionDetail().ErrorCode != openfeature.TypeMismatchCode {
+		t.Errorf("Expected a type mismatch, got %+v", got)
+	}
+	if got := provider.BooleanEvaluation(ctx, "missing", true, nil); got.Value != true || got.ResolutionDetail().ErrorCode != openfeature.FlagNotFoundCode {
+		t.Errorf("Expected FLAG_NOT_FOUND, got %+v", got)
+	}
+
+	flag := provider.EvaluateFlag("limit", openfeature.FlattenedContext{})
+	if flag == nil || flag.ValueType != "integer" || flag.AsInt() != 10 || flag.Reason != "STATIC" {
+		t.Errorf("Unexpected evaluation %+v", flag)
+	}
+	if flag := provider.EvaluateFlag("missing", openfeature.FlattenedContext{}); flag != nil {
+		t.Errorf("Expected nil for a missing flag, got %+v", flag)
+	}
+
+	flags := provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+	wantTypes := map[string]string{"config": "object", "dark-mode": "boolean", "limit": "integer", "ratio": "number", "theme": "string"}
+	if len(flags) != len(wantTypes) {
+		t.Fatalf("Expected %d flags, got %+v", len(wantTypes), flags)
+	}
+	for _, flag := range flags {
+		if flag.ValueType != wantTypes[flag.Key] {
+			t.Errorf("%s: expected type %s, got %s", flag.Key, wantTypes[flag.Key], flag.ValueType)
+		}
+	}
+}
+
+func TestInMemoryProvider_SetFlagNotifiesListeners(t *testing.T) {
+	provider := NewInMemoryProvider(map[string]interface{}{"theme": "blue"})
+	defer provider.Shutdown()
+
+	events := make(chan FlagChangeEvent, 10)
+	provider.AddFlagChangeListener(func(event FlagChangeEvent) {
+		events <- event
+	})
+	keyEvents := make(chan FlagChangeEvent, 10)
+	provider.AddFlagKeyChangeListener("theme", func(event FlagChangeEvent) {
+		keyEvents <- event
+	})
+
+	provider.SetFlag("theme", "green")
+
+	for _, ch := range []chan FlagChangeEvent{events, keyEvents} {
+		select {
+		case event := <-ch:
+			if event.FlagKey != "theme" {
+				t.Errorf("Expected an event for theme, got %+v", event)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the change event")
+		}
+	}
+	if got := provider.StringEvaluation(context.Background(), "theme", "", nil); got.Value != "green" {
+		t.Errorf("Expected the new value, got %+v", got)
+	}
+
+	provider.SetFlag("beta", true)
+	if flag := provider.EvaluateFlag("beta", openfeature.FlattenedContext{}); flag == nil || flag.Value != true {
+		t.Errorf("Expected the added flag, got %+v", flag)
+	}
+	if flags := provider.EvaluateAllFlags(openfeature.FlattenedContext{}); len(flags) != 2 {
+		t.Errorf("Expected 2 flags, got %+v", flags)
+	}
+}
+
+func TestInMemoryProvider_WithOpenFeatureClient(t *testing.T) {
+	provider := NewInMemoryProvider(map[string]interface{}{"dark-mode": true})
+
+	domain := "in-memory-provider"
+	if err := openfeature.SetNamedProviderAndWait(domain, provider); err != nil {
+		t.Fatalf("Failed to set provider: %v", err)
+	}
+	defer openfeature.SetNamedProviderAndWait(domain, openfeature.NoopProvider{})
+
+	client := openfeature.NewClient(domain)
+	value, err := client.BooleanValue(context.Background(), "dark-mode", false, openfeature.EvaluationContext{})
+	if err != nil || !value {
+		t.Errorf("Expected true, got %v, %v", value, err)
+	}
+}
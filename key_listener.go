@@ -0,0 +1,61 @@
+package flipswitch
+
+// KeyListenerOption configures a listener added with
+// AddFlagKeyChangeListenerOpts.
+type KeyListenerOption func(*keyListener)
+
+// keyListener is a flag change listener registered for a single flag key.
+type keyListener struct {
+	handler FlagChangeHandler
+
+	// Skip bulk invalidations that do not name the key
+	exactOnly bool
+}
+
+// OnlyExactKey makes a key listener fire only for changes that name its
+// key, either as the FlagKey of a targeted change or among the AffectedKeys
+// of a bulk invalidation. Bulk invalidations that do not say which flags
+// changed, such as config-updated events, are skipped.
+func OnlyExactKey() KeyListenerOption {
+	return func(l *keyListener) {
+		l.exactOnly = true
+	}
+}
+
+// AddFlagKeyChangeListenerOpts is AddFlagKeyChangeListener with options.
+// Without options it behaves exactly like AddFlagKeyChangeListener.
+// Returns a CancelFunc that removes the listener when called.
+func (p *FlipswitchProvider) AddFlagKeyChangeListenerOpts(flagKey string, handler FlagChangeHandler, opts ...KeyListenerOption) CancelFunc {
+	listener := keyListener{handler: handler}
+	for _, opt := range opts {
+		opt(&listener)
+	}
+
+	p.mu.Lock()
+	id := p.nextListenerID
+	p.nextListenerID++
+	if _, ok := p.keyFlagChangeListeners[flagKey]; !ok {
+		p.keyFlagChangeListeners[flagKey] = make(map[int]keyListener)
+	}
+	p.keyFlagChangeListeners[flagKey][id] = listener
+	exceeded := p.listenerAdded()
+	p.mu.Unlock()
+
+	if exceeded {
+		p.warnListenerLimit()
+	}
+
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if listeners, ok := p.keyFlagChangeListeners[flagKey]; ok {
+			if _, ok := listeners[id]; ok {
+				delete(listeners, id)
+				p.listenerRemoved()
+			}
+			if len(listeners) == 0 {
+				delete(p.keyFlagChangeListeners, flagKey)
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package flipswitch
+
+import (
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// keyListenerCalls registers a "dark-mode" listener with opts and returns
+// how often it fired for each of: a targeted change to dark-mode, a targeted
+// change to another flag, a bulk invalidation naming dark-mode and an
+// unscoped bulk invalidation.
+func keyListenerCalls(t *testing.T, opts ...KeyListenerOption) []int {
+	t.Helper()
+	server := httptest.NewServer(NewTestDispatcher())
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	calls := 0
+	provider.AddFlagKeyChangeListenerOpts("dark-mode", func(FlagChangeEvent) {
+		calls++
+	}, opts...)
+
+	var counts []int
+	for _, event := range []FlagChangeEvent{
+		{FlagKey: "dark-mode"},
+		{FlagKey: "other-flag"},
+		{AffectedKeys: []string{"other-flag", "dark-mode"}},
+		{},
+	} {
+		calls = 0
+		provider.handleFlagChange(event)
+		counts = append(counts, calls)
+	}
+	return counts
+}
+
+func TestAddFlagKeyChangeListenerOpts_DefaultIncludesBulk(t *testing.T) {
+	want := []int{1, 0, 1, 1}
+	if got := keyListenerCalls(t); !slices.Equal(got, want) {
+		t.Errorf("Expected calls %v, got %v", want, got)
+	}
+}
+
+func TestAddFlagKeyChangeListenerOpts_OnlyExactKeySkipsUnscopedBulk(t *testing.T) {
+	want := []int{1, 0, 1, 0}
+	if got := keyListenerCalls(t, OnlyExactKey()); !slices.Equal(got, want) {
+		t.Errorf("Expected calls %v, got %v", want, got)
+	}
+}
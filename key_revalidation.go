@@ -0,0 +1,95 @@
+package flipswitch
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// WithKeyRevalidationInterval re-validates the API key every interval after
+// Init. The SSE connection is only authenticated when it is opened, so a key
+// revoked server-side would otherwise keep receiving updates. When the server
+// rejects the key with 401 or 403, the provider moves to the FATAL state,
+// emits a PROVIDER_ERROR event with error code PROVIDER_FATAL (which
+// OpenFeature reports to fatal handlers) and closes the SSE connection.
+// Other failures, such as the server being unreachable, are logged and the
+// key is checked again at the next interval. Zero, the default, disables
+// re-validation.
+func WithKeyRevalidationInterval(interval time.Duration) Option {
+	return func(p *FlipswitchProvider) {
+		p.keyRevalidationInterval = interval
+	}
+}
+
+// startKeyRevalidation starts the re-validation loop if it is configured.
+func (p *FlipswitchProvider) startKeyRevalidation() {
+	if p.keyRevalidationInterval <= 0 {
+		return
+	}
+
+	p.mu.Lock()
+	if p.keyRevalidationDone != nil {
+		p.mu.Unlock()
+		return
+	}
+	done := make(chan struct{})
+	p.keyRevalidationDone = done
+	p.mu.Unlock()
+
+	go func() {
+		ticker := p.clock.NewTicker(p.keyRevalidationInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.Chan():
+				err := p.validateAPIKey(context.Background())
+				if errors.Is(err, ErrInvalidAPIKey) {
+					p.fatal("API key was rejected by Flipswitch")
+					return
+				}
+				if err != nil {
+					p.logger.Warnw("API key re-validation failed", errorFields(err)...)
+				}
+			}
+		}
+	}()
+}
+
+// stopKeyRevalidation stops the re-validation loop, if it is running.
+func (p *FlipswitchProvider) stopKeyRevalidation() {
+	p.mu.Lock()
+	done := p.keyRevalidationDone
+	p.keyRevalidationDone = nil
+	p.mu.Unlock()
+
+	if done != nil {
+		close(done)
+	}
+}
+
+// fatal moves the provider to the FATAL state and stops all background
+// activity, so that nothing more is served with a dead API key.
+func (p *FlipswitchProvider) fatal(message string) {
+	p.stopPolling()
+	p.stopKeyRevalidation()
+
+	p.mu.Lock()
+	client := p.sseClient
+	p.sseClient = nil
+	p.status = openfeature.FatalState
+	p.mu.Unlock()
+
+	if client != nil {
+		client.Close()
+	}
+
+	p.logger.Errorw("Provider is in a fatal state", "reason", message)
+	p.emitEventDetails(openfeature.ProviderError, openfeature.ProviderEventDetails{
+		Message:   message,
+		ErrorCode: openfeature.ProviderFatalCode,
+	})
+}
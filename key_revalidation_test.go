@@ -0,0 +1,150 @@
+package flipswitch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// revocableKeyServer accepts the API key until revoke is called, after which
+// the bulk endpoint used for key validation returns 401.
+func revocableKeyServer() (server *httptest.Server, revoke func()) {
+	var revoked int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		if atomic.LoadInt32(&revoked) == 1 {
+			return 401, map[string]interface{}{}
+		}
+		return 200, map[string]interface{}{"flags": []interface{}{}}
+	})
+	dispatcher.SetSseHandler(serveSseKeepAlive)
+	return httptest.NewServer(dispatcher), func() { atomic.StoreInt32(&revoked, 1) }
+}
+
+func TestKeyRevalidation_RevokedKeyIsFatal(t *testing.T) {
+	server, revoke := revocableKeyServer()
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(true),
+		WithKeyRevalidationInterval(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if provider.Status() != openfeature.ReadyState {
+		t.Fatalf("Expected READY after init, got %s", provider.Status())
+	}
+
+	revoke()
+
+	// Skip the events reporting readiness and the state of the SSE connection
+	timeout := time.After(5 * time.Second)
+	for fatal := false; !fatal; {
+		select {
+		case event := <-provider.EventChannel():
+			fatal = event.EventType == openfeature.ProviderError && event.ErrorCode == openfeature.ProviderFatalCode
+		case <-timeout:
+			t.Fatal("timed out waiting for the provider to go fatal")
+		}
+	}
+
+	if provider.Status() != openfeature.FatalState {
+		t.Errorf("Expected FATAL, got %s", provider.Status())
+	}
+	if status := provider.GetSseStatus(); status != StatusDisconnected {
+		t.Errorf("Expected SSE to be torn down, got %s", status)
+	}
+}
+
+func TestKeyRevalidation_TransientErrorsAreNotFatal(t *testing.T) {
+	var failing int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		if atomic.LoadInt32(&failing) == 1 {
+			return http.StatusServiceUnavailable, map[string]interface{}{}
+		}
+		return 200, map[string]interface{}{"flags": []interface{}{}}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithKeyRevalidationInterval(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	atomic.StoreInt32(&failing, 1)
+	time.Sleep(100 * time.Millisecond)
+
+	if provider.Status() != openfeature.ReadyState {
+		t.Errorf("Expected READY despite failed re-validation, got %s", provider.Status())
+	}
+}
+
+func TestSseUnauthorized_ProviderIsFatal(t *testing.T) {
+	var sseConnections int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&sseConnections, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithSseRetryBounds(10*time.Millisecond, 10*time.Millisecond),
+		WithMaxSseRetries(1),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for fatal := false; !fatal; {
+		select {
+		case event := <-provider.EventChannel():
+			fatal = event.EventType == openfeature.ProviderError && event.ErrorCode == openfeature.ProviderFatalCode
+		case <-timeout:
+			t.Fatal("timed out waiting for the provider to go fatal")
+		}
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if provider.Status() != openfeature.FatalState {
+		t.Errorf("Expected FATAL, got %s", provider.Status())
+	}
+	if n := atomic.LoadInt32(&sseConnections); n != 1 {
+		t.Errorf("Expected a single SSE connection attempt, got %d", n)
+	}
+	if provider.IsPollingActive() {
+		t.Error("Expected no polling fallback with a rejected API key")
+	}
+}
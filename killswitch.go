@@ -0,0 +1,74 @@
+package flipswitch
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// WithKillSwitch makes masterFlagKey a kill switch for dependentKeys. Before
+// a dependent boolean flag is evaluated, the master is evaluated for the same
+// context; if it is false, the dependent resolves to false locally with
+// reason DISABLED and no request is made for it. The master is evaluated like
+// any other flag, so with WithCache it is served from the cache. If the
+// master cannot be evaluated, dependents are evaluated normally. May be given
+// several times for independent kill switches. A master may not itself be a
+// dependent, so kill switches cannot be chained or form a cycle; NewProvider
+// returns an error if one is.
+func WithKillSwitch(masterFlagKey string, dependentKeys []string) Option {
+	return func(p *FlipswitchProvider) {
+		if p.killSwitches == nil {
+			p.killSwitches = make(map[string]string)
+		}
+		for _, key := range dependentKeys {
+			if key != masterFlagKey {
+				p.killSwitches[key] = masterFlagKey
+			}
+		}
+	}
+}
+
+// checkKillSwitches rejects a master that is itself the dependent of another
+// kill switch, as resolving it would recurse through the chain, forever if it
+// is a cycle.
+func (p *FlipswitchProvider) checkKillSwitches() error {
+	dependents := make([]string, 0, len(p.killSwitches))
+	for key := range p.killSwitches {
+		dependents = append(dependents, key)
+	}
+	slices.Sort(dependents)
+	for _, key := range dependents {
+		master := p.killSwitches[key]
+		if outer, ok := p.killSwitches[master]; ok {
+			return fmt.Errorf("kill switch %q for %q is itself a dependent of %q", master, key, outer)
+		}
+	}
+	return nil
+}
+
+// killed reports whether flagKey is a dependent whose kill switch evaluates
+// to false for evalCtx.
+func (p *FlipswitchProvider) killed(ctx context.Context, flagKey string, evalCtx openfeature.FlattenedContext) bool {
+	master, ok := p.killSwitches[flagKey]
+	if !ok {
+		return false
+	}
+	eval, err := p.evaluateFlag(ctx, master, evalCtx)
+	if err != nil {
+		return false
+	}
+	on, isBool := eval.Value.(bool)
+	return isBool && !on
+}
+
+// killedEvaluation is the result served for a flag disabled by its kill switch.
+func killedEvaluation(flagKey string) *FlagEvaluation {
+	return &FlagEvaluation{
+		Key:       flagKey,
+		Value:     false,
+		ValueType: "boolean",
+		Reason:    string(openfeature.DisabledReason),
+	}
+}
@@ -0,0 +1,142 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// killSwitchServer serves a master kill switch with the given value and a
+// dependent flag "checkout" that is on, counting requests for each.
+func killSwitchServer(master bool) (server *httptest.Server, masterCalls, dependentCalls *int32) {
+	masterCalls, dependentCalls = new(int32), new(int32)
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("master", func() (int, map[string]interface{}) {
+		atomic.AddInt32(masterCalls, 1)
+		return 200, map[string]interface{}{"key": "master", "value": master}
+	})
+	dispatcher.SetFlagResponse("checkout", func() (int, map[string]interface{}) {
+		atomic.AddInt32(dependentCalls, 1)
+		return 200, map[string]interface{}{"key": "checkout", "value": true, "reason": "TARGETING_MATCH"}
+	})
+	dispatcher.SetFlagResponse("unrelated", countingFlag("unrelated", new(int32)))
+	return httptest.NewServer(dispatcher), masterCalls, dependentCalls
+}
+
+func createKillSwitchProvider(t *testing.T, server *httptest.Server, opts ...Option) *FlipswitchProvider {
+	t.Helper()
+	provider, err := NewProvider(
+		"test-api-key",
+		append([]Option{
+			WithBaseURL(server.URL),
+			WithRealtime(false),
+			WithKillSwitch("master", []string{"checkout"}),
+		}, opts...)...,
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	return provider
+}
+
+func TestKillSwitch_OffForcesDependentsFalse(t *testing.T) {
+	server, _, dependentCalls := killSwitchServer(false)
+	defer server.Close()
+
+	provider := createKillSwitchProvider(t, server)
+	defer provider.Shutdown()
+
+	result := provider.EvaluateFlag("checkout", openfeature.FlattenedContext{})
+	if result == nil || result.AsBoolean() {
+		t.Fatalf("Expected checkout to be forced false, got %+v", result)
+	}
+	if result.Reason != string(openfeature.DisabledReason) {
+		t.Errorf("Expected reason DISABLED, got %q", result.Reason)
+	}
+
+	detail := provider.BooleanEvaluation(context.Background(), "checkout", true, openfeature.FlattenedContext{})
+	if detail.Value {
+		t.Error("Expected BooleanEvaluation to return false")
+	}
+	if detail.Reason != openfeature.DisabledReason {
+		t.Errorf("Expected reason DISABLED, got %q", detail.Reason)
+	}
+
+	if got := atomic.LoadInt32(dependentCalls); got != 0 {
+		t.Errorf("Expected no requests for the dependent flag, got %d", got)
+	}
+}
+
+func TestKillSwitch_OnEvaluatesDependentsNormally(t *testing.T) {
+	server, _, dependentCalls := killSwitchServer(true)
+	defer server.Close()
+
+	provider := createKillSwitchProvider(t, server)
+	defer provider.Shutdown()
+
+	result := provider.EvaluateFlag("checkout", openfeature.FlattenedContext{})
+	if result == nil || !result.AsBoolean() {
+		t.Fatalf("Expected checkout to be true, got %+v", result)
+	}
+	if result.Reason != "TARGETING_MATCH" {
+		t.Errorf("Expected the server's reason, got %q", result.Reason)
+	}
+	if got := atomic.LoadInt32(dependentCalls); got != 1 {
+		t.Errorf("Expected 1 request for the dependent flag, got %d", got)
+	}
+}
+
+func TestKillSwitch_DoesNotAffectOtherFlags(t *testing.T) {
+	server, masterCalls, _ := killSwitchServer(false)
+	defer server.Close()
+
+	provider := createKillSwitchProvider(t, server)
+	defer provider.Shutdown()
+
+	result := provider.EvaluateFlag("unrelated", openfeature.FlattenedContext{})
+	if result == nil || !result.AsBoolean() {
+		t.Errorf("Expected unrelated flag to evaluate normally, got %+v", result)
+	}
+	if got := atomic.LoadInt32(masterCalls); got != 0 {
+		t.Errorf("Expected the master not to be evaluated, got %d requests", got)
+	}
+}
+
+func TestKillSwitch_MasterIsCached(t *testing.T) {
+	server, masterCalls, _ := killSwitchServer(false)
+	defer server.Close()
+
+	provider := createKillSwitchProvider(t, server, WithCache(time.Minute))
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("checkout", openfeature.FlattenedContext{})
+	provider.BooleanEvaluation(context.Background(), "checkout", true, openfeature.FlattenedContext{})
+
+	if got := atomic.LoadInt32(masterCalls); got != 1 {
+		t.Errorf("Expected the master to be fetched once, got %d", got)
+	}
+}
+
+func TestKillSwitch_ChainsAndCyclesAreRejected(t *testing.T) {
+	tests := map[string][]Option{
+		"chain": {
+			WithKillSwitch("master", []string{"checkout"}),
+			WithKillSwitch("checkout", []string{"payment"}),
+		},
+		"cycle": {
+			WithKillSwitch("a", []string{"b"}),
+			WithKillSwitch("b", []string{"a"}),
+		},
+	}
+	for name, opts := range tests {
+		provider, err := NewProvider("test-api-key", append([]Option{WithRealtime(false)}, opts...)...)
+		if err == nil {
+			provider.Shutdown()
+			t.Errorf("%s: expected NewProvider to fail", name)
+		}
+	}
+}
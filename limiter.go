@@ -0,0 +1,55 @@
+package flipswitch
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// evaluationLimiter bounds the number of in-flight evaluation requests.
+// A nil limiter imposes no bound.
+type evaluationLimiter chan struct{}
+
+// WithMaxConcurrentEvaluations bounds the number of direct evaluation
+// requests in flight at once. Excess requests wait for a free slot until
+// their context is done. Zero or a negative value means no limit, which is
+// the default.
+func WithMaxConcurrentEvaluations(n int) Option {
+	return func(p *FlipswitchProvider) {
+		if n <= 0 {
+			p.limiter = nil
+			return
+		}
+		p.limiter = make(evaluationLimiter, n)
+	}
+}
+
+// acquire waits for a free slot and returns a function that releases it.
+func (l evaluationLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	select {
+	case l <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-l })
+	}, nil
+}
+
+// releasingBody runs release when the response body is closed, so the
+// resources held by a request attempt stay held while the caller reads the
+// response.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,140 @@
+package flipswitch
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// blockingFlag returns a flag response func that signals started and then
+// blocks until release is closed.
+func blockingFlag(flagKey string, started chan<- string, release <-chan struct{}) func() (int, map[string]interface{}) {
+	return func() (int, map[string]interface{}) {
+		started <- flagKey
+		<-release
+		return 200, map[string]interface{}{"key": flagKey, "value": true}
+	}
+}
+
+func TestMaxConcurrentEvaluations_QueuesExcessCalls(t *testing.T) {
+	started := make(chan string, 2)
+	release := make(chan struct{})
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("flag-a", blockingFlag("flag-a", started, release))
+	dispatcher.SetFlagResponse("flag-b", blockingFlag("flag-b", started, release))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithMaxConcurrentEvaluations(1),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	done := make(chan *FlagEvaluation, 2)
+	go func() { done <- provider.EvaluateFlag("flag-a", openfeature.FlattenedContext{}) }()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for first request")
+	}
+
+	go func() { done <- provider.EvaluateFlag("flag-b", openfeature.FlattenedContext{}) }()
+
+	select {
+	case key := <-started:
+		t.Fatalf("Expected second call to wait, but %s reached the server", key)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case key := <-started:
+		if key != "flag-b" {
+			t.Errorf("Expected flag-b to start after the first call, got %s", key)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for queued request")
+	}
+
+	for i := 0; i < 2; i++ {
+		if result := <-done; result == nil || result.Value != true {
+			t.Errorf("Expected successful evaluation, got %+v", result)
+		}
+	}
+}
+
+func TestMaxConcurrentEvaluations_CancelledContextReleasesQueuedCall(t *testing.T) {
+	started := make(chan string, 2)
+	release := make(chan struct{})
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("flag-a", blockingFlag("flag-a", started, release))
+	dispatcher.SetFlagResponse("flag-b", blockingFlag("flag-b", started, release))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+	// Unblock the handler before the server waits for it to finish
+	defer close(release)
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithMaxConcurrentEvaluations(1),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	go provider.EvaluateFlag("flag-a", openfeature.FlattenedContext{})
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for first request")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := provider.evaluateFlag(ctx, "flag-b", openfeature.FlattenedContext{})
+		errCh <- err
+	}()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Queued call was not released by its context")
+	}
+
+	select {
+	case key := <-started:
+		t.Errorf("Expected cancelled call never to reach the server, but %s did", key)
+	default:
+	}
+}
+
+func TestMaxConcurrentEvaluations_UnlimitedByDefault(t *testing.T) {
+	provider, err := NewProvider("test-api-key", WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	if provider.limiter != nil {
+		t.Errorf("Expected no limiter by default")
+	}
+}
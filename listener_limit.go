@@ -0,0 +1,40 @@
+package flipswitch
+
+// WithMaxListeners logs a warning when more than n flag change listeners,
+// counting both AddFlagChangeListener and AddFlagKeyChangeListener
+// registrations, are registered at once. A count that keeps growing usually
+// means listeners are registered per request and their CancelFunc is never
+// called. The warning is repeated only after the count has dropped back to
+// n. Zero, the default, disables the check.
+func WithMaxListeners(n int) Option {
+	return func(p *FlipswitchProvider) {
+		if n >= 0 {
+			p.maxListeners = n
+		}
+	}
+}
+
+// listenerAdded counts a new flag change listener and reports whether the
+// limit set with WithMaxListeners has just been exceeded. p.mu must be held.
+func (p *FlipswitchProvider) listenerAdded() bool {
+	p.listenerCount++
+	if p.maxListeners == 0 || p.listenerCount <= p.maxListeners || p.listenerLimitWarned {
+		return false
+	}
+	p.listenerLimitWarned = true
+	return true
+}
+
+// listenerRemoved counts a removed flag change listener. p.mu must be held.
+func (p *FlipswitchProvider) listenerRemoved() {
+	p.listenerCount--
+	if p.listenerCount <= p.maxListeners {
+		p.listenerLimitWarned = false
+	}
+}
+
+// warnListenerLimit logs that the listener limit was exceeded.
+func (p *FlipswitchProvider) warnListenerLimit() {
+	p.logger.Warnw("Flag change listener count exceeds the configured maximum, listeners may be leaking",
+		"max", p.maxListeners)
+}
@@ -0,0 +1,100 @@
+package flipswitch
+
+import (
+	"strconv"
+	"testing"
+)
+
+const listenerLimitWarning = "Flag change listener count exceeds the configured maximum, listeners may be leaking"
+
+// countWarnings returns how many listener limit warnings logger recorded.
+func countWarnings(logger *recordingLogger) int {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	count := 0
+	for _, e := range logger.entries {
+		if e.msg == listenerLimitWarning {
+			count++
+		}
+	}
+	return count
+}
+
+func TestListeners_RegisterAndCancelLeavesNothingBehind(t *testing.T) {
+	provider, err := NewProvider("test-api-key", WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	cancels := make([]CancelFunc, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		if i%2 == 0 {
+			cancels = append(cancels, provider.AddFlagChangeListener(func(FlagChangeEvent) {}))
+		} else {
+			cancels = append(cancels, provider.AddFlagKeyChangeListener("flag-"+strconv.Itoa(i%10), func(FlagChangeEvent) {}))
+		}
+	}
+	for _, cancel := range cancels {
+		cancel()
+		cancel() // Cancelling twice does nothing
+	}
+
+	provider.mu.RLock()
+	defer provider.mu.RUnlock()
+	if len(provider.flagChangeListeners) != 0 || len(provider.keyFlagChangeListeners) != 0 {
+		t.Errorf("Expected no listeners, got %d global and %d keys",
+			len(provider.flagChangeListeners), len(provider.keyFlagChangeListeners))
+	}
+	if provider.listenerCount != 0 {
+		t.Errorf("Expected a listener count of 0, got %d", provider.listenerCount)
+	}
+}
+
+func TestWithMaxListeners_WarnsOnceWhenExceeded(t *testing.T) {
+	logger := &recordingLogger{}
+	provider, err := NewProvider("test-api-key", WithRealtime(false), WithLogger(logger), WithMaxListeners(2))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.AddFlagChangeListener(func(FlagChangeEvent) {})
+	cancel := provider.AddFlagKeyChangeListener("theme", func(FlagChangeEvent) {})
+	if n := countWarnings(logger); n != 0 {
+		t.Fatalf("Expected no warning at the limit, got %d", n)
+	}
+
+	id := provider.AddFlagChangeListenerWithID(func(FlagChangeEvent) {})
+	provider.AddFlagChangeListener(func(FlagChangeEvent) {})
+	if n := countWarnings(logger); n != 1 {
+		t.Fatalf("Expected one warning above the limit, got %d", n)
+	}
+	if entry, _ := logger.find(listenerLimitWarning); entry.level != "warn" || entry.fields["max"] != 2 {
+		t.Errorf("Expected a warning with the limit, got %+v", entry)
+	}
+
+	// Back at the limit, exceeding it again warns again
+	provider.RemoveFlagChangeListenerByID(id)
+	cancel()
+	provider.AddFlagChangeListener(func(FlagChangeEvent) {})
+	if n := countWarnings(logger); n != 2 {
+		t.Errorf("Expected a second warning, got %d", n)
+	}
+}
+
+func TestWithMaxListeners_DisabledByDefault(t *testing.T) {
+	logger := &recordingLogger{}
+	provider, err := NewProvider("test-api-key", WithRealtime(false), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	for i := 0; i < 100; i++ {
+		provider.AddFlagChangeListener(func(FlagChangeEvent) {})
+	}
+	if n := countWarnings(logger); n != 0 {
+		t.Errorf("Expected no warning, got %d", n)
+	}
+}
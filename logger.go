@@ -0,0 +1,88 @@
+package flipswitch
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Logger receives the SDK's internal log output. Each method takes a message
+// and alternating key-value pairs of structured fields, such as
+// "flagKey", "my-flag", "statusCode", 503.
+type Logger interface {
+	Debugw(msg string, kv ...any)
+	Infow(msg string, kv ...any)
+	Warnw(msg string, kv ...any)
+	Errorw(msg string, kv ...any)
+}
+
+// WithLogger routes the provider's log output, including that of its SSE
+// client, to logger. The default writes to the standard library logger.
+func WithLogger(logger Logger) Option {
+	return func(p *FlipswitchProvider) {
+		p.logger = logger
+	}
+}
+
+// WithSseLogger routes the SSE client's log output to logger.
+func WithSseLogger(logger Logger) SseOption {
+	return func(c *SseClient) {
+		c.logger = logger
+	}
+}
+
+// stdLogger is the default Logger. It writes to the standard library logger,
+// appending fields as key=value pairs, and drops debug output.
+type stdLogger struct{}
+
+func (stdLogger) Debugw(msg string, kv ...any) {}
+
+func (stdLogger) Infow(msg string, kv ...any) {
+	log.Print(formatLogLine("", msg, kv))
+}
+
+func (stdLogger) Warnw(msg string, kv ...any) {
+	log.Print(formatLogLine("WARN: ", msg, kv))
+}
+
+func (stdLogger) Errorw(msg string, kv ...any) {
+	log.Print(formatLogLine("ERROR: ", msg, kv))
+}
+
+func formatLogLine(level, msg string, kv []any) string {
+	var b strings.Builder
+	b.WriteString("[Flipswitch] ")
+	b.WriteString(level)
+	b.WriteString(msg)
+	for i := 0; i < len(kv); i += 2 {
+		b.WriteByte(' ')
+		b.WriteString(fmt.Sprint(kv[i]))
+		b.WriteByte('=')
+		if i+1 >= len(kv) {
+			b.WriteString("<missing>")
+			break
+		}
+		value := fmt.Sprint(kv[i+1])
+		if strings.ContainsAny(value, " \t\n\"=") {
+			value = fmt.Sprintf("%q", value)
+		}
+		b.WriteString(value)
+	}
+	return b.String()
+}
+
+// errorFields returns the structured fields describing err, including the
+// HTTP status code when the error carries one.
+func errorFields(err error) []any {
+	kv := []any{"error", err}
+	var se *HTTPStatusError
+	if errors.As(err, &se) {
+		kv = append(kv, "statusCode", se.StatusCode)
+	}
+	var sse *SseStatusError
+	if errors.As(err, &sse) {
+		kv = append(kv, "statusCode", sse.StatusCode)
+	}
+	return kv
+}
@@ -0,0 +1,196 @@
+package flipswitch
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+type logEntry struct {
+	level  string
+	msg    string
+	fields map[string]any
+}
+
+// recordingLogger is a Logger that keeps every entry for inspection.
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordingLogger) record(level, msg string, kv []any) {
+	fields := make(map[string]any, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		fields[kv[i].(string)] = kv[i+1]
+	}
+	l.mu.Lock()
+	l.entries = append(l.entries, logEntry{level: level, msg: msg, fields: fields})
+	l.mu.Unlock()
+}
+
+func (l *recordingLogger) Debugw(msg string, kv ...any) { l.record("debug", msg, kv) }
+func (l *recordingLogger) Infow(msg string, kv ...any)  { l.record("info", msg, kv) }
+func (l *recordingLogger) Warnw(msg string, kv ...any)  { l.record("warn", msg, kv) }
+func (l *recordingLogger) Errorw(msg string, kv ...any) { l.record("error", msg, kv) }
+
+// find returns the first entry with the given message.
+func (l *recordingLogger) find(msg string) (logEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, e := range l.entries {
+		if e.msg == msg {
+			return e, true
+		}
+	}
+	return logEntry{}, false
+}
+
+func TestLogger_SseConnectionErrorCarriesStatusCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	statusCh := make(chan ConnectionStatus, 10)
+	client := NewSseClient(server.URL, "test-key", nil, nil,
+		func(status ConnectionStatus) {
+			statusCh <- status
+		}, WithSseLogger(logger))
+	client.mu.Lock()
+	client.retryDelay = 10 * time.Second
+	client.mu.Unlock()
+	defer client.Close()
+
+	client.Connect()
+
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case s := <-statusCh:
+			if s != StatusError {
+				continue
+			}
+		case <-deadline:
+			t.Fatal("Timed out waiting for error status")
+		}
+		break
+	}
+
+	entry, ok := logger.find("SSE connection error")
+	if !ok {
+		t.Fatal("Expected an SSE connection error log entry")
+	}
+	if entry.level != "warn" {
+		t.Errorf("Expected warn level, got %s", entry.level)
+	}
+	if got := entry.fields["statusCode"]; got != http.StatusServiceUnavailable {
+		t.Errorf("Expected statusCode field 503, got %v", got)
+	}
+}
+
+func TestLogger_EvaluationErrorCarriesFlagKey(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", func() (int, map[string]interface{}) {
+		return 500, map[string]interface{}{}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithLogger(logger),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+
+	entry, ok := logger.find("Error evaluating flag")
+	if !ok {
+		t.Fatal("Expected an evaluation error log entry")
+	}
+	if got := entry.fields["flagKey"]; got != "my-flag" {
+		t.Errorf("Expected flagKey field 'my-flag', got %v", got)
+	}
+	if got := entry.fields["statusCode"]; got != 500 {
+		t.Errorf("Expected statusCode field 500, got %v", got)
+	}
+}
+
+func TestFormatLogLine(t *testing.T) {
+	tests := []struct {
+		level string
+		msg   string
+		kv    []any
+		want  string
+	}{
+		{"", "Provider initialized", []any{"realtime", true}, "[Flipswitch] Provider initialized realtime=true"},
+		{"WARN: ", "SSE connection error", []any{"statusCode", 503}, "[Flipswitch] WARN: SSE connection error statusCode=503"},
+		{"", "msg", []any{"error", "bad thing"}, `[Flipswitch] msg error="bad thing"`},
+		{"", "msg", []any{"orphan"}, "[Flipswitch] msg orphan=<missing>"},
+	}
+
+	for _, tt := range tests {
+		if got := formatLogLine(tt.level, tt.msg, tt.kv); got != tt.want {
+			t.Errorf("formatLogLine(%q, %q, %v) = %q, want %q", tt.level, tt.msg, tt.kv, got, tt.want)
+		}
+	}
+}
+
+func TestLogger_SseChatterIsDebug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, sseFrame("heartbeat", "{}"))
+		// Returning closes the stream cleanly, as a server rotation would
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	reconnecting := make(chan struct{}, 1)
+	client := NewSseClient(server.URL, "test-key", nil, nil,
+		func(status ConnectionStatus) {
+			if status == StatusDisconnected {
+				select {
+				case reconnecting <- struct{}{}:
+				default:
+				}
+			}
+		}, WithSseLogger(logger))
+	defer client.Close()
+
+	client.Connect()
+
+	select {
+	case <-reconnecting:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the stream to close")
+	}
+
+	for _, msg := range []string{"SSE connection established", "SSE heartbeat received", "SSE connection closed", "Scheduling SSE reconnect"} {
+		deadline := time.Now().Add(time.Second)
+		entry, ok := logger.find(msg)
+		for !ok && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+			entry, ok = logger.find(msg)
+		}
+		if !ok {
+			t.Errorf("Expected a %q log entry", msg)
+			continue
+		}
+		if entry.level != "debug" {
+			t.Errorf("Expected %q at debug level, got %s", msg, entry.level)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package flipswitch
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metric names registered by WithPrometheusRegisterer.
+const (
+	// MetricEvaluations counts flag evaluations, labelled by "flag".
+	MetricEvaluations = "flipswitch_evaluations_total"
+	// MetricEvaluationErrors counts evaluations that failed or resolved with
+	// an error, labelled by "flag".
+	MetricEvaluationErrors = "flipswitch_evaluation_errors_total"
+	// MetricEvaluationDuration is a histogram of evaluation latency in seconds.
+	MetricEvaluationDuration = "flipswitch_evaluation_duration_seconds"
+	// MetricSseReconnects counts SSE connection attempts after the first.
+	MetricSseReconnects = "flipswitch_sse_reconnects_total"
+	// MetricCacheHits counts evaluations served from the cache.
+	MetricCacheHits = "flipswitch_cache_hits_total"
+	// MetricCacheMisses counts cache lookups that had to go to the server.
+	// The hit ratio is hits / (hits + misses).
+	MetricCacheMisses = "flipswitch_cache_misses_total"
+)
+
+// prometheusMetrics is the prometheus.Collector registered by
+// WithPrometheusRegisterer. A nil collector records nothing.
+type prometheusMetrics struct {
+	evaluations   *prometheus.CounterVec
+	errors        *prometheus.CounterVec
+	duration      prometheus.Histogram
+	sseReconnects prometheus.Counter
+	cacheHits     prometheus.Counter
+	cacheMisses   prometheus.Counter
+}
+
+func newPrometheusMetrics() *prometheusMetrics {
+	return &prometheusMetrics{
+		evaluations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: MetricEvaluations,
+			Help: "Number of flag evaluations.",
+		}, []string{"flag"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: MetricEvaluationErrors,
+			Help: "Number of flag evaluations that failed.",
+		}, []string{"flag"}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    MetricEvaluationDuration,
+			Help:    "Flag evaluation latency in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		sseReconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: MetricSseReconnects,
+			Help: "Number of SSE reconnect attempts.",
+		}),
+		cacheHits: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: MetricCacheHits,
+			Help: "Number of evaluations served from the cache.",
+		}),
+		cacheMisses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: MetricCacheMisses,
+			Help: "Number of cache lookups that missed.",
+		}),
+	}
+}
+
+// collectors returns the metrics in the order they are described.
+func (m *prometheusMetrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.evaluations, m.errors, m.duration, m.sseReconnects, m.cacheHits, m.cacheMisses}
+}
+
+// Describe implements prometheus.Collector.
+func (m *prometheusMetrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range m.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (m *prometheusMetrics) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range m.collectors() {
+		c.Collect(ch)
+	}
+}
+
+// WithPrometheusRegisterer registers evaluation, error, SSE reconnect and
+// cache metrics on r, named by the Metric constants, so they are scraped
+// along with the application's other collectors. Several providers may
+// share a registry; they then update the same metrics. NewProvider returns
+// an error if the metrics cannot be registered, for example because other
+// collectors already use their names.
+func WithPrometheusRegisterer(r prometheus.Registerer) Option {
+	return func(p *FlipswitchProvider) {
+		p.metricsRegisterer = r
+	}
+}
+
+// registerMetrics registers the provider's metrics on the registerer set
+// with WithPrometheusRegisterer, reusing the metrics of another provider
+// registered there before.
+func (p *FlipswitchProvider) registerMetrics() error {
+	if p.metricsRegisterer == nil {
+		return nil
+	}
+	metrics := newPrometheusMetrics()
+	if err := p.metricsRegisterer.Register(metrics); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &registered) {
+			return fmt.Errorf("registering Prometheus metrics: %w", err)
+		}
+		existing, ok := registered.ExistingCollector.(*prometheusMetrics)
+		if !ok {
+			return fmt.Errorf("registering Prometheus metrics: %w", err)
+		}
+		metrics = existing
+	}
+	p.metrics = metrics
+	return nil
+}
+
+// observeEvaluation records one evaluation of flag that took d.
+func (m *prometheusMetrics) observeEvaluation(flag string, d time.Duration, failed bool) {
+	if m == nil {
+		return
+	}
+	m.evaluations.WithLabelValues(flag).Inc()
+	if failed {
+		m.errors.WithLabelValues(flag).Inc()
+	}
+	m.duration.Observe(d.Seconds())
+}
+
+// observeResolution records a typed evaluation of valueType that started at
+// start, in the metrics, the error history and with the evaluation observer.
+// It is called on return with the result's value and detail, and the source
+// the evaluation was served from.
+func (p *FlipswitchProvider) observeResolution(flag, valueType string, start time.Time, source EvaluationSource, value interface{}, detail *openfeature.ProviderResolutionDetail) {
+	p.observeTypedResolution(flag, valueType, start, source, value, detail)
+	if resolution := detail.ResolutionDetail(); resolution.ErrorCode != "" && resolution.ErrorCode != openfeature.FlagNotFoundCode {
+		p.recordError(OperationEvaluation, flag, errors.New(string(resolution.ErrorCode)+": "+resolution.ErrorMessage))
+	}
+	if p.metrics == nil {
+		return
+	}
+	failed := detail.Reason == openfeature.ErrorReason || detail.ResolutionDetail().ErrorCode != ""
+	p.metrics.observeEvaluation(flag, time.Since(start), failed)
+}
+
+// observeCache records a cache lookup.
+func (m *prometheusMetrics) observeCache(hit bool) {
+	if m == nil {
+		return
+	}
+	if hit {
+		m.cacheHits.Inc()
+	} else {
+		m.cacheMisses.Inc()
+	}
+}
+
+// observeSseReconnect records an SSE reconnect.
+func (m *prometheusMetrics) observeSseReconnect() {
+	if m == nil {
+		return
+	}
+	m.sseReconnects.Inc()
+}
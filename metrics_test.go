@@ -0,0 +1,178 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// scrape gathers the samples registered on reg, keyed by series in the
+// text exposition format.
+func scrape(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	samples := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			series := family.GetName()
+			if labels := metric.GetLabel(); len(labels) > 0 {
+				pairs := make([]string, len(labels))
+				for i, label := range labels {
+					pairs[i] = label.GetName() + `="` + label.GetValue() + `"`
+				}
+				series += "{" + strings.Join(pairs, ",") + "}"
+			}
+			switch {
+			case metric.Counter != nil:
+				samples[series] = metric.GetCounter().GetValue()
+			case metric.Histogram != nil:
+				samples[series+"_count"] = float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return samples
+}
+
+func TestPrometheusMetrics_RegistersAllMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if _, err := NewProvider("test-api-key", WithRealtime(false), WithPrometheusRegisterer(reg)); err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	samples := scrape(t, reg)
+
+	for _, series := range []string{
+		MetricEvaluationDuration + "_count",
+		MetricSseReconnects,
+		MetricCacheHits,
+		MetricCacheMisses,
+	} {
+		if value, ok := samples[series]; !ok || value != 0 {
+			t.Errorf("Expected %s to start at 0, got %v (present %v)", series, value, ok)
+		}
+	}
+}
+
+func TestPrometheusMetrics_SharedRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	first, err := NewProvider("test-api-key", WithRealtime(false), WithPrometheusRegisterer(reg))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	second, err := NewProvider("test-api-key", WithRealtime(false), WithPrometheusRegisterer(reg))
+	if err != nil {
+		t.Fatalf("Expected a second provider to share the registry, got %v", err)
+	}
+	if first.metrics != second.metrics {
+		t.Error("Expected both providers to update the same metrics")
+	}
+}
+
+func TestPrometheusMetrics_NameConflictFailsNewProvider(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{Name: MetricCacheHits, Help: "Taken."}))
+	if _, err := NewProvider("test-api-key", WithRealtime(false), WithPrometheusRegisterer(reg)); err == nil {
+		t.Error("Expected NewProvider to fail when a metric name is taken")
+	}
+}
+
+func TestPrometheusMetrics_CountsEvaluationsAndCache(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "my-flag", "value": true, "reason": "STATIC"}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	reg := prometheus.NewRegistry()
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithCache(time.Minute),
+		WithPrometheusRegisterer(reg),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	provider.EvaluateFlag("missing", openfeature.FlattenedContext{})
+	provider.BooleanEvaluation(context.Background(), "my-flag", false, openfeature.FlattenedContext{})
+
+	samples := scrape(t, reg)
+	want := map[string]float64{
+		MetricEvaluations + `{flag="my-flag"}`:      3,
+		MetricEvaluations + `{flag="missing"}`:      1,
+		MetricEvaluationErrors + `{flag="missing"}`: 1,
+		MetricEvaluationDuration + "_count":         4,
+		MetricCacheHits:                             2,
+		MetricCacheMisses:                           2,
+	}
+	for series, value := range want {
+		if samples[series] != value {
+			t.Errorf("Expected %s = %v, got %v", series, value, samples[series])
+		}
+	}
+	if _, ok := samples[MetricEvaluationErrors+`{flag="my-flag"}`]; ok {
+		t.Errorf("Expected no errors recorded for my-flag")
+	}
+}
+
+func TestPrometheusMetrics_CountsSseReconnects(t *testing.T) {
+	connected := make(chan struct{}, 2)
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		connected <- struct{}{}
+		serveSseKeepAlive(w, r)
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	reg := prometheus.NewRegistry()
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(true),
+		WithPrometheusRegisterer(reg),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for first SSE connection")
+	}
+	if got := scrape(t, reg)[MetricSseReconnects]; got != 0 {
+		t.Errorf("Expected no reconnects after the first connection, got %v", got)
+	}
+
+	provider.ReconnectSse()
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for SSE reconnection")
+	}
+	if got := scrape(t, reg)[MetricSseReconnects]; got != 1 {
+		t.Errorf("Expected 1 reconnect, got %v", got)
+	}
+}
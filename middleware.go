@@ -0,0 +1,37 @@
+package flipswitch
+
+import "net/http"
+
+// EvaluationMiddleware wraps the transport used for evaluation requests, for
+// cross-cutting concerns such as logging, metrics or header injection.
+type EvaluationMiddleware func(next http.RoundTripper) http.RoundTripper
+
+// WithEvaluationMiddleware wraps the transport of the evaluation HTTP client
+// with middleware. It may be given several times; middleware is applied in
+// order, so the first one registered sees each request first. The client
+// passed to WithHTTPClient is not modified.
+func WithEvaluationMiddleware(middleware EvaluationMiddleware) Option {
+	return func(p *FlipswitchProvider) {
+		p.middleware = append(p.middleware, middleware)
+	}
+}
+
+// applyMiddleware replaces the evaluation client with a copy whose transport
+// is wrapped by the configured middleware.
+func (p *FlipswitchProvider) applyMiddleware() {
+	if len(p.middleware) == 0 {
+		return
+	}
+
+	transport := p.httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(p.middleware) - 1; i >= 0; i-- {
+		transport = p.middleware[i](transport)
+	}
+
+	client := *p.httpClient
+	client.Transport = transport
+	p.httpClient = &client
+}
@@ -0,0 +1,105 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// recordingMiddleware appends name and the request path to calls and tags
+// the request with a header.
+func recordingMiddleware(name string, mu *sync.Mutex, calls *[]string) EvaluationMiddleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			mu.Lock()
+			*calls = append(*calls, name+" "+r.URL.Path)
+			mu.Unlock()
+			r = r.Clone(r.Context())
+			r.Header.Add("X-Middleware", name)
+			return next.RoundTrip(r)
+		})
+	}
+}
+
+func TestEvaluationMiddleware_WrapsSingleAndBulkCalls(t *testing.T) {
+	var headersMu sync.Mutex
+	var headers [][]string
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "my-flag", "value": true}
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headersMu.Lock()
+		headers = append(headers, r.Header.Values("X-Middleware"))
+		headersMu.Unlock()
+		dispatcher.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var calls []string
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithEvaluationMiddleware(recordingMiddleware("outer", &mu, &calls)),
+		WithEvaluationMiddleware(recordingMiddleware("inner", &mu, &calls)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	provider.EvaluateFlag("my-flag", evalCtx)
+	provider.EvaluateAllFlags(evalCtx)
+	provider.BooleanEvaluation(context.Background(), "my-flag", false, evalCtx)
+
+	want := []string{
+		"outer /ofrep/v1/evaluate/flags/my-flag",
+		"inner /ofrep/v1/evaluate/flags/my-flag",
+		"outer /ofrep/v1/evaluate/flags",
+		"inner /ofrep/v1/evaluate/flags",
+		"outer /ofrep/v1/evaluate/flags/my-flag",
+		"inner /ofrep/v1/evaluate/flags/my-flag",
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("Expected calls %v, got %v", want, calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("Call %d: expected %q, got %q", i, want[i], calls[i])
+		}
+	}
+
+	for i, values := range headers {
+		if len(values) != 2 || values[0] != "outer" || values[1] != "inner" {
+			t.Errorf("Request %d: expected X-Middleware [outer inner], got %v", i, values)
+		}
+	}
+}
+
+func TestEvaluationMiddleware_DoesNotModifyUserClient(t *testing.T) {
+	client := &http.Client{}
+	var mu sync.Mutex
+	var calls []string
+	provider, err := NewProvider(
+		"test-api-key",
+		WithHTTPClient(client),
+		WithEvaluationMiddleware(recordingMiddleware("mw", &mu, &calls)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	if client.Transport != nil {
+		t.Error("Expected the caller's client to be left untouched")
+	}
+	if provider.httpClient == client {
+		t.Error("Expected the provider to use a wrapped copy of the client")
+	}
+}
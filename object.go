@@ -0,0 +1,50 @@
+package flipswitch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// ErrTypeMismatch is returned when a flag's value does not have the type the
+// caller asked for.
+var ErrTypeMismatch = errors.New("flag value has a different type")
+
+// EvaluateObjectInto evaluates an object flag like EvaluateFlag and decodes
+// its value into out, which must be a non-nil pointer, using encoding/json
+// rules and struct tags. It returns an error wrapping ErrTypeMismatch if the
+// value is not a JSON object, one wrapping ErrSchemaMismatch if
+// WithSchemaValidation is enabled and the value does not match the flag's
+// schema, and the evaluation or decoding error otherwise. out is left
+// untouched on a mismatch.
+func (p *FlipswitchProvider) EvaluateObjectInto(flagKey string, evalCtx openfeature.FlattenedContext, out interface{}) error {
+	eval, err := p.evaluateFlag(context.Background(), flagKey, evalCtx)
+	if err != nil {
+		if !isUnavailable(err) {
+			return err
+		}
+		if eval = p.fallbackFlag(flagKey, evalCtx); eval == nil {
+			return err
+		}
+	}
+
+	if _, ok := eval.Value.(map[string]interface{}); !ok {
+		return fmt.Errorf("flag %q: %w: want object, got %s", flagKey, ErrTypeMismatch, inferType(eval.Value))
+	}
+	if err := p.validateFlagSchema(flagKey, eval.Value); err != nil {
+		return fmt.Errorf("flag %q: %w", flagKey, err)
+	}
+
+	// Round trip through JSON so that out's field types and tags apply
+	raw, err := json.Marshal(eval.Value)
+	if err != nil {
+		return fmt.Errorf("flag %q: encoding value: %w", flagKey, err)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("flag %q: decoding value: %w", flagKey, err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package flipswitch
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+type tuning struct {
+	BatchSize int      `json:"batchSize"`
+	Timeout   float64  `json:"timeout"`
+	Regions   []string `json:"regions"`
+	Enabled   bool     `json:"enabled"`
+}
+
+func createObjectProvider(t *testing.T) (*FlipswitchProvider, func()) {
+	t.Helper()
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("tuning", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{
+			"key": "tuning",
+			"value": map[string]interface{}{
+				"batchSize": 50,
+				"timeout":   2.5,
+				"regions":   []string{"eu", "us"},
+				"enabled":   true,
+			},
+		}
+	})
+	dispatcher.SetFlagResponse("dark-mode", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "dark-mode", "value": true}
+	})
+	server := httptest.NewServer(dispatcher)
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		server.Close()
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	return provider, func() {
+		provider.Shutdown()
+		server.Close()
+	}
+}
+
+func TestEvaluateObjectInto_DecodesStruct(t *testing.T) {
+	provider, cleanup := createObjectProvider(t)
+	defer cleanup()
+
+	var got tuning
+	if err := provider.EvaluateObjectInto("tuning", openfeature.FlattenedContext{}, &got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got.BatchSize != 50 {
+		t.Errorf("Expected batchSize 50, got %d", got.BatchSize)
+	}
+	if got.Timeout != 2.5 {
+		t.Errorf("Expected timeout 2.5, got %v", got.Timeout)
+	}
+	if len(got.Regions) != 2 || got.Regions[0] != "eu" || got.Regions[1] != "us" {
+		t.Errorf("Expected regions [eu us], got %v", got.Regions)
+	}
+	if !got.Enabled {
+		t.Error("Expected enabled to be true")
+	}
+}
+
+func TestEvaluateObjectInto_NonObjectValue(t *testing.T) {
+	provider, cleanup := createObjectProvider(t)
+	defer cleanup()
+
+	got := tuning{BatchSize: 7}
+	err := provider.EvaluateObjectInto("dark-mode", openfeature.FlattenedContext{}, &got)
+	if !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("Expected ErrTypeMismatch, got %v", err)
+	}
+	if got.BatchSize != 7 {
+		t.Errorf("Expected out to be left untouched, got %+v", got)
+	}
+}
+
+func TestEvaluateObjectInto_DecodeFailure(t *testing.T) {
+	provider, cleanup := createObjectProvider(t)
+	defer cleanup()
+
+	var wrong struct {
+		BatchSize string `json:"batchSize"`
+	}
+	err := provider.EvaluateObjectInto("tuning", openfeature.FlattenedContext{}, &wrong)
+	if err == nil || errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("Expected a decoding error, got %v", err)
+	}
+
+	if err := provider.EvaluateObjectInto("tuning", openfeature.FlattenedContext{}, tuning{}); err == nil {
+		t.Error("Expected an error for a non-pointer target")
+	}
+}
+
+func TestEvaluateObjectInto_MissingFlag(t *testing.T) {
+	provider, cleanup := createObjectProvider(t)
+	defer cleanup()
+
+	var got tuning
+	if err := provider.EvaluateObjectInto("missing", openfeature.FlattenedContext{}, &got); !errors.Is(err, ErrFlagNotFound) {
+		t.Errorf("Expected ErrFlagNotFound, got %v", err)
+	}
+}
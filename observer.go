@@ -0,0 +1,181 @@
+package flipswitch
+
+import (
+	"errors"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// EvaluationSource tells where the value of an evaluation came from.
+type EvaluationSource string
+
+const (
+	// EvaluationSourceNetwork is a value the server returned for the call.
+	EvaluationSourceNetwork EvaluationSource = "network"
+
+	// EvaluationSourceCache is a value the SDK already held: a cached or
+	// request-scoped evaluation, or a last-known or bootstrapped value
+	// served because the server could not be reached.
+	EvaluationSourceCache EvaluationSource = "cache"
+
+	// EvaluationSourceLocal is a value the SDK decided without the server:
+	// an environment override, the kill switch, or a bootstrapped value in
+	// offline mode.
+	EvaluationSourceLocal EvaluationSource = "local"
+
+	// EvaluationSourceDefault means no value was available and the caller
+	// got its default (or nil from EvaluateFlag).
+	EvaluationSourceDefault EvaluationSource = "default"
+)
+
+// EvaluationRecord describes one flag evaluation, as reported to the
+// observer set with WithEvaluationObserver.
+type EvaluationRecord struct {
+	// FlagKey is the evaluated flag. It is empty for a failed
+	// EvaluateAllFlags call, which is reported as a single record.
+	FlagKey string
+
+	// ValueType is the type of the resolved value, as in
+	// FlagEvaluation.ValueType, or the type requested by a typed
+	// OpenFeature evaluation.
+	ValueType string
+
+	// Value is the value returned to the caller, after any value transformer.
+	// For a default it is the caller's default, or nil for EvaluateFlag.
+	Value interface{}
+
+	// Reason and Variant are those of the returned evaluation.
+	Reason  string
+	Variant string
+
+	// Source tells where Value came from. It is EvaluationSourceDefault
+	// whenever Default is set.
+	Source EvaluationSource
+
+	// Default is set when no evaluation was available and the caller got
+	// its default value (or nil from EvaluateFlag).
+	Default bool
+
+	// Latency is the duration of the call. For EvaluateAllFlags every
+	// record carries the duration of the whole bulk call.
+	Latency time.Duration
+
+	// Error is the error that caused the default, or that caused a fallback
+	// value to be served, if any.
+	Error error
+}
+
+// WithEvaluationObserver calls observer after every evaluation made through
+// EvaluateFlag, EvaluateAllFlags, EvaluateFlagWithPolicy and the OpenFeature
+// typed evaluations, including failed ones. EvaluateAllFlags reports a
+// record per returned flag. The observer runs synchronously on the
+// evaluating goroutine, so it should be fast; a panic in it is recovered and
+// logged.
+func WithEvaluationObserver(observer func(EvaluationRecord)) Option {
+	return func(p *FlipswitchProvider) {
+		p.evaluationObserver = observer
+	}
+}
+
+// observe passes record to the evaluation observer, if one is set.
+func (p *FlipswitchProvider) observe(record EvaluationRecord) {
+	if p.evaluationObserver == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Errorw("Error in evaluation observer", "flagKey", record.FlagKey, "panic", r)
+		}
+	}()
+	p.evaluationObserver(record)
+}
+
+// fetchSource is the source of an evaluation the provider fetched: the
+// bootstrapped values stand in for the server in offline mode.
+func (p *FlipswitchProvider) fetchSource() EvaluationSource {
+	if p.offlineMode {
+		return EvaluationSourceLocal
+	}
+	return EvaluationSourceNetwork
+}
+
+// observeFlag reports a single flag evaluation that started at start and
+// returned eval from source; eval is nil if the caller got nothing.
+func (p *FlipswitchProvider) observeFlag(flagKey string, start time.Time, eval *FlagEvaluation, source EvaluationSource, err error) {
+	if p.evaluationObserver == nil {
+		return
+	}
+	record := EvaluationRecord{
+		FlagKey: flagKey,
+		Default: eval == nil,
+		Source:  EvaluationSourceDefault,
+		Latency: time.Since(start),
+		Error:   err,
+	}
+	if eval != nil {
+		record.ValueType = eval.ValueType
+		record.Value = eval.Value
+		record.Reason = eval.Reason
+		record.Variant = eval.Variant
+		record.Source = source
+	}
+	p.observe(record)
+}
+
+// observeAll reports an EvaluateAllFlags call that started at start and
+// returned flags from source: a record per flag, or a single record if the
+// call failed and returned nothing. Overridden flags are reported as local.
+func (p *FlipswitchProvider) observeAll(start time.Time, flags []FlagEvaluation, source EvaluationSource, err error) {
+	if p.evaluationObserver == nil {
+		return
+	}
+	latency := time.Since(start)
+	if len(flags) == 0 && err != nil {
+		p.observe(EvaluationRecord{Default: true, Source: EvaluationSourceDefault, Latency: latency, Error: err})
+		return
+	}
+	for _, flag := range flags {
+		record := EvaluationRecord{
+			FlagKey:   flag.Key,
+			ValueType: flag.ValueType,
+			Value:     flag.Value,
+			Reason:    flag.Reason,
+			Variant:   flag.Variant,
+			Source:    source,
+			Latency:   latency,
+			Error:     err,
+		}
+		if p.envOverride(flag.Key) != nil {
+			record.Source = EvaluationSourceLocal
+		}
+		p.observe(record)
+	}
+}
+
+// observeTypedResolution reports a typed OpenFeature evaluation that started
+// at start and returned value from source.
+func (p *FlipswitchProvider) observeTypedResolution(flag, valueType string, start time.Time, source EvaluationSource, value interface{}, detail *openfeature.ProviderResolutionDetail) {
+	if p.evaluationObserver == nil {
+		return
+	}
+	record := EvaluationRecord{
+		FlagKey:   flag,
+		ValueType: valueType,
+		Value:     value,
+		Reason:    string(detail.Reason),
+		Variant:   detail.Variant,
+		Source:    source,
+		Latency:   time.Since(start),
+	}
+	if resolution := detail.ResolutionDetail(); resolution.ErrorCode != "" {
+		record.Default = true
+		record.Error = errors.New(string(resolution.ErrorCode) + ": " + resolution.ErrorMessage)
+	} else if detail.Reason == openfeature.ErrorReason {
+		record.Default = true
+	}
+	if record.Default {
+		record.Source = EvaluationSourceDefault
+	}
+	p.observe(record)
+}
@@ -0,0 +1,253 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// recordingObserver collects every EvaluationRecord it is given.
+type recordingObserver struct {
+	mu      sync.Mutex
+	records []EvaluationRecord
+}
+
+func (o *recordingObserver) observe(record EvaluationRecord) {
+	o.mu.Lock()
+	o.records = append(o.records, record)
+	o.mu.Unlock()
+}
+
+func (o *recordingObserver) all() []EvaluationRecord {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]EvaluationRecord(nil), o.records...)
+}
+
+func createObservedProvider(t *testing.T, opts ...Option) (*FlipswitchProvider, *recordingObserver, func()) {
+	t.Helper()
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "my-flag", "value": true, "reason": "TARGETING_MATCH", "variant": "on"}
+	})
+	dispatcher.SetFlagResponse("broken", func() (int, map[string]interface{}) {
+		return 500, map[string]interface{}{}
+	})
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"flags": []interface{}{
+			map[string]interface{}{"key": "flag-a", "value": "x", "reason": "STATIC"},
+			map[string]interface{}{"key": "flag-b", "value": 2.5, "reason": "DEFAULT"},
+		}}
+	})
+	server := httptest.NewServer(dispatcher)
+
+	observer := &recordingObserver{}
+	provider, err := NewProvider(
+		"test-api-key",
+		append([]Option{
+			WithBaseURL(server.URL),
+			WithRealtime(false),
+			WithEvaluationObserver(observer.observe),
+		}, opts...)...,
+	)
+	if err != nil {
+		server.Close()
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	return provider, observer, func() {
+		provider.Shutdown()
+		server.Close()
+	}
+}
+
+func TestEvaluationObserver_EvaluateFlag(t *testing.T) {
+	provider, observer, cleanup := createObservedProvider(t)
+	defer cleanup()
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+
+	records := observer.all()
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	r := records[0]
+	if r.FlagKey != "my-flag" || r.ValueType != "boolean" || r.Reason != "TARGETING_MATCH" || r.Variant != "on" {
+		t.Errorf("Unexpected record %+v", r)
+	}
+	if r.Default || r.Error != nil {
+		t.Errorf("Expected a successful record, got %+v", r)
+	}
+	if r.Value != true || r.Source != EvaluationSourceNetwork {
+		t.Errorf("Expected value true from the network, got %v from %q", r.Value, r.Source)
+	}
+	if r.Latency <= 0 {
+		t.Errorf("Expected a positive latency, got %v", r.Latency)
+	}
+}
+
+func TestEvaluationObserver_ErrorPath(t *testing.T) {
+	provider, observer, cleanup := createObservedProvider(t)
+	defer cleanup()
+
+	provider.EvaluateFlag("broken", openfeature.FlattenedContext{})
+	provider.BooleanEvaluation(context.Background(), "broken", false, openfeature.FlattenedContext{})
+
+	records := observer.all()
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	for _, r := range records {
+		if r.FlagKey != "broken" || !r.Default || r.Error == nil {
+			t.Errorf("Expected a default with an error for broken, got %+v", r)
+		}
+		if r.Source != EvaluationSourceDefault {
+			t.Errorf("Expected the default source, got %q", r.Source)
+		}
+	}
+}
+
+func TestEvaluationObserver_TypedEvaluations(t *testing.T) {
+	provider, observer, cleanup := createObservedProvider(t)
+	defer cleanup()
+
+	provider.BooleanEvaluation(context.Background(), "my-flag", false, openfeature.FlattenedContext{})
+
+	records := observer.all()
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	r := records[0]
+	if r.FlagKey != "my-flag" || r.ValueType != "boolean" || r.Reason != "TARGETING_MATCH" || r.Variant != "on" || r.Default {
+		t.Errorf("Unexpected record %+v", r)
+	}
+	if r.Value != true || r.Source != EvaluationSourceNetwork {
+		t.Errorf("Expected value true from the network, got %v from %q", r.Value, r.Source)
+	}
+}
+
+func TestEvaluationObserver_CacheHit(t *testing.T) {
+	provider, observer, cleanup := createObservedProvider(t, WithCache(time.Minute))
+	defer cleanup()
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	provider.BooleanEvaluation(context.Background(), "my-flag", false, openfeature.FlattenedContext{})
+
+	records := observer.all()
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+	wantSources := []EvaluationSource{EvaluationSourceNetwork, EvaluationSourceCache, EvaluationSourceCache}
+	for i, r := range records {
+		if r.Source != wantSources[i] {
+			t.Errorf("Record %d: expected source %q, got %q", i, wantSources[i], r.Source)
+		}
+		if r.Value != true || r.Default || r.Error != nil {
+			t.Errorf("Record %d: expected a successful true, got %+v", i, r)
+		}
+		if r.Latency <= 0 {
+			t.Errorf("Record %d: expected a positive latency, got %v", i, r.Latency)
+		}
+	}
+}
+
+func TestEvaluationObserver_Overrides(t *testing.T) {
+	t.Setenv("FLIPSWITCH_FLAG_my_flag", "false")
+	provider, observer, cleanup := createObservedProvider(t, WithEnvOverrides(""))
+	defer cleanup()
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+
+	records := observer.all()
+	if len(records) != 1 || records[0].Source != EvaluationSourceLocal || records[0].Value != false {
+		t.Errorf("Expected an overridden false from the local source, got %+v", records)
+	}
+}
+
+func TestEvaluationObserver_EvaluateFlagWithPolicy(t *testing.T) {
+	provider, observer, cleanup := createObservedProvider(t, WithCache(time.Minute))
+	defer cleanup()
+
+	if _, err := provider.EvaluateFlagWithPolicy("my-flag", openfeature.FlattenedContext{}, CacheOnly); err == nil {
+		t.Fatal("Expected a cache miss")
+	}
+	if _, err := provider.EvaluateFlagWithPolicy("my-flag", openfeature.FlattenedContext{}, NetworkOnly); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	records := observer.all()
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if !records[0].Default || records[0].Source != EvaluationSourceDefault || records[0].Error == nil {
+		t.Errorf("Expected a default for the cache miss, got %+v", records[0])
+	}
+	if records[1].Source != EvaluationSourceNetwork || records[1].Value != true {
+		t.Errorf("Expected true from the network, got %+v", records[1])
+	}
+}
+
+func TestEvaluationObserver_EvaluateAllFlags(t *testing.T) {
+	provider, observer, cleanup := createObservedProvider(t)
+	defer cleanup()
+
+	provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+
+	records := observer.all()
+	if len(records) != 2 {
+		t.Fatalf("Expected a record per flag, got %d", len(records))
+	}
+	if records[0].FlagKey != "flag-a" || records[0].ValueType != "string" || records[1].FlagKey != "flag-b" || records[1].Reason != "DEFAULT" {
+		t.Errorf("Unexpected records %+v", records)
+	}
+	if records[0].Latency != records[1].Latency {
+		t.Errorf("Expected every record to carry the bulk call latency, got %v and %v", records[0].Latency, records[1].Latency)
+	}
+}
+
+func TestEvaluationObserver_EvaluateAllFlagsFailure(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		return 503, map[string]interface{}{}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithEvaluationObserver(observer.observe),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+
+	records := observer.all()
+	if len(records) != 1 || !records[0].Default || records[0].Error == nil || records[0].FlagKey != "" {
+		t.Errorf("Expected a single failed record, got %+v", records)
+	}
+}
+
+func TestEvaluationObserver_PanicIsRecovered(t *testing.T) {
+	provider, _, cleanup := createObservedProvider(t, WithEvaluationObserver(func(EvaluationRecord) {
+		panic("observer failed")
+	}))
+	defer cleanup()
+
+	result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	if result == nil || !result.AsBoolean() {
+		t.Errorf("Expected the evaluation to succeed despite the panicking observer, got %+v", result)
+	}
+}
@@ -0,0 +1,84 @@
+package flipswitch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// errOfflineMode is returned for requests attempted in offline mode, such as
+// point-in-time evaluations, which cannot be served from the bootstrap flags.
+var errOfflineMode = errors.New("offline mode: no requests are made")
+
+// WithBootstrapFile loads the bootstrap flags from a JSON file in the format
+// of a bulk evaluation response, {"flags": [{"key": ..., "value": ...}]},
+// which is parsed exactly like a live response. The flags are served when
+// the server cannot be reached, as with WithBootstrap (whose flags they
+// replace), and always in offline mode. NewProvider returns an error if the
+// file cannot be read or parsed.
+func WithBootstrapFile(path string) Option {
+	return func(p *FlipswitchProvider) {
+		p.bootstrapFile = path
+	}
+}
+
+// WithOfflineMode serves every evaluation from the bootstrap flags, for
+// local development and CI without a Flipswitch backend. Init neither
+// validates the API key nor opens an SSE connection, and always succeeds; no
+// request is ever made. Flags that were not bootstrapped are not found.
+func WithOfflineMode(enabled bool) Option {
+	return func(p *FlipswitchProvider) {
+		p.offlineMode = enabled
+	}
+}
+
+// loadBootstrapFile replaces the bootstrap flags with those in the file set
+// with WithBootstrapFile, if any.
+func (p *FlipswitchProvider) loadBootstrapFile() error {
+	if p.bootstrapFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(p.bootstrapFile)
+	if err != nil {
+		return fmt.Errorf("reading bootstrap file: %w", err)
+	}
+	flags, err := parseBulkResponse(data)
+	if err != nil {
+		return fmt.Errorf("bootstrap file %s: %w", p.bootstrapFile, err)
+	}
+	p.bootstrap = newBootstrapStore(flags)
+	return nil
+}
+
+// offlineFlag resolves flag from the bootstrap flags in offline mode. The
+// returned evaluation is never nil; for a flag that was not bootstrapped its
+// value is nil and the detail reports FLAG_NOT_FOUND.
+func (p *FlipswitchProvider) offlineFlag(flag string) (*FlagEvaluation, openfeature.ProviderResolutionDetail) {
+	eval := p.bootstrapFlag(flag)
+	if eval == nil {
+		return &FlagEvaluation{Key: flag}, openfeature.ProviderResolutionDetail{
+			Reason:          openfeature.ErrorReason,
+			ResolutionError: openfeature.NewFlagNotFoundResolutionError("flag " + flag + " is not bootstrapped"),
+		}
+	}
+	reason := openfeature.Reason(eval.Reason)
+	if reason == "" {
+		reason = openfeature.StaticReason
+	}
+	return eval, openfeature.ProviderResolutionDetail{Reason: reason, Variant: eval.Variant}
+}
+
+// offlineMismatch is the resolution detail returned in offline mode when the
+// bootstrapped value does not have the requested type. A missing flag keeps
+// its FLAG_NOT_FOUND detail.
+func offlineMismatch(flag string, detail openfeature.ProviderResolutionDetail) openfeature.ProviderResolutionDetail {
+	if detail.Reason == openfeature.ErrorReason {
+		return detail
+	}
+	return openfeature.ProviderResolutionDetail{
+		Reason:          openfeature.ErrorReason,
+		ResolutionError: openfeature.NewTypeMismatchResolutionError("bootstrapped value of " + flag + " has the wrong type"),
+	}
+}
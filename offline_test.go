@@ -0,0 +1,129 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const bootstrapFixture = "testdata/bootstrap.json"
+
+func TestOfflineMode_ServesBootstrapFileWithoutRequests(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithBootstrapFile(bootstrapFixture),
+		WithOfflineMode(true),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Expected offline Init to succeed, got %v", err)
+	}
+	if provider.Status() != openfeature.ReadyState {
+		t.Errorf("Expected READY, got %s", provider.Status())
+	}
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	tests := []struct {
+		key, valueType string
+		value          interface{}
+	}{
+		{"dark-mode", "boolean", true},
+		{"theme", "string", "blue"},
+		{"max-items", "integer", float64(25)},
+		{"discount", "number", 0.15},
+	}
+	for _, tt := range tests {
+		result := provider.EvaluateFlag(tt.key, evalCtx)
+		if result == nil || result.Value != tt.value || result.ValueType != tt.valueType {
+			t.Errorf("%s: expected %v (%s), got %+v", tt.key, tt.value, tt.valueType, result)
+		}
+	}
+	if result := provider.EvaluateFlag("dark-mode", evalCtx); result.Reason != "STATIC" || result.Variant != "on" {
+		t.Errorf("Expected the fixture's reason and variant, got %+v", result)
+	}
+	if result := provider.EvaluateFlag("missing", evalCtx); result != nil {
+		t.Errorf("Expected a flag missing from the file not to be found, got %+v", result)
+	}
+	if flags := provider.EvaluateAllFlags(evalCtx); len(flags) != 5 {
+		t.Errorf("Expected all 5 bootstrapped flags, got %d", len(flags))
+	}
+
+	ctx := context.Background()
+	if detail := provider.BooleanEvaluation(ctx, "dark-mode", false, evalCtx); !detail.Value || detail.Reason != openfeature.StaticReason {
+		t.Errorf("Expected dark-mode true with reason STATIC, got %+v", detail)
+	}
+	if detail := provider.IntEvaluation(ctx, "max-items", 0, evalCtx); detail.Value != 25 {
+		t.Errorf("Expected max-items 25, got %+v", detail)
+	}
+	if detail := provider.StringEvaluation(ctx, "missing", "fallback", evalCtx); detail.Value != "fallback" ||
+		detail.ResolutionDetail().ErrorCode != openfeature.FlagNotFoundCode {
+		t.Errorf("Expected the default with FLAG_NOT_FOUND, got %+v", detail)
+	}
+	if detail := provider.StringEvaluation(ctx, "dark-mode", "fallback", evalCtx); detail.Value != "fallback" ||
+		detail.ResolutionDetail().ErrorCode != openfeature.TypeMismatchCode {
+		t.Errorf("Expected the default with TYPE_MISMATCH, got %+v", detail)
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 0 {
+		t.Errorf("Expected no requests in offline mode, got %d", got)
+	}
+}
+
+func TestBootstrapFile_ServedWhenServerUnavailable(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("theme", func() (int, map[string]interface{}) {
+		return 503, map[string]interface{}{}
+	})
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		return 503, map[string]interface{}{}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithBootstrapFile(bootstrapFixture),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if result := provider.EvaluateFlag("theme", openfeature.FlattenedContext{}); result == nil || result.AsString() != "blue" {
+		t.Errorf("Expected the bootstrapped theme, got %+v", result)
+	}
+	if flags := provider.EvaluateAllFlags(openfeature.FlattenedContext{}); len(flags) != 5 {
+		t.Errorf("Expected the 5 bootstrapped flags, got %d", len(flags))
+	}
+}
+
+func TestBootstrapFile_InvalidFileFailsNewProvider(t *testing.T) {
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(invalid, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{invalid, filepath.Join(t.TempDir(), "missing.json")} {
+		if _, err := NewProvider("test-api-key", WithBootstrapFile(path)); err == nil {
+			t.Errorf("Expected an error for %s", path)
+		}
+	}
+}
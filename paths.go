@@ -0,0 +1,104 @@
+package flipswitch
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Default endpoint paths, relative to the base URL.
+const (
+	defaultSsePath     = "/api/v1/flags/events"
+	defaultOfrepPrefix = "/ofrep/v1"
+)
+
+// WithSsePath overrides the path of the SSE event stream, relative to the
+// base URL, for servers mounted under a path prefix. The default is
+// "/api/v1/flags/events". The path must start with "/".
+func WithSsePath(path string) Option {
+	return func(p *FlipswitchProvider) {
+		p.ssePath = path
+	}
+}
+
+// WithOfrepPrefix overrides the prefix of the OFREP evaluation endpoints,
+// relative to the base URL, for servers mounted under a path prefix. It
+// applies to the OpenFeature evaluations, the direct EvaluateFlag and
+// EvaluateAllFlags calls and the API key check made by Init. The default is
+// "/ofrep/v1". The prefix must start with "/".
+func WithOfrepPrefix(prefix string) Option {
+	return func(p *FlipswitchProvider) {
+		p.ofrepPrefix = prefix
+	}
+}
+
+// normalizePaths validates the configured paths and strips trailing slashes.
+func (p *FlipswitchProvider) normalizePaths() error {
+	if !strings.HasPrefix(p.ssePath, "/") {
+		return fmt.Errorf("invalid SSE path %q: must start with /", p.ssePath)
+	}
+	if !strings.HasPrefix(p.ofrepPrefix, "/") {
+		return fmt.Errorf("invalid OFREP prefix %q: must start with /", p.ofrepPrefix)
+	}
+	p.ssePath = strings.TrimSuffix(p.ssePath, "/")
+	p.ofrepPrefix = strings.TrimSuffix(p.ofrepPrefix, "/")
+	return nil
+}
+
+// ofrepClient returns the HTTP client for the OFREP provider, which always
+// requests paths under "/ofrep/v1". With a custom prefix, the client
+// rewrites those paths to use it, and with WithResponseVerification it
+// rejects responses that fail verification.
+func (p *FlipswitchProvider) ofrepClient() *http.Client {
+	if p.ofrepPrefix == defaultOfrepPrefix && p.verificationKey == nil {
+		return p.httpClient
+	}
+
+	transport := p.httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if p.ofrepPrefix != defaultOfrepPrefix {
+		basePath := ""
+		if u, err := url.Parse(p.baseURL); err == nil {
+			basePath = u.Path
+		}
+		transport = &prefixRewriter{
+			next: transport,
+			from: basePath + defaultOfrepPrefix,
+			to:   basePath + p.ofrepPrefix,
+		}
+	}
+	if p.verificationKey != nil {
+		transport = &verifyingTransport{next: transport, verify: p.verifyResponse}
+	}
+	client := *p.httpClient
+	client.Transport = transport
+	return &client
+}
+
+// prefixRewriter replaces the path prefix from with to on outgoing requests.
+type prefixRewriter struct {
+	next     http.RoundTripper
+	from, to string
+}
+
+func (r *prefixRewriter) RoundTrip(req *http.Request) (*http.Response, error) {
+	rest, ok := strings.CutPrefix(req.URL.Path, r.from)
+	if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+		return r.next.RoundTrip(req)
+	}
+	// RoundTrippers must not modify the request they are given
+	req = req.Clone(req.Context())
+	req.URL.Path = r.to + rest
+	req.URL.RawPath = ""
+	return r.next.RoundTrip(req)
+}
+
+// withSsePath sets the path of the event stream.
+func withSsePath(path string) SseOption {
+	return func(c *SseClient) {
+		c.path = path
+	}
+}
@@ -0,0 +1,125 @@
+package flipswitch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestCustomPaths_UsedByEveryRequest(t *testing.T) {
+	const (
+		evalPrefix = "/base/eval/ofrep"
+		ssePath    = "/base/events/stream"
+	)
+
+	var mu sync.Mutex
+	var paths []string
+	connected := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+
+		switch {
+		case r.URL.Path == ssePath:
+			select {
+			case connected <- struct{}{}:
+			default:
+			}
+			serveSseKeepAlive(w, r)
+		case r.URL.Path == evalPrefix+"/evaluate/flags":
+			json.NewEncoder(w).Encode(map[string]interface{}{"flags": []interface{}{}})
+		case strings.HasPrefix(r.URL.Path, evalPrefix+"/evaluate/flags/"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"key": "my-flag", "value": true, "reason": "STATIC"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL+"/base"),
+		WithOfrepPrefix("/eval/ofrep/"),
+		WithSsePath("/events/stream"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the SSE connection on the custom path")
+	}
+
+	if result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{}); result == nil || !result.AsBoolean() {
+		t.Errorf("Expected EvaluateFlag to use the custom prefix, got %+v", result)
+	}
+	provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+	if detail := provider.BooleanEvaluation(context.Background(), "my-flag", false, openfeature.FlattenedContext{}); !detail.Value {
+		t.Errorf("Expected the OpenFeature evaluation to use the custom prefix, got %+v", detail)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := map[string]bool{
+		evalPrefix + "/evaluate/flags":         true, // validateAPIKey and EvaluateAllFlags
+		ssePath:                                true,
+		evalPrefix + "/evaluate/flags/my-flag": true, // EvaluateFlag and BooleanEvaluation
+	}
+	for _, path := range paths {
+		if !want[path] {
+			t.Errorf("Unexpected request path %q", path)
+		}
+	}
+	if len(paths) != 5 {
+		t.Errorf("Expected 5 requests, got %d: %v", len(paths), paths)
+	}
+}
+
+func TestCustomPaths_MustStartWithSlash(t *testing.T) {
+	if _, err := NewProvider("test-api-key", WithSsePath("events")); err == nil {
+		t.Error("Expected an error for an SSE path without a leading slash")
+	}
+	if _, err := NewProvider("test-api-key", WithOfrepPrefix("ofrep/v1")); err == nil {
+		t.Error("Expected an error for an OFREP prefix without a leading slash")
+	}
+}
+
+func TestPrefixRewriter_OnlyRewritesWholeSegments(t *testing.T) {
+	var got []string
+	rewriter := &prefixRewriter{
+		next: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			got = append(got, req.URL.Path)
+			return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+		}),
+		from: "/ofrep/v1",
+		to:   "/custom",
+	}
+	for _, path := range []string{"/ofrep/v1/evaluate/flags/x", "/ofrep/v10/evaluate", "/other"} {
+		req := httptest.NewRequest("POST", "http://example.com"+path, nil)
+		if _, err := rewriter.RoundTrip(req); err != nil {
+			t.Fatal(err)
+		}
+		if req.URL.Path != path {
+			t.Errorf("Expected the original request to be left untouched, got %q", req.URL.Path)
+		}
+	}
+	want := []string{"/custom/evaluate/flags/x", "/ofrep/v10/evaluate", "/other"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %q, got %q", want[i], got[i])
+		}
+	}
+}
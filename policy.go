@@ -0,0 +1,92 @@
+package flipswitch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// EvalPolicy selects where EvaluateFlagWithPolicy may get a flag value from.
+type EvalPolicy int
+
+const (
+	// CacheThenNetwork serves a cached value if there is one and fetches
+	// from the server otherwise. This is how EvaluateFlag behaves.
+	CacheThenNetwork EvalPolicy = iota
+	// NetworkOnly always fetches from the server and neither reads nor
+	// updates the cache.
+	NetworkOnly
+	// CacheOnly serves a cached value and never contacts the server.
+	CacheOnly
+	// NetworkThenCache fetches from the server, falling back to a cached
+	// value if the request fails. A successful result updates the cache.
+	NetworkThenCache
+)
+
+// ErrCacheMiss is returned by EvaluateFlagWithPolicy with CacheOnly when the
+// flag has no cached value for the context.
+var ErrCacheMiss = errors.New("flag is not cached for this context")
+
+// EvaluateFlagWithPolicy evaluates a single flag like EvaluateFlag, but lets
+// the caller choose between the cache and the server for this call only,
+// regardless of how the provider evaluates flags otherwise. Policies that
+// read the cache need WithCache; without it CacheOnly always fails.
+func (p *FlipswitchProvider) EvaluateFlagWithPolicy(flagKey string, evalCtx openfeature.FlattenedContext, policy EvalPolicy) (*FlagEvaluation, error) {
+	start := time.Now()
+	if flagKey == "" {
+		p.observeFlag(flagKey, start, nil, EvaluationSourceDefault, ErrEmptyFlagKey)
+		return nil, ErrEmptyFlagKey
+	}
+
+	ctx := context.Background()
+	if policy == CacheThenNetwork {
+		eval, source, err := p.evaluateFlagFrom(ctx, flagKey, evalCtx)
+		p.observeFlag(flagKey, start, eval, source, err)
+		return eval, err
+	}
+
+	eval, source, err := p.evaluateWithPolicy(ctx, flagKey, evalCtx, policy)
+	p.metrics.observeEvaluation(flagKey, time.Since(start), err != nil)
+	p.observeFlag(flagKey, start, eval, source, err)
+	return eval, err
+}
+
+func (p *FlipswitchProvider) evaluateWithPolicy(ctx context.Context, flagKey string, evalCtx openfeature.FlattenedContext, policy EvalPolicy) (*FlagEvaluation, EvaluationSource, error) {
+	switch policy {
+	case NetworkOnly:
+		eval, err := p.fetchFlag(ctx, flagKey, evalCtx)
+		if err != nil {
+			return nil, EvaluationSourceDefault, err
+		}
+		return eval, p.fetchSource(), nil
+
+	case CacheOnly:
+		if p.cache == nil {
+			return nil, EvaluationSourceDefault, errCacheDisabled
+		}
+		if cached, _, _ := p.cachedEvaluation(flagKey, evalCtx); cached != nil {
+			return cached, EvaluationSourceCache, nil
+		}
+		return nil, EvaluationSourceDefault, ErrCacheMiss
+
+	case NetworkThenCache:
+		ctxHash := contextHash(evalCtx)
+		generation := p.cache.currentGeneration()
+		eval, err := p.fetchFlag(ctx, flagKey, evalCtx)
+		if err == nil {
+			p.cache.set(flagKey, ctxHash, *eval, generation)
+			return eval, p.fetchSource(), nil
+		}
+		// A missing flag is an answer from the server, not a failure to reach it
+		if !errors.Is(err, ErrFlagNotFound) {
+			if cached, _, _ := p.cachedEvaluation(flagKey, evalCtx); cached != nil {
+				return cached, EvaluationSourceCache, nil
+			}
+		}
+		return nil, EvaluationSourceDefault, err
+	}
+	return nil, EvaluationSourceDefault, fmt.Errorf("unknown evaluation policy %d", policy)
+}
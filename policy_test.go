@@ -0,0 +1,168 @@
+package flipswitch
+
+import (
+	"errors"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// primedPolicyProvider returns a caching provider whose cache holds "cached"
+// for my-flag while the server now serves "fresh". Server requests made after
+// priming are counted in calls; fail makes the server return 503.
+func primedPolicyProvider(t *testing.T) (provider *FlipswitchProvider, calls *int32, fail func(), cleanup func()) {
+	t.Helper()
+	calls = new(int32)
+	var failing int32
+	flag, setValue := switchingFlag("my-flag", "cached")
+
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", func() (int, map[string]interface{}) {
+		atomic.AddInt32(calls, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			return 503, map[string]interface{}{}
+		}
+		return flag()
+	})
+	server := httptest.NewServer(dispatcher)
+
+	provider = createCachingProvider(t, server, time.Minute)
+	if result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{}); result == nil || result.AsString() != "cached" {
+		t.Fatalf("Failed to prime cache, got %+v", result)
+	}
+	setValue("fresh")
+	atomic.StoreInt32(calls, 0)
+
+	fail = func() { atomic.StoreInt32(&failing, 1) }
+	cleanup = func() {
+		provider.Shutdown()
+		server.Close()
+	}
+	return provider, calls, fail, cleanup
+}
+
+func TestEvaluateFlagWithPolicy_CacheThenNetworkServesCache(t *testing.T) {
+	provider, calls, _, cleanup := primedPolicyProvider(t)
+	defer cleanup()
+
+	result, err := provider.EvaluateFlagWithPolicy("my-flag", openfeature.FlattenedContext{}, CacheThenNetwork)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.AsString() != "cached" {
+		t.Errorf("Expected cached value, got %v", result.Value)
+	}
+	if got := atomic.LoadInt32(calls); got != 0 {
+		t.Errorf("Expected no requests, got %d", got)
+	}
+}
+
+func TestEvaluateFlagWithPolicy_NetworkOnlyBypassesCache(t *testing.T) {
+	provider, calls, _, cleanup := primedPolicyProvider(t)
+	defer cleanup()
+
+	result, err := provider.EvaluateFlagWithPolicy("my-flag", openfeature.FlattenedContext{}, NetworkOnly)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.AsString() != "fresh" {
+		t.Errorf("Expected fresh value, got %v", result.Value)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+
+	// The cache is left untouched
+	cached, err := provider.EvaluateFlagWithPolicy("my-flag", openfeature.FlattenedContext{}, CacheOnly)
+	if err != nil || cached.AsString() != "cached" {
+		t.Errorf("Expected cache to still hold the cached value, got %+v, %v", cached, err)
+	}
+}
+
+func TestEvaluateFlagWithPolicy_CacheOnlyNeverFetches(t *testing.T) {
+	provider, calls, _, cleanup := primedPolicyProvider(t)
+	defer cleanup()
+
+	result, err := provider.EvaluateFlagWithPolicy("my-flag", openfeature.FlattenedContext{}, CacheOnly)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.AsString() != "cached" {
+		t.Errorf("Expected cached value, got %v", result.Value)
+	}
+
+	_, err = provider.EvaluateFlagWithPolicy("my-flag", openfeature.FlattenedContext{"targetingKey": "other"}, CacheOnly)
+	if !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("Expected ErrCacheMiss for an uncached context, got %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 0 {
+		t.Errorf("Expected no requests, got %d", got)
+	}
+}
+
+func TestEvaluateFlagWithPolicy_CacheOnlyWithoutCache(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if _, err := provider.EvaluateFlagWithPolicy("my-flag", openfeature.FlattenedContext{}, CacheOnly); !errors.Is(err, errCacheDisabled) {
+		t.Errorf("Expected errCacheDisabled, got %v", err)
+	}
+}
+
+func TestEvaluateFlagWithPolicy_NetworkThenCachePrefersNetwork(t *testing.T) {
+	provider, calls, _, cleanup := primedPolicyProvider(t)
+	defer cleanup()
+
+	result, err := provider.EvaluateFlagWithPolicy("my-flag", openfeature.FlattenedContext{}, NetworkThenCache)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.AsString() != "fresh" {
+		t.Errorf("Expected fresh value, got %v", result.Value)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+
+	// The fresh value replaces the cached one
+	cached, err := provider.EvaluateFlagWithPolicy("my-flag", openfeature.FlattenedContext{}, CacheOnly)
+	if err != nil || cached.AsString() != "fresh" {
+		t.Errorf("Expected cache to hold the fresh value, got %+v, %v", cached, err)
+	}
+}
+
+func TestEvaluateFlagWithPolicy_NetworkThenCacheFallsBack(t *testing.T) {
+	provider, calls, fail, cleanup := primedPolicyProvider(t)
+	defer cleanup()
+	fail()
+
+	result, err := provider.EvaluateFlagWithPolicy("my-flag", openfeature.FlattenedContext{}, NetworkThenCache)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.AsString() != "cached" {
+		t.Errorf("Expected cached value after the request failed, got %v", result.Value)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+}
+
+func TestEvaluateFlagWithPolicy_UnknownPolicy(t *testing.T) {
+	provider, _, _, cleanup := primedPolicyProvider(t)
+	defer cleanup()
+
+	if _, err := provider.EvaluateFlagWithPolicy("my-flag", openfeature.FlattenedContext{}, EvalPolicy(99)); err == nil {
+		t.Error("Expected an error for an unknown policy")
+	}
+}
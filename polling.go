@@ -0,0 +1,91 @@
+package flipswitch
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// WithPollingMode replaces SSE with interval polling, for networks where
+// SSE cannot work, such as behind load balancers that buffer responses. No
+// SSE connection is ever attempted. Instead, Init starts polling right away:
+// every WithPollingInterval, all flags are evaluated with one bulk request
+// for the evaluation context passed to Init (or set with SetRefreshContext)
+// and compared with the previous poll. Change listeners then receive a
+// FlagChangeEvent for each flag whose value or variant changed, and one bulk
+// event listing the flags that were added or removed, as they would from
+// SSE. Polling stops at Shutdown.
+func WithPollingMode() Option {
+	return func(p *FlipswitchProvider) {
+		p.pollingMode = true
+	}
+}
+
+// pollFlags evaluates all flags and reports the differences from the
+// previous poll. The first poll after polling starts only records the
+// results.
+func (p *FlipswitchProvider) pollFlags() {
+	p.mu.RLock()
+	ctx, evalCtx := p.backgroundCtx, p.refreshContext
+	p.mu.RUnlock()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	flags, err := p.fetchAllFlags(ctx, evalCtx)
+	if err != nil {
+		p.logger.Warnw("Polling for flag updates failed", errorFields(err)...)
+		return
+	}
+
+	snapshot := make(map[string]FlagEvaluation, len(flags))
+	for _, flag := range flags {
+		snapshot[flag.Key] = flag
+	}
+
+	p.mu.Lock()
+	previous := p.pollSnapshot
+	p.pollSnapshot = snapshot
+	p.mu.Unlock()
+
+	if previous == nil {
+		return
+	}
+	changed, addedOrRemoved := diffSnapshots(previous, snapshot)
+	if len(changed) == 0 && len(addedOrRemoved) == 0 {
+		p.logger.Debugw("Polling: no flag changes")
+		return
+	}
+	p.logger.Infow("Polling: flags changed", "changed", changed, "addedOrRemoved", addedOrRemoved)
+
+	timestamp := p.clock.Now().UTC().Format(time.RFC3339)
+	for _, key := range changed {
+		p.handleFlagChange(FlagChangeEvent{FlagKey: key, Timestamp: timestamp})
+	}
+	if len(addedOrRemoved) > 0 {
+		p.handleFlagChange(FlagChangeEvent{Timestamp: timestamp, AffectedKeys: addedOrRemoved})
+	}
+}
+
+// diffSnapshots compares two polls. It returns, in key order, the flags in
+// both whose value or variant changed, and the flags in only one of them.
+func diffSnapshots(previous, current map[string]FlagEvaluation) (changed, addedOrRemoved []string) {
+	for key, flag := range current {
+		old, ok := previous[key]
+		switch {
+		case !ok:
+			addedOrRemoved = append(addedOrRemoved, key)
+		case old.Variant != flag.Variant || !reflect.DeepEqual(old.Value, flag.Value):
+			changed = append(changed, key)
+		}
+	}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			addedOrRemoved = append(addedOrRemoved, key)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(addedOrRemoved)
+	return changed, addedOrRemoved
+}
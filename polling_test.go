@@ -0,0 +1,229 @@
+package flipswitch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestPollingMode_NotifiesListenersWhenFlagsChange(t *testing.T) {
+	var mu sync.Mutex
+	value := "blue"
+	var sseAttempts int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		return 200, map[string]interface{}{"flags": []interface{}{
+			map[string]interface{}{"key": "theme", "value": value, "reason": "STATIC"},
+		}}
+	})
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&sseAttempts, 1)
+		serveSseKeepAlive(w, r)
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithPollingMode(),
+		WithPollingInterval(20*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	events := make(chan FlagChangeEvent, 10)
+	provider.AddFlagChangeListener(func(event FlagChangeEvent) {
+		events <- event
+	})
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if !provider.IsPollingActive() {
+		t.Fatal("Expected polling to start at Init")
+	}
+
+	// Unchanged results notify nobody
+	select {
+	case event := <-events:
+		t.Fatalf("Expected no event while flags are unchanged, got %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	mu.Lock()
+	value = "green"
+	mu.Unlock()
+
+	select {
+	case event := <-events:
+		if event.FlagKey != "theme" {
+			t.Errorf("Expected a change event for theme, got %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a change event")
+	}
+
+	// The change is reported once, not on every poll
+	select {
+	case event := <-events:
+		t.Errorf("Expected a single event per change, got another %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if got := atomic.LoadInt32(&sseAttempts); got != 0 {
+		t.Errorf("Expected no SSE connection in polling mode, got %d", got)
+	}
+	if provider.GetSseStatus() != StatusDisconnected {
+		t.Errorf("Expected no SSE client, got status %s", provider.GetSseStatus())
+	}
+}
+
+func TestPollingMode_StopsAtShutdown(t *testing.T) {
+	var polls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		atomic.AddInt32(&polls, 1)
+		return 200, map[string]interface{}{"flags": []interface{}{}}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithPollingMode(),
+		WithPollingInterval(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	provider.Shutdown()
+
+	if provider.IsPollingActive() {
+		t.Error("Expected polling to stop at Shutdown")
+	}
+	// Allow a poll that was already in flight to finish
+	time.Sleep(20 * time.Millisecond)
+	after := atomic.LoadInt32(&polls)
+	time.Sleep(50 * time.Millisecond)
+	if got := atomic.LoadInt32(&polls); got != after {
+		t.Errorf("Expected no polls after Shutdown, got %d more", got-after)
+	}
+}
+
+func TestPolling_EmitsTargetedEventsForChangedFlags(t *testing.T) {
+	var mu sync.Mutex
+	flags := map[string]interface{}{"theme": "blue", "limit": 10.0}
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		list := []interface{}{}
+		for key, value := range flags {
+			list = append(list, map[string]interface{}{"key": key, "value": value, "reason": "STATIC"})
+		}
+		return 200, map[string]interface{}{"flags": list}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithPollingMode(),
+		WithPollingInterval(20*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	events := make(chan FlagChangeEvent, 10)
+	provider.AddFlagChangeListener(func(event FlagChangeEvent) {
+		events <- event
+	})
+	limitEvents := make(chan FlagChangeEvent, 10)
+	provider.AddFlagKeyChangeListener("limit", func(event FlagChangeEvent) {
+		limitEvents <- event
+	})
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	// Let the first poll record the snapshot
+	time.Sleep(60 * time.Millisecond)
+
+	mu.Lock()
+	flags["theme"] = "green"
+	mu.Unlock()
+
+	select {
+	case event := <-events:
+		if event.FlagKey != "theme" || len(event.AffectedKeys) != 0 {
+			t.Errorf("Expected a targeted event for theme, got %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a change event")
+	}
+	select {
+	case event := <-events:
+		t.Errorf("Expected exactly one event, got another %+v", event)
+	case event := <-limitEvents:
+		t.Errorf("Expected the unchanged flag's listener not to fire, got %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// An added flag is reported in a bulk event scoped to it
+	mu.Lock()
+	flags["beta"] = true
+	mu.Unlock()
+
+	select {
+	case event := <-events:
+		if event.FlagKey != "" || len(event.AffectedKeys) != 1 || event.AffectedKeys[0] != "beta" {
+			t.Errorf("Expected a bulk event for the added flag, got %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the added flag event")
+	}
+	select {
+	case event := <-limitEvents:
+		t.Errorf("Expected the unchanged flag's listener not to fire, got %+v", event)
+	default:
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	previous := map[string]FlagEvaluation{
+		"same":    {Key: "same", Value: map[string]interface{}{"a": 1.0}},
+		"value":   {Key: "value", Value: 1.0},
+		"variant": {Key: "variant", Value: true, Variant: "on"},
+		"removed": {Key: "removed", Value: true},
+	}
+	current := map[string]FlagEvaluation{
+		"same":    {Key: "same", Value: map[string]interface{}{"a": 1.0}},
+		"value":   {Key: "value", Value: 2.0},
+		"variant": {Key: "variant", Value: true, Variant: "treatment"},
+		"added":   {Key: "added", Value: "x"},
+	}
+
+	changed, addedOrRemoved := diffSnapshots(previous, current)
+	if !reflect.DeepEqual(changed, []string{"value", "variant"}) {
+		t.Errorf("Expected value and variant to change, got %v", changed)
+	}
+	if !reflect.DeepEqual(addedOrRemoved, []string{"added", "removed"}) {
+		t.Errorf("Expected added and removed, got %v", addedOrRemoved)
+	}
+}
@@ -0,0 +1,96 @@
+package flipswitch
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ProtocolVersion is the version of the Flipswitch wire protocol this SDK
+// speaks. It is sent as X-Flipswitch-Protocol on every request.
+const ProtocolVersion = 1
+
+const (
+	// protocolHeader carries the SDK's protocol version on requests, and the
+	// newest version the server speaks on responses.
+	protocolHeader = "X-Flipswitch-Protocol"
+
+	// minProtocolHeader carries the oldest protocol version the server still
+	// accepts.
+	minProtocolHeader = "X-Flipswitch-Min-Protocol"
+)
+
+// protocolValue is ProtocolVersion as sent in protocolHeader.
+var protocolValue = strconv.Itoa(ProtocolVersion)
+
+// ProtocolMismatchError is returned by Init when the server does not support
+// the protocol version this SDK speaks, so its responses could not be parsed
+// reliably.
+type ProtocolMismatchError struct {
+	// SDKProtocol is the protocol version this SDK speaks.
+	SDKProtocol int
+
+	// MinProtocol and MaxProtocol are the oldest and newest protocol
+	// versions the server supports.
+	MinProtocol int
+	MaxProtocol int
+}
+
+func (e *ProtocolMismatchError) Error() string {
+	advice := "upgrade the Flipswitch SDK"
+	if e.SDKProtocol > e.MaxProtocol {
+		advice = "the Flipswitch server is older than this SDK"
+	}
+	return fmt.Sprintf("incompatible Flipswitch protocol: SDK speaks version %d, server supports %d to %d; %s",
+		e.SDKProtocol, e.MinProtocol, e.MaxProtocol, advice)
+}
+
+// setProtocolHeader adds the SDK's protocol version to req.
+func setProtocolHeader(req *http.Request) {
+	req.Header.Set(protocolHeader, protocolValue)
+}
+
+// checkProtocol compares the protocol versions announced in the response
+// headers with ProtocolVersion. A server outside the supported range gives a
+// *ProtocolMismatchError; a server that speaks a newer version but still
+// accepts this one is logged as a warning. Servers that announce nothing are
+// assumed to be compatible.
+func (p *FlipswitchProvider) checkProtocol(header http.Header) error {
+	maxProtocol, ok := p.protocolHeaderValue(header, protocolHeader)
+	if !ok {
+		return nil
+	}
+	minProtocol, ok := p.protocolHeaderValue(header, minProtocolHeader)
+	if !ok {
+		minProtocol = maxProtocol
+	}
+
+	if ProtocolVersion < minProtocol || ProtocolVersion > maxProtocol {
+		return &ProtocolMismatchError{
+			SDKProtocol: ProtocolVersion,
+			MinProtocol: minProtocol,
+			MaxProtocol: maxProtocol,
+		}
+	}
+	if ProtocolVersion < maxProtocol {
+		p.logger.Warnw("Flipswitch server speaks a newer protocol, consider upgrading the SDK",
+			"sdkProtocol", ProtocolVersion, "serverProtocol", maxProtocol)
+	}
+	return nil
+}
+
+// protocolHeaderValue parses the protocol version in header name. A value
+// that is not a positive integer is logged and ignored.
+func (p *FlipswitchProvider) protocolHeaderValue(header http.Header, name string) (int, bool) {
+	raw := strings.TrimSpace(header.Get(name))
+	if raw == "" {
+		return 0, false
+	}
+	version, err := strconv.Atoi(raw)
+	if err != nil || version < 1 {
+		p.logger.Warnw("Ignoring invalid protocol version header", "header", name, "value", raw)
+		return 0, false
+	}
+	return version, true
+}
@@ -0,0 +1,124 @@
+package flipswitch
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// protocolServer answers every request with an empty bulk evaluation and
+// the given protocol headers, and records the protocol header of each
+// request.
+func protocolServer(header http.Header) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sent = append(sent, r.Header.Get(protocolHeader))
+		mu.Unlock()
+		for key, values := range header {
+			w.Header()[key] = values
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"flags": []}`))
+	}))
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), sent...)
+	}
+}
+
+func TestProtocol_IncompatibleServerFailsInit(t *testing.T) {
+	server, _ := protocolServer(http.Header{
+		protocolHeader:    {"3"},
+		minProtocolHeader: {"2"},
+	})
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		// A protocol mismatch is not an outage, so bootstrapped flags do not
+		// paper over it
+		WithBootstrap([]FlagEvaluation{{Key: "my-flag", Value: true}}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	err = provider.Init(openfeature.EvaluationContext{})
+	var mismatch *ProtocolMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Expected a protocol mismatch error, got %v", err)
+	}
+	if mismatch.SDKProtocol != ProtocolVersion || mismatch.MinProtocol != 2 || mismatch.MaxProtocol != 3 {
+		t.Errorf("Unexpected mismatch %+v", mismatch)
+	}
+	if !strings.Contains(err.Error(), "upgrade the Flipswitch SDK") {
+		t.Errorf("Expected the error to say how to fix it, got %q", err.Error())
+	}
+	if provider.Status() != openfeature.ErrorState {
+		t.Errorf("Expected the ERROR state, got %v", provider.Status())
+	}
+}
+
+func TestProtocol_InvalidHeaderIsIgnored(t *testing.T) {
+	server, _ := protocolServer(http.Header{protocolHeader: {"0"}})
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key", WithBaseURL(server.URL), WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Errorf("Expected an invalid protocol header to be ignored, got %v", err)
+	}
+}
+
+func TestProtocolMismatchError_OlderServer(t *testing.T) {
+	mismatch := &ProtocolMismatchError{SDKProtocol: 2, MinProtocol: 1, MaxProtocol: 1}
+	if !strings.Contains(mismatch.Error(), "server is older than this SDK") {
+		t.Errorf("Unexpected message %q", mismatch.Error())
+	}
+}
+
+func TestProtocol_NewerCompatibleServerWarns(t *testing.T) {
+	server, sent := protocolServer(http.Header{
+		protocolHeader:    {"2"},
+		minProtocolHeader: {"1"},
+	})
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithLogger(logger),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Expected a compatible server to initialize, got %v", err)
+	}
+	if _, ok := logger.find("Flipswitch server speaks a newer protocol, consider upgrading the SDK"); !ok {
+		t.Error("Expected a warning about the newer protocol")
+	}
+
+	provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+	for i, version := range sent() {
+		if version != protocolValue {
+			t.Errorf("Request %d: expected protocol header %q, got %q", i, protocolValue, version)
+		}
+	}
+}
@@ -21,28 +21,41 @@ package flipswitch
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
-	"runtime"
 	"runtime/debug"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/open-feature/go-sdk-contrib/providers/ofrep"
 	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
 	defaultBaseURL         = "https://api.flipswitch.io"
 	defaultPollingInterval = 30 * time.Second
 	defaultMaxSseRetries   = 5
+	statusChannelBuffer    = 16
 )
 
+// ErrInvalidAPIKey is returned when the Flipswitch server rejects the API key.
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// ErrEmptyFlagKey is returned when a single flag evaluation is requested with
+// an empty flag key. No request is made, since the resulting URL would address
+// the bulk evaluation endpoint instead.
+var ErrEmptyFlagKey = errors.New("flag key is empty")
+
+// ErrFlagNotFound is returned when the server has no flag with the given key.
+var ErrFlagNotFound = errors.New("flag not found")
+
 var sdkVersion = getVersion()
 
 func getVersion() string {
@@ -59,73 +72,347 @@ func getVersion() string {
 			return strings.TrimPrefix(info.Main.Version, "v")
 		}
 	}
-	return "dev"
+	return Version
 }
 
 // FlipswitchProvider is an OpenFeature provider for Flipswitch with
 // real-time SSE support.
 type FlipswitchProvider struct {
 	baseURL        string
+	baseURLSet     bool
+	region         string
 	apiKey         string
 	enableRealtime bool
 	httpClient     *http.Client
 
+	// sseHTTPClient is the HTTP client configured before middleware was
+	// applied, for the SSE connection
+	sseHTTPClient *http.Client
+
+	// SHA-256 public key fingerprints the server certificate must match
+	tlsPins []string
+
 	// Polling fallback configuration
 	enablePollingFallback bool
 	pollingInterval       time.Duration
 	maxSseRetries         int
+	sseEventMapping       map[string]ChangeType
+	readyAfterFirstSync   bool
 	sseRetryCount         int
 	pollingActive         bool
-	pollingTicker         *time.Ticker
-	pollingDone           chan bool
-
-	ofrepProvider       *ofrep.Provider
-	flagChangeListeners map[int]FlagChangeHandler
-	keyFlagChangeListeners map[string]map[int]FlagChangeHandler
-	nextListenerID      int
-	sseClient           *SseClient
-	initialized         bool
-	eventChan           chan openfeature.Event
-	mu                  sync.RWMutex
+	pollingTicker         ticker
+	pollingDone           chan struct{}
+	onFallbackChange      func(active bool)
+
+	// Bounds of the SSE reconnect backoff
+	sseMinRetryDelay time.Duration
+	sseMaxRetryDelay time.Duration
+
+	// How long the SSE connection may receive nothing before reconnecting
+	sseReadTimeout time.Duration
+
+	// Upper bound of the random wait before Init's first request
+	startupJitter time.Duration
+
+	// Bootstrap flags served when the backend is unreachable
+	bootstrap *bootstrapStore
+
+	// File the bootstrap flags are loaded from, and whether they are served
+	// without ever contacting the backend
+	bootstrapFile string
+	offlineMode   bool
+
+	// Flags served without a request until a refresh replaces them, the
+	// raw bulk response they are parsed from, and whether serving one has
+	// started that refresh
+	initialFlags     *seedStore
+	initialFlagsJSON []byte
+	seedRefreshed    atomic.Bool
+
+	// File the last successful bulk evaluation is kept in, the contents it
+	// was last written or loaded with, and the lock serializing writes
+	snapshotFile string
+	snapshotLast []byte
+	snapshotMu   sync.Mutex
+
+	// Deduplicates concurrent identical single flag evaluations
+	flights flightGroup
+
+	// Source of all randomness (jitter, connection IDs)
+	rng *lockedRand
+
+	// Retry configuration for direct evaluation requests
+	retryMaxAttempts     int
+	retryBaseDelay       time.Duration
+	retryableStatusCodes map[int]bool
+	perAttemptTimeout    time.Duration
+	requestTimeout       time.Duration
+
+	// Bounds in-flight direct evaluation requests
+	limiter evaluationLimiter
+
+	// Optional cache of single flag evaluations
+	cache           *evaluationCache
+	cacheMaxEntries int
+
+	// Last successful evaluations, if WithServeStaleOnError is set
+	lastKnown *lastKnownStore
+
+	// Telemetry headers sent with every request, computed once in
+	// NewProvider; nil when WithTelemetryDisabled is set
+	telemetry         http.Header
+	telemetryDisabled bool
+
+	// Static headers added to every request with WithHeaders
+	customHeaders http.Header
+
+	// User-Agent sent on every request
+	userAgent string
+
+	// Source of time for backoff, polling, retries and debouncing; replaced
+	// in tests
+	clock clock
+
+	// Validate object flag values against the schema in their metadata
+	schemaValidation bool
+	schemas          schemaStore
+
+	// Endpoint paths relative to the base URL
+	ssePath     string
+	ofrepPrefix string
+
+	// Poll instead of using SSE, and the flags seen by the last poll
+	pollingMode  bool
+	pollSnapshot map[string]FlagEvaluation
+
+	// Sent as X-Flipswitch-Connection-ID on the SSE connection, and on
+	// evaluation requests if connectionIDOnEvaluations is set
+	connectionID              string
+	connectionIDOnEvaluations bool
+
+	// Set when a flag change event is dropped from eventChan, so that
+	// WithCatchUpOnReconnect can emit a bulk invalidation
+	changesDropped     bool
+	catchUpOnReconnect bool
+
+	// Copy of the last evaluation request body, if WithDebugCapture is set
+	capture *requestCapture
+
+	// Limits the context attributes sent to the server, if configured
+	contextFilter *contextFilter
+	// Pseudonymizes the targeting key before it is sent, if configured
+	targetingKeyHash func(string) string
+	// Builds the context sent to the server, if configured
+	contextMapper func(openfeature.FlattenedContext) map[string]interface{}
+
+	// Baggage members merged into the evaluation context by the Ctx
+	// evaluation methods, if configured
+	baggageKeys   []string
+	baggageReader BaggageReader
+
+	// Flag values read from the environment by Init, if configured
+	envOverrides *envOverrides
+
+	// Called after every evaluation, if set
+	evaluationObserver func(EvaluationRecord)
+
+	// Post-processes the values returned by EvaluateFlag and
+	// EvaluateAllFlags, if set
+	valueTransformer func(flagKey string, value interface{}) interface{}
+
+	// Re-evaluates changed flags for the refresh context in the background,
+	// if enabled
+	autoReevaluate    bool
+	reevaluateTimeout time.Duration
+
+	// Re-fetches all flags for the refresh context after a bulk change, if
+	// enabled; refreshTimer is pending while a refresh is scheduled
+	autoRefresh     bool
+	refreshDebounce time.Duration
+	refreshTimer    timer
+
+	// Coalesces SSE flag changes within a window, if set; changeTimer is
+	// pending while pendingChanges are collected
+	changeDebounce time.Duration
+	changeTimer    timer
+	pendingChanges *pendingChanges
+
+	// Context for background evaluations, the Init context unless set with
+	// SetRefreshContext; cancelBackground cancels those in flight
+	refreshContext    openfeature.FlattenedContext
+	refreshContextSet bool
+	backgroundCtx     context.Context
+	cancelBackground  context.CancelFunc
+
+	// Flags evaluated one at a time when the server answers bulk
+	// evaluation with 404, if enabled; bulkUnsupported is set once it has
+	bulkUnsupportedFallback bool
+	bulkFallbackKeys        []string
+	bulkUnsupported         atomic.Bool
+
+	// Dependent flag key -> kill switch flag key
+	killSwitches map[string]string
+
+	// Re-validates the API key while initialized, if configured; the
+	// channel is closed to stop the loop
+	keyRevalidationInterval time.Duration
+	keyRevalidationDone     chan struct{}
+
+	// Registry the Prometheus metrics are registered on, if set, and the
+	// metrics themselves
+	metricsRegisterer prometheus.Registerer
+	metrics           *prometheusMetrics
+	// Set once the first SSE connection attempt starts, so later attempts
+	// are counted as reconnects
+	sseConnectStarted bool
+	sseReconnects     int
+
+	// Recent connection and evaluation errors, for RecentErrors
+	errorHistorySize int
+	errorHistory     *errorRing
+
+	// Public key that evaluation responses must be signed with, if set
+	verificationKey ed25519.PublicKey
+
+	// Wraps the evaluation client's transport, outermost first
+	middleware []EvaluationMiddleware
+
+	logger Logger
+
+	ofrepProvider          *ofrep.Provider
+	flagChangeListeners    map[int]FlagChangeHandler
+	keyFlagChangeListeners map[string]map[int]keyListener
+	nextListenerID         int
+
+	// Number of flag change listeners, and the count above which a leak
+	// warning is logged once, if set
+	listenerCount       int
+	maxListeners        int
+	listenerLimitWarned bool
+
+	statusSubscribers map[int]chan ConnectionStatus
+	statusListeners   map[int]ConnectionStatusHandler
+	sseClient         *SseClient
+	initialized       bool
+	status            openfeature.State
+	eventChan         chan openfeature.Event
+	eventBufferSize   int
+	mu                sync.RWMutex
 }
 
 // NewProvider creates a new FlipswitchProvider with the given API key.
-// Returns an error if the API key is empty.
+// Returns an error if the API key is empty or an option is invalid.
 func NewProvider(apiKey string, opts ...Option) (*FlipswitchProvider, error) {
 	if apiKey == "" {
 		return nil, errors.New("apiKey is required")
 	}
 
 	p := &FlipswitchProvider{
-		baseURL:               defaultBaseURL,
-		apiKey:                apiKey,
-		enableRealtime:        true,
-		httpClient:            &http.Client{},
+		baseURL:                defaultBaseURL,
+		apiKey:                 apiKey,
+		enableRealtime:         true,
+		httpClient:             &http.Client{},
 		flagChangeListeners:    make(map[int]FlagChangeHandler),
-		keyFlagChangeListeners: make(map[string]map[int]FlagChangeHandler),
-		enablePollingFallback: true,
-		pollingInterval:       defaultPollingInterval,
-		maxSseRetries:         defaultMaxSseRetries,
-		pollingDone:           make(chan bool),
-		eventChan:             make(chan openfeature.Event, 5),
+		keyFlagChangeListeners: make(map[string]map[int]keyListener),
+		statusSubscribers:      make(map[int]chan ConnectionStatus),
+		statusListeners:        make(map[int]ConnectionStatusHandler),
+		enablePollingFallback:  true,
+		pollingInterval:        defaultPollingInterval,
+		maxSseRetries:          defaultMaxSseRetries,
+		eventBufferSize:        defaultEventBufferSize,
+		status:                 openfeature.NotReadyState,
+		retryMaxAttempts:       1,
+		requestTimeout:         defaultRequestTimeout,
+		reevaluateTimeout:      defaultReevaluateTimeout,
+		sseMinRetryDelay:       defaultMinRetryDelay,
+		sseMaxRetryDelay:       defaultMaxRetryDelay,
+		sseReadTimeout:         defaultSseReadTimeout,
+		refreshDebounce:        defaultRefreshDebounce,
+		rng:                    newTimeSeededRand(),
+		logger:                 stdLogger{},
+		retryableStatusCodes:   statusCodeSet(defaultRetryableStatusCodes),
+		errorHistorySize:       defaultErrorHistorySize,
+		ssePath:                defaultSsePath,
+		ofrepPrefix:            defaultOfrepPrefix,
+		userAgent:              defaultUserAgent,
+		clock:                  realClock{},
 	}
 
 	for _, opt := range opts {
 		opt(p)
 	}
 
+	p.eventChan = make(chan openfeature.Event, p.eventBufferSize)
+	p.errorHistory = newErrorRing(p.errorHistorySize)
+	if p.cache != nil {
+		p.cache.maxEntries = p.cacheMaxEntries
+	}
+	if p.lastKnown != nil && p.cacheMaxEntries > 0 {
+		p.lastKnown.maxEntries = p.cacheMaxEntries
+	}
+	if p.connectionID == "" {
+		p.connectionID = newUUID(p.rng)
+	}
+	if p.pollingMode {
+		p.enableRealtime = false
+	}
+
+	if p.sseMinRetryDelay <= 0 || p.sseMaxRetryDelay < p.sseMinRetryDelay {
+		return nil, fmt.Errorf("invalid SSE retry bounds: min %v, max %v", p.sseMinRetryDelay, p.sseMaxRetryDelay)
+	}
+	if err := p.checkKillSwitches(); err != nil {
+		return nil, err
+	}
+	if err := p.applyRegion(); err != nil {
+		return nil, err
+	}
+	if err := p.normalizePaths(); err != nil {
+		return nil, err
+	}
+	if err := p.loadBootstrapFile(); err != nil {
+		return nil, err
+	}
+	if err := p.loadInitialFlags(); err != nil {
+		return nil, err
+	}
+	p.prepareSnapshot()
+	if err := p.registerMetrics(); err != nil {
+		return nil, err
+	}
+	if err := p.applyTLSPin(); err != nil {
+		return nil, err
+	}
+	p.sseHTTPClient = p.httpClient
+	p.applyMiddleware()
+	p.dropReservedHeaders()
+
 	p.baseURL = strings.TrimSuffix(p.baseURL, "/")
 
-	// Create underlying OFREP provider for flag evaluation
+	if !p.telemetryDisabled {
+		p.telemetry = buildTelemetryHeaders(p.enableRealtime)
+	}
+
+	// Create underlying OFREP provider for flag evaluation. The client has
+	// no timeout of its own, so the typed evaluations bound each call with
+	// requestContext instead
 	ofrepOpts := []ofrep.Option{
+		ofrep.WithClient(p.ofrepClient()),
 		ofrep.WithHeader("X-API-Key", p.apiKey),
-		ofrep.WithHeader("X-Flipswitch-SDK", p.getTelemetrySdkHeader()),
-		ofrep.WithHeader("X-Flipswitch-Runtime", p.getTelemetryRuntimeHeader()),
-		ofrep.WithHeader("X-Flipswitch-OS", p.getTelemetryOsHeader()),
-		ofrep.WithHeader("X-Flipswitch-Features", p.getTelemetryFeaturesHeader()),
+		ofrep.WithHeader(protocolHeader, protocolValue),
+		ofrep.WithHeader("User-Agent", p.userAgent),
+	}
+	for key, values := range p.telemetry {
+		ofrepOpts = append(ofrepOpts, ofrep.WithHeader(key, values[0]))
+	}
+	if p.connectionIDOnEvaluations {
+		ofrepOpts = append(ofrepOpts, ofrep.WithHeader(connectionIDHeader, p.connectionID))
+	}
+	for key, values := range p.customHeaders {
+		ofrepOpts = append(ofrepOpts, ofrep.WithHeader(key, values[0]))
 	}
 
-	// Note: OFREP provider automatically appends /ofrep/v1 to the baseUrl
+	// Note: OFREP provider automatically appends /ofrep/v1 to the baseUrl;
+	// ofrepClient rewrites it to a custom prefix
 	p.ofrepProvider = ofrep.NewProvider(
 		p.baseURL,
 		ofrepOpts...,
@@ -134,32 +421,6 @@ func NewProvider(apiKey string, opts ...Option) (*FlipswitchProvider, error) {
 	return p, nil
 }
 
-func (p *FlipswitchProvider) getTelemetrySdkHeader() string {
-	return "go/" + sdkVersion
-}
-
-func (p *FlipswitchProvider) getTelemetryRuntimeHeader() string {
-	return "go/" + runtime.Version()[2:] // Strip "go" prefix from go1.21.0
-}
-
-func (p *FlipswitchProvider) getTelemetryOsHeader() string {
-	return runtime.GOOS + "/" + runtime.GOARCH
-}
-
-func (p *FlipswitchProvider) getTelemetryFeaturesHeader() string {
-	if p.enableRealtime {
-		return "sse=true"
-	}
-	return "sse=false"
-}
-
-func (p *FlipswitchProvider) setTelemetryHeaders(req *http.Request) {
-	req.Header.Set("X-Flipswitch-SDK", p.getTelemetrySdkHeader())
-	req.Header.Set("X-Flipswitch-Runtime", p.getTelemetryRuntimeHeader())
-	req.Header.Set("X-Flipswitch-OS", p.getTelemetryOsHeader())
-	req.Header.Set("X-Flipswitch-Features", p.getTelemetryFeaturesHeader())
-}
-
 // Option is a functional option for configuring the provider.
 type Option func(*FlipswitchProvider)
 
@@ -167,6 +428,7 @@ type Option func(*FlipswitchProvider)
 func WithBaseURL(url string) Option {
 	return func(p *FlipswitchProvider) {
 		p.baseURL = url
+		p.baseURLSet = true
 	}
 }
 
@@ -177,7 +439,10 @@ func WithRealtime(enabled bool) Option {
 	}
 }
 
-// WithHTTPClient sets a custom HTTP client.
+// WithHTTPClient sets a custom HTTP client. It is used for evaluations, and
+// for the SSE connection too, so its transport (proxy, TLS configuration)
+// applies to the event stream; the SSE connection uses a copy without the
+// client's Timeout, which would cut the stream off, and without middleware.
 func WithHTTPClient(client *http.Client) Option {
 	return func(p *FlipswitchProvider) {
 		p.httpClient = client
@@ -205,6 +470,42 @@ func WithMaxSseRetries(retries int) Option {
 	}
 }
 
+// WithSseRetryBounds sets the bounds of the SSE reconnect backoff (default 1s
+// and 30s). The delay starts at min, doubles after every failed attempt up to
+// max, and returns to min after a clean disconnect; each wait is jittered
+// down to half the delay. Both must be positive and min must not exceed max,
+// otherwise NewProvider returns an error.
+func WithSseRetryBounds(min, max time.Duration) Option {
+	return func(p *FlipswitchProvider) {
+		p.sseMinRetryDelay = min
+		p.sseMaxRetryDelay = max
+	}
+}
+
+// WithReadyAfterFirstSync makes Init bulk-evaluate every flag for the
+// initialization context before reporting ready, so that ProviderReady means
+// flags are available rather than only that the API key was accepted. The
+// results populate the cache when WithCache is set. Init fails if the sync
+// fails.
+func WithReadyAfterFirstSync(enabled bool) Option {
+	return func(p *FlipswitchProvider) {
+		p.readyAfterFirstSync = enabled
+	}
+}
+
+// WithSseEventMapping maps custom SSE event names to the change types the SDK
+// understands, for servers that name their events differently (for example
+// "flag.updated" instead of "flag-updated"). Unmapped names keep their
+// default meaning.
+func WithSseEventMapping(mapping map[string]ChangeType) Option {
+	return func(p *FlipswitchProvider) {
+		p.sseEventMapping = make(map[string]ChangeType, len(mapping))
+		for name, changeType := range mapping {
+			p.sseEventMapping[name] = changeType
+		}
+	}
+}
+
 // Metadata returns the provider metadata.
 func (p *FlipswitchProvider) Metadata() openfeature.Metadata {
 	return openfeature.Metadata{
@@ -214,7 +515,25 @@ func (p *FlipswitchProvider) Metadata() openfeature.Metadata {
 
 // Init initializes the provider. Validates the API key and starts SSE connection
 // if real-time is enabled.
+//
+// If bootstrap flags are configured and the server cannot be reached, Init
+// succeeds anyway and the provider is marked stale, serving the bootstrapped
+// values until the connection recovers: until the SSE connection is
+// established or, without realtime updates, a bulk evaluation or poll
+// succeeds. An invalid API key always fails, as
+// does a server that does not support this SDK's protocol version, with a
+// *ProtocolMismatchError. The OpenFeature SDK emits ProviderReady or
+// ProviderError itself when Init returns; a stale start is emitted on
+// EventChannel as ProviderStale after that.
 func (p *FlipswitchProvider) Init(evaluationContext openfeature.EvaluationContext) error {
+	return p.InitWithContext(context.Background(), evaluationContext)
+}
+
+// InitWithContext is like Init, but the startup jitter and the initial
+// request are abandoned when ctx is cancelled or its deadline passes, in
+// which case the context's error is returned. OpenFeature calls it when the
+// provider is set with a context.
+func (p *FlipswitchProvider) InitWithContext(ctx context.Context, evaluationContext openfeature.EvaluationContext) error {
 	// Prevent double initialization (OpenFeature may call Init multiple times)
 	p.mu.Lock()
 	if p.initialized {
@@ -223,9 +542,42 @@ func (p *FlipswitchProvider) Init(evaluationContext openfeature.EvaluationContex
 	}
 	p.mu.Unlock()
 
+	if err := p.waitStartupJitter(ctx); err != nil {
+		return fmt.Errorf("initialization cancelled: %w", err)
+	}
+
+	p.envOverrides.load()
+
+	if p.offlineMode {
+		p.mu.Lock()
+		p.initialized = true
+		p.mu.Unlock()
+		p.setStatus(openfeature.ReadyState)
+		p.logger.Infow("Provider initialized in offline mode")
+		return nil
+	}
+
+	p.loadSnapshot()
+	p.setInitContext(flattenContext(evaluationContext))
+
+	stale := false
+
 	// Validate API key first (OFREP provider doesn't throw on auth errors during init)
-	if err := p.validateAPIKey(); err != nil {
-		return err
+	check := p.validateAPIKey
+	if p.readyAfterFirstSync {
+		check = func(ctx context.Context) error { return p.firstSync(ctx, evaluationContext) }
+	}
+	if err := check(ctx); err != nil {
+		var mismatch *ProtocolMismatchError
+		if errors.Is(err, ErrInvalidAPIKey) || errors.As(err, &mismatch) || !p.bootstrap.hasFlags() {
+			p.setStatus(openfeature.ErrorState)
+			return err
+		}
+		p.logger.Warnw("Serving bootstrapped flags, provider is stale", errorFields(err)...)
+		// Set before the SSE connection or polling starts, so that either
+		// can already clear it
+		p.setStatus(openfeature.StaleState)
+		stale = true
 	}
 
 	// Start SSE connection for real-time updates
@@ -237,12 +589,75 @@ func (p *FlipswitchProvider) Init(evaluationContext openfeature.EvaluationContex
 	p.initialized = true
 	p.mu.Unlock()
 
-	log.Printf("[Flipswitch] Provider initialized (realtime=%v)", p.enableRealtime)
+	p.startKeyRevalidation()
+	p.startBackgroundEvaluation()
+	if p.pollingMode {
+		p.startPolling()
+	}
+
+	// The OpenFeature SDK emits ProviderReady itself once Init returns, so
+	// a stale start is only reported after that or it would be overridden
+	if stale {
+		defer func() { go p.markInitStale() }()
+	} else {
+		p.setStatus(openfeature.ReadyState)
+	}
+
+	p.logger.Infow("Provider initialized", "realtime", p.enableRealtime)
+	return nil
+}
+
+// Status returns the current state of the provider.
+func (p *FlipswitchProvider) Status() openfeature.State {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.status
+}
+
+func (p *FlipswitchProvider) setStatus(status openfeature.State) {
+	p.mu.Lock()
+	p.status = status
+	p.mu.Unlock()
+}
+
+// emitEvent sends a provider event without blocking if the channel is full.
+func (p *FlipswitchProvider) emitEvent(eventType openfeature.EventType, message string) {
+	p.emitEventDetails(eventType, openfeature.ProviderEventDetails{Message: message})
+}
+
+// emitEventDetails is emitEvent for events that carry more than a message.
+func (p *FlipswitchProvider) emitEventDetails(eventType openfeature.EventType, details openfeature.ProviderEventDetails) {
+	event := openfeature.Event{
+		ProviderName:         "flipswitch",
+		EventType:            eventType,
+		ProviderEventDetails: details,
+	}
+	select {
+	case p.eventChan <- event:
+	default:
+		p.logger.Warnw("Event channel full, dropping event", "eventType", eventType)
+	}
+}
+
+// validateAPIKey checks the API key with a bulk evaluation request, and the
+// protocol versions the server announces in its response.
+func (p *FlipswitchProvider) validateAPIKey(ctx context.Context) error {
+	statusCode, err := p.checkAPIKey(ctx)
+	if err != nil {
+		return err
+	}
+	if statusCode >= 500 {
+		return fmt.Errorf("failed to connect to Flipswitch: %d", statusCode)
+	}
 	return nil
 }
 
-func (p *FlipswitchProvider) validateAPIKey() error {
-	url := p.baseURL + "/ofrep/v1/evaluate/flags"
+// checkAPIKey makes the lightweight bulk evaluation request behind
+// validateAPIKey and Ping and returns its status code. A rejected API key
+// gives ErrInvalidAPIKey, and an incompatible server a
+// *ProtocolMismatchError.
+func (p *FlipswitchProvider) checkAPIKey(ctx context.Context) (int, error) {
+	url := p.baseURL + p.ofrepPrefix + "/evaluate/flags"
 
 	body := map[string]interface{}{
 		"context": map[string]string{
@@ -251,88 +666,140 @@ func (p *FlipswitchProvider) validateAPIKey() error {
 	}
 	bodyBytes, _ := json.Marshal(body)
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(bodyBytes))
+	ctx, cancel := p.requestContext(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-API-Key", p.apiKey)
+	setProtocolHeader(req)
+	p.setUserAgent(req)
 	p.setTelemetryHeaders(req)
+	p.setCustomHeaders(req)
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to connect to Flipswitch: %w", err)
+		return 0, fmt.Errorf("failed to connect to Flipswitch: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 401 || resp.StatusCode == 403 {
-		return errors.New("invalid API key")
+		return resp.StatusCode, ErrInvalidAPIKey
 	}
 
-	if resp.StatusCode >= 500 {
-		return fmt.Errorf("failed to connect to Flipswitch: %d", resp.StatusCode)
+	if err := p.checkProtocol(resp.Header); err != nil {
+		return resp.StatusCode, err
 	}
 
+	return resp.StatusCode, nil
+}
+
+// firstSync bulk-evaluates all flags for the initialization context and
+// populates the cache with the results.
+func (p *FlipswitchProvider) firstSync(ctx context.Context, evaluationContext openfeature.EvaluationContext) error {
+	if err := p.warmContext(ctx, flattenContext(evaluationContext)); err != nil {
+		return fmt.Errorf("initial flag sync failed: %w", err)
+	}
 	return nil
 }
 
-// Shutdown shuts down the provider and closes all connections.
+// Shutdown shuts down the provider and closes all connections. It returns
+// once the SSE connection goroutine has exited, see ShutdownContext.
 func (p *FlipswitchProvider) Shutdown() {
+	_ = p.ShutdownContext(context.Background())
+}
+
+// ShutdownContext shuts down the provider and closes all connections, then
+// waits until the SSE connection goroutine has exited, including a reconnect
+// it was waiting to make. If ctx is done first, it returns ctx.Err(); the
+// provider is shut down regardless and the goroutine exits shortly after.
+//
+// Flag change and connection status listeners run on the SSE goroutine, so
+// a listener that shuts the provider down must do so from another goroutine
+// or pass a context with a deadline.
+func (p *FlipswitchProvider) ShutdownContext(ctx context.Context) error {
 	// Stop polling if active
 	p.stopPolling()
+	p.stopKeyRevalidation()
+	p.stopBackgroundEvaluation()
 
-	if p.sseClient != nil {
-		p.sseClient.Close()
-		p.sseClient = nil
+	p.mu.Lock()
+	client := p.sseClient
+	p.sseClient = nil
+	p.mu.Unlock()
+	var err error
+	if client != nil {
+		err = client.CloseContext(ctx)
 	}
+	p.stopChangeDebounce()
 
 	p.mu.Lock()
 	p.initialized = false
+	p.status = openfeature.NotReadyState
 	p.mu.Unlock()
 
-	log.Println("[Flipswitch] Provider shut down")
+	p.logger.Infow("Provider shut down")
+	return err
+}
+
+// ShutdownWithContext is ShutdownContext under the name used by
+// openfeature.ContextAwareStateHandler, which the provider implements
+// together with InitWithContext.
+func (p *FlipswitchProvider) ShutdownWithContext(ctx context.Context) error {
+	return p.ShutdownContext(ctx)
 }
 
 // startPollingFallback starts polling when SSE fails.
 func (p *FlipswitchProvider) startPollingFallback() {
+	if !p.enablePollingFallback || !p.startPolling() {
+		return
+	}
+	p.logger.Infow("Starting polling fallback", "interval", p.pollingInterval)
+	p.notifyFallbackChange(true)
+}
+
+// startPolling starts polling for flag changes every pollingInterval, unless
+// polling is already active. It reports whether it started polling.
+func (p *FlipswitchProvider) startPolling() bool {
 	p.mu.Lock()
-	if p.pollingActive || !p.enablePollingFallback {
+	if p.pollingActive {
 		p.mu.Unlock()
-		return
+		return false
 	}
 
-	log.Printf("[Flipswitch] Starting polling fallback (interval: %v)", p.pollingInterval)
 	p.pollingActive = true
-	p.pollingTicker = time.NewTicker(p.pollingInterval)
-	tickerC := p.pollingTicker.C
+	p.pollSnapshot = nil
+	p.pollingTicker = p.clock.NewTicker(p.pollingInterval)
+	tickerC := p.pollingTicker.Chan()
+	done := make(chan struct{})
+	p.pollingDone = done
 	p.mu.Unlock()
 
 	go func() {
+		// Poll once right away so that the first tick already has a
+		// snapshot to compare against
+		p.pollFlags()
 		for {
 			select {
-			case <-p.pollingDone:
+			case <-done:
 				return
 			case <-tickerC:
 				p.pollFlags()
 			}
 		}
 	}()
+	return true
 }
 
-// pollFlags polls for flag updates.
-func (p *FlipswitchProvider) pollFlags() {
-	// The OFREP Go provider doesn't expose cache invalidation,
-	// but flag evaluations will refetch on next call
-	log.Println("[Flipswitch] Polling: checking for flag updates")
-}
-
-// stopPolling stops the polling fallback.
+// stopPolling stops polling, whether it is the fallback or polling mode.
 func (p *FlipswitchProvider) stopPolling() {
 	p.mu.Lock()
-	defer p.mu.Unlock()
-
 	if !p.pollingActive {
+		p.mu.Unlock()
 		return
 	}
 
@@ -341,11 +808,41 @@ func (p *FlipswitchProvider) stopPolling() {
 		p.pollingTicker.Stop()
 		p.pollingTicker = nil
 	}
+	close(p.pollingDone)
+	p.pollingDone = nil
+	p.mu.Unlock()
 
-	select {
-	case p.pollingDone <- true:
-	default:
+	if !p.pollingMode {
+		p.notifyFallbackChange(false)
+	}
+}
+
+// OnFallbackStateChange sets a handler called with true when the provider
+// falls back to polling because SSE keeps failing, and with false when
+// polling stops, either because SSE reconnected or the provider shut down.
+// It replaces any handler set before; nil removes it. A panic in the handler
+// is recovered and logged.
+func (p *FlipswitchProvider) OnFallbackStateChange(handler func(active bool)) {
+	p.mu.Lock()
+	p.onFallbackChange = handler
+	p.mu.Unlock()
+}
+
+// notifyFallbackChange calls the OnFallbackStateChange handler, if any.
+func (p *FlipswitchProvider) notifyFallbackChange(active bool) {
+	p.mu.RLock()
+	handler := p.onFallbackChange
+	p.mu.RUnlock()
+	if handler == nil {
+		return
 	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Errorw("Error in fallback state change handler", "active", active, "panic", r)
+		}
+	}()
+	handler(active)
 }
 
 // IsPollingActive returns whether polling fallback is active.
@@ -356,24 +853,43 @@ func (p *FlipswitchProvider) IsPollingActive() bool {
 }
 
 func (p *FlipswitchProvider) startSseConnection() {
-	telemetryHeaders := p.getTelemetryHeaders()
-	p.sseClient = NewSseClient(
+	client := p.newSseClient()
+	p.mu.Lock()
+	p.sseClient = client
+	p.mu.Unlock()
+	client.Connect()
+}
+
+// currentSseClient returns the SSE client, or nil if there is none.
+func (p *FlipswitchProvider) currentSseClient() *SseClient {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.sseClient
+}
+
+// newSseClient creates an SSE client wired to this provider's handlers.
+func (p *FlipswitchProvider) newSseClient() *SseClient {
+	return NewSseClient(
 		p.baseURL,
 		p.apiKey,
-		telemetryHeaders,
-		p.handleFlagChange,
+		p.getTelemetryHeaders(),
+		p.handleSseFlagChange,
 		p.handleStatusChange,
+		withSseRand(p.rng),
+		WithSseLogger(p.logger),
+		withSseEventMapping(p.sseEventMapping),
+		withSseRetryBounds(p.sseMinRetryDelay, p.sseMaxRetryDelay),
+		withSseReadTimeout(p.sseReadTimeout),
+		withSseErrorHandler(func(err error) {
+			p.recordError(OperationSseConnection, "", err)
+		}),
+		WithSseConnectionID(p.connectionID),
+		withSseHeaders(p.customHeaders),
+		withSseUserAgent(p.userAgent),
+		withSsePath(p.ssePath),
+		WithSseHTTPClient(p.sseHTTPClient),
+		withSseClock(p.clock),
 	)
-	p.sseClient.Connect()
-}
-
-func (p *FlipswitchProvider) getTelemetryHeaders() map[string]string {
-	return map[string]string{
-		"X-Flipswitch-SDK":      p.getTelemetrySdkHeader(),
-		"X-Flipswitch-Runtime":  p.getTelemetryRuntimeHeader(),
-		"X-Flipswitch-OS":       p.getTelemetryOsHeader(),
-		"X-Flipswitch-Features": p.getTelemetryFeaturesHeader(),
-	}
 }
 
 // EventChannel returns the channel for OpenFeature provider events.
@@ -387,20 +903,28 @@ func (p *FlipswitchProvider) handleFlagChange(event FlagChangeEvent) {
 	// Note: The OFREP Go provider uses in-memory caching that gets refreshed
 	// on the next evaluation call, so we just need to notify listeners
 	// that configuration has changed
+	p.invalidateCacheFor(event)
+	p.reevaluate(event)
+	p.scheduleRefresh(event)
 
 	// Emit OpenFeature ProviderConfigChange event
 	ofEvent := openfeature.Event{
-		ProviderName: "flipswitch",
-		EventType:    openfeature.ProviderConfigChange,
+		ProviderName:         "flipswitch",
+		EventType:            openfeature.ProviderConfigChange,
 		ProviderEventDetails: openfeature.ProviderEventDetails{},
 	}
 	if event.FlagKey != "" {
 		ofEvent.FlagChanges = []string{event.FlagKey}
+	} else if len(event.AffectedKeys) > 0 {
+		ofEvent.FlagChanges = event.AffectedKeys
 	}
 	select {
 	case p.eventChan <- ofEvent:
 	default:
-		log.Println("[Flipswitch] Event channel full, dropping config change event")
+		p.logger.Warnw("Event channel full, dropping event", "eventType", openfeature.ProviderConfigChange, "flagKey", event.FlagKey)
+		p.mu.Lock()
+		p.changesDropped = true
+		p.mu.Unlock()
 	}
 
 	// Snapshot global listeners
@@ -417,14 +941,24 @@ func (p *FlipswitchProvider) handleFlagChange(event FlagChangeEvent) {
 		if listeners, ok := p.keyFlagChangeListeners[event.FlagKey]; ok {
 			keyListeners = make([]FlagChangeHandler, 0, len(listeners))
 			for _, listener := range listeners {
-				keyListeners = append(keyListeners, listener)
+				keyListeners = append(keyListeners, listener.handler)
+			}
+		}
+	} else if len(event.AffectedKeys) > 0 {
+		// Scoped bulk invalidation — fire listeners for the affected keys only
+		for _, key := range event.AffectedKeys {
+			for _, listener := range p.keyFlagChangeListeners[key] {
+				keyListeners = append(keyListeners, listener.handler)
 			}
 		}
 	} else {
-		// Bulk invalidation — fire ALL key-specific listeners
+		// Bulk invalidation — fire all key-specific listeners except those
+		// registered with OnlyExactKey
 		for _, listeners := range p.keyFlagChangeListeners {
 			for _, listener := range listeners {
-				keyListeners = append(keyListeners, listener)
+				if !listener.exactOnly {
+					keyListeners = append(keyListeners, listener.handler)
+				}
 			}
 		}
 	}
@@ -435,7 +969,7 @@ func (p *FlipswitchProvider) handleFlagChange(event FlagChangeEvent) {
 		func() {
 			defer func() {
 				if r := recover(); r != nil {
-					log.Printf("[Flipswitch] Error in flag change listener: %v", r)
+					p.logger.Errorw("Error in flag change listener", "flagKey", event.FlagKey, "panic", r)
 				}
 			}()
 			listener(event)
@@ -447,7 +981,7 @@ func (p *FlipswitchProvider) handleFlagChange(event FlagChangeEvent) {
 		func() {
 			defer func() {
 				if r := recover(); r != nil {
-					log.Printf("[Flipswitch] Error in flag change listener: %v", r)
+					p.logger.Errorw("Error in flag change listener", "flagKey", event.FlagKey, "panic", r)
 				}
 			}()
 			listener(event)
@@ -456,20 +990,45 @@ func (p *FlipswitchProvider) handleFlagChange(event FlagChangeEvent) {
 }
 
 func (p *FlipswitchProvider) handleStatusChange(status ConnectionStatus) {
-	if status == StatusError {
+	p.publishStatus(status)
+	p.notifyStatusListeners(status)
+
+	if status == StatusConnecting {
+		p.mu.Lock()
+		reconnect := p.sseConnectStarted
+		p.sseConnectStarted = true
+		if reconnect {
+			p.sseReconnects++
+		}
+		p.mu.Unlock()
+
+		if reconnect {
+			p.metrics.observeSseReconnect()
+		}
+	} else if status == StatusError {
 		p.mu.Lock()
 		p.sseRetryCount++
 		retryCount := p.sseRetryCount
 		maxRetries := p.maxSseRetries
 		p.mu.Unlock()
 
-		log.Printf("[Flipswitch] SSE connection error (retry %d), provider is stale", retryCount)
+		p.logger.Warnw("SSE connection error, provider is stale", "retry", retryCount)
+
+		if retryCount < maxRetries {
+			p.markSseStale()
+		} else {
+			p.markSseFailed(retryCount)
+		}
 
 		// Check if we should fall back to polling
 		if retryCount >= maxRetries && p.enablePollingFallback {
-			log.Printf("[Flipswitch] SSE failed after %d retries - falling back to polling", retryCount)
+			p.logger.Warnw("SSE failed, falling back to polling", "retries", retryCount)
 			p.startPollingFallback()
 		}
+	} else if status == StatusDisconnected {
+		p.markSseStale()
+	} else if status == StatusUnauthorized {
+		p.fatal("API key was rejected by the Flipswitch event stream")
 	} else if status == StatusConnected {
 		// SSE connected - reset retry count and stop polling
 		p.mu.Lock()
@@ -477,78 +1036,202 @@ func (p *FlipswitchProvider) handleStatusChange(status ConnectionStatus) {
 		wasPolling := p.pollingActive
 		p.mu.Unlock()
 
+		p.markSseRestored()
+
 		if wasPolling {
-			log.Println("[Flipswitch] SSE reconnected - stopping polling fallback")
+			p.logger.Infow("SSE reconnected - stopping polling fallback")
 			p.stopPolling()
 		}
 
-		log.Println("[Flipswitch] SSE connection restored")
+		p.logger.Infow("SSE connection restored")
+
+		if p.catchUpOnReconnect {
+			p.catchUp()
+		}
 	}
 }
 
 // AddFlagChangeListener adds a listener for all flag change events.
 // Returns a CancelFunc that removes the listener when called.
 func (p *FlipswitchProvider) AddFlagChangeListener(handler FlagChangeHandler) CancelFunc {
+	id := p.AddFlagChangeListenerWithID(handler)
+	return func() {
+		p.RemoveFlagChangeListenerByID(id)
+	}
+}
+
+// AddFlagChangeListenerWithID is like AddFlagChangeListener, but returns an
+// ID identifying the registration, for callers that would rather store an ID
+// than a function. Pass it to RemoveFlagChangeListenerByID to remove the
+// listener.
+func (p *FlipswitchProvider) AddFlagChangeListenerWithID(handler FlagChangeHandler) ListenerID {
 	p.mu.Lock()
 	id := p.nextListenerID
 	p.nextListenerID++
 	p.flagChangeListeners[id] = handler
+	exceeded := p.listenerAdded()
 	p.mu.Unlock()
 
-	return func() {
-		p.mu.Lock()
-		defer p.mu.Unlock()
-		delete(p.flagChangeListeners, id)
+	if exceeded {
+		p.warnListenerLimit()
+	}
+	return ListenerID(id)
+}
+
+// RemoveFlagChangeListenerByID removes the listener registered with
+// AddFlagChangeListenerWithID. Other listeners, including ones registered
+// with the same handler, are unaffected. Removing a listener that was
+// already removed does nothing.
+func (p *FlipswitchProvider) RemoveFlagChangeListenerByID(id ListenerID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.flagChangeListeners[int(id)]; ok {
+		delete(p.flagChangeListeners, int(id))
+		p.listenerRemoved()
 	}
 }
 
 // AddFlagKeyChangeListener adds a listener for changes to a specific flag key.
 // The listener fires on targeted changes matching the key AND on bulk
-// invalidations (events with empty FlagKey).
+// invalidations (events with empty FlagKey). When a bulk invalidation reports
+// its AffectedKeys, only listeners for those keys fire. Use
+// AddFlagKeyChangeListenerOpts with OnlyExactKey to skip bulk invalidations
+// that do not name the key.
 // Returns a CancelFunc that removes the listener when called.
 func (p *FlipswitchProvider) AddFlagKeyChangeListener(flagKey string, handler FlagChangeHandler) CancelFunc {
+	return p.AddFlagKeyChangeListenerOpts(flagKey, handler)
+}
+
+// RemoveFlagChangeListener is deprecated. Use the CancelFunc returned by
+// AddFlagChangeListener or AddFlagKeyChangeListener, or
+// RemoveFlagChangeListenerByID, instead.
+//
+// Deprecated: Function pointer comparison is unreliable in Go.
+func (p *FlipswitchProvider) RemoveFlagChangeListener(handler FlagChangeHandler) {
+	// No-op: kept for backward compatibility.
+	// Callers should use the CancelFunc returned by AddFlagChangeListener.
+}
+
+// AddConnectionStatusListener adds a listener called with every SSE
+// connection status transition: StatusConnecting, StatusConnected,
+// StatusError, StatusUnauthorized and StatusDisconnected, including the
+// transitions caused by ReconnectSse and Shutdown. Listeners run on the SSE
+// goroutine, so they should return quickly; a panicking listener is logged
+// and does not affect the others. Returns a CancelFunc that removes the
+// listener when called.
+func (p *FlipswitchProvider) AddConnectionStatusListener(handler ConnectionStatusHandler) CancelFunc {
 	p.mu.Lock()
 	id := p.nextListenerID
 	p.nextListenerID++
-	if _, ok := p.keyFlagChangeListeners[flagKey]; !ok {
-		p.keyFlagChangeListeners[flagKey] = make(map[int]FlagChangeHandler)
-	}
-	p.keyFlagChangeListeners[flagKey][id] = handler
+	p.statusListeners[id] = handler
 	p.mu.Unlock()
 
 	return func() {
 		p.mu.Lock()
 		defer p.mu.Unlock()
-		if listeners, ok := p.keyFlagChangeListeners[flagKey]; ok {
-			delete(listeners, id)
-			if len(listeners) == 0 {
-				delete(p.keyFlagChangeListeners, flagKey)
-			}
-		}
+		delete(p.statusListeners, id)
 	}
 }
 
-// RemoveFlagChangeListener is deprecated. Use the CancelFunc returned by
-// AddFlagChangeListener or AddFlagKeyChangeListener instead.
-//
-// Deprecated: Function pointer comparison is unreliable in Go.
-func (p *FlipswitchProvider) RemoveFlagChangeListener(handler FlagChangeHandler) {
-	// No-op: kept for backward compatibility.
-	// Callers should use the CancelFunc returned by AddFlagChangeListener.
+// notifyStatusListeners calls every AddConnectionStatusListener listener
+// with status.
+func (p *FlipswitchProvider) notifyStatusListeners(status ConnectionStatus) {
+	p.mu.RLock()
+	listeners := make([]ConnectionStatusHandler, 0, len(p.statusListeners))
+	for _, listener := range p.statusListeners {
+		listeners = append(listeners, listener)
+	}
+	p.mu.RUnlock()
+
+	for _, listener := range listeners {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					p.logger.Errorw("Error in connection status listener", "status", status, "panic", r)
+				}
+			}()
+			listener(status)
+		}()
+	}
+}
+
+// StatusChanges returns a channel that receives SSE connection status
+// transitions, and a function that unsubscribes and closes the channel.
+// The channel is buffered; if the consumer falls behind, the oldest pending
+// status is dropped so the SSE connection is never blocked.
+func (p *FlipswitchProvider) StatusChanges() (<-chan ConnectionStatus, func()) {
+	ch := make(chan ConnectionStatus, statusChannelBuffer)
+
+	p.mu.Lock()
+	id := p.nextListenerID
+	p.nextListenerID++
+	p.statusSubscribers[id] = ch
+	p.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			delete(p.statusSubscribers, id)
+			close(ch)
+		})
+	}
+}
+
+// publishStatus delivers status to every StatusChanges subscriber,
+// dropping the oldest buffered value when a subscriber's buffer is full.
+func (p *FlipswitchProvider) publishStatus(status ConnectionStatus) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, ch := range p.statusSubscribers {
+		for {
+			select {
+			case ch <- status:
+			default:
+				// Buffer full - drop the oldest value and try again
+				select {
+				case <-ch:
+				default:
+				}
+				continue
+			}
+			break
+		}
+	}
 }
 
 // GetSseStatus returns the current SSE connection status.
 func (p *FlipswitchProvider) GetSseStatus() ConnectionStatus {
-	if p.sseClient != nil {
-		return p.sseClient.GetStatus()
+	if client := p.currentSseClient(); client != nil {
+		return client.GetStatus()
 	}
 	return StatusDisconnected
 }
 
+// GetSseRetryDelay returns the base delay before the next SSE reconnect
+// attempt, see SseClient.RetryDelay, or zero without an SSE connection.
+func (p *FlipswitchProvider) GetSseRetryDelay() time.Duration {
+	if client := p.currentSseClient(); client != nil {
+		return client.RetryDelay()
+	}
+	return 0
+}
+
+// GetSseReconnectAttempts returns the number of SSE reconnects scheduled
+// since the connection last succeeded, or zero without an SSE connection.
+func (p *FlipswitchProvider) GetSseReconnectAttempts() int {
+	if client := p.currentSseClient(); client != nil {
+		return client.ReconnectAttempts()
+	}
+	return 0
+}
+
 // ReconnectSse forces a reconnection of the SSE client.
 func (p *FlipswitchProvider) ReconnectSse() {
-	if p.enableRealtime && p.sseClient != nil {
-		p.sseClient.Close()
+	if client := p.currentSseClient(); p.enableRealtime && client != nil {
+		client.Close()
 		p.startSseConnection()
 	}
 }
@@ -568,8 +1251,55 @@ func (p *FlipswitchProvider) BooleanEvaluation(
 	flag string,
 	defaultValue bool,
 	evalCtx openfeature.FlattenedContext,
-) openfeature.BoolResolutionDetail {
-	return p.ofrepProvider.BooleanEvaluation(ctx, flag, defaultValue, evalCtx)
+) (result openfeature.BoolResolutionDetail) {
+	start, source := time.Now(), EvaluationSourceNetwork
+	defer func() {
+		p.observeResolution(flag, "boolean", start, source, result.Value, &result.ProviderResolutionDetail)
+	}()
+
+	if eval := p.envOverride(flag); eval != nil {
+		if v, ok := eval.Value.(bool); ok {
+			source = EvaluationSourceLocal
+			return openfeature.BoolResolutionDetail{Value: v, ProviderResolutionDetail: overrideResolutionDetail}
+		}
+	}
+
+	if p.killed(ctx, flag, evalCtx) {
+		source = EvaluationSourceLocal
+		return openfeature.BoolResolutionDetail{
+			Value:                    false,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{Reason: openfeature.DisabledReason},
+		}
+	}
+
+	if p.offlineMode {
+		source = EvaluationSourceLocal
+		eval, detail := p.offlineFlag(flag)
+		if v, ok := eval.Value.(bool); ok {
+			return openfeature.BoolResolutionDetail{Value: v, ProviderResolutionDetail: detail}
+		}
+		return openfeature.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: offlineMismatch(flag, detail)}
+	}
+
+	cached, store := p.sharedCacheLookup(ctx, flag, evalCtx)
+	if cached != nil {
+		if v, ok := cached.Value.(bool); ok {
+			source = EvaluationSourceCache
+			return openfeature.BoolResolutionDetail{Value: v, ProviderResolutionDetail: cachedResolutionDetail(*cached)}
+		}
+	}
+
+	ofrepCtx, cancel := p.requestContext(ctx)
+	result = p.ofrepProvider.BooleanEvaluation(ofrepCtx, flag, defaultValue, p.ofrepContext(evalCtx))
+	cancel()
+	store(result.Value, result.ProviderResolutionDetail)
+	if eval, fallback, ok := p.errorFallback(flag, evalCtx, result.ProviderResolutionDetail); ok {
+		if v, ok := eval.Value.(bool); ok {
+			source = EvaluationSourceCache
+			return openfeature.BoolResolutionDetail{Value: v, ProviderResolutionDetail: fallback}
+		}
+	}
+	return result
 }
 
 // StringEvaluation evaluates a string flag.
@@ -578,8 +1308,47 @@ func (p *FlipswitchProvider) StringEvaluation(
 	flag string,
 	defaultValue string,
 	evalCtx openfeature.FlattenedContext,
-) openfeature.StringResolutionDetail {
-	return p.ofrepProvider.StringEvaluation(ctx, flag, defaultValue, evalCtx)
+) (result openfeature.StringResolutionDetail) {
+	start, source := time.Now(), EvaluationSourceNetwork
+	defer func() {
+		p.observeResolution(flag, "string", start, source, result.Value, &result.ProviderResolutionDetail)
+	}()
+
+	if eval := p.envOverride(flag); eval != nil {
+		if v, ok := eval.Value.(string); ok {
+			source = EvaluationSourceLocal
+			return openfeature.StringResolutionDetail{Value: v, ProviderResolutionDetail: overrideResolutionDetail}
+		}
+	}
+
+	if p.offlineMode {
+		source = EvaluationSourceLocal
+		eval, detail := p.offlineFlag(flag)
+		if v, ok := eval.Value.(string); ok {
+			return openfeature.StringResolutionDetail{Value: v, ProviderResolutionDetail: detail}
+		}
+		return openfeature.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: offlineMismatch(flag, detail)}
+	}
+
+	cached, store := p.sharedCacheLookup(ctx, flag, evalCtx)
+	if cached != nil {
+		if v, ok := cached.Value.(string); ok {
+			source = EvaluationSourceCache
+			return openfeature.StringResolutionDetail{Value: v, ProviderResolutionDetail: cachedResolutionDetail(*cached)}
+		}
+	}
+
+	ofrepCtx, cancel := p.requestContext(ctx)
+	result = p.ofrepProvider.StringEvaluation(ofrepCtx, flag, defaultValue, p.ofrepContext(evalCtx))
+	cancel()
+	store(result.Value, result.ProviderResolutionDetail)
+	if eval, fallback, ok := p.errorFallback(flag, evalCtx, result.ProviderResolutionDetail); ok {
+		if v, ok := eval.Value.(string); ok {
+			source = EvaluationSourceCache
+			return openfeature.StringResolutionDetail{Value: v, ProviderResolutionDetail: fallback}
+		}
+	}
+	return result
 }
 
 // FloatEvaluation evaluates a float flag.
@@ -588,8 +1357,50 @@ func (p *FlipswitchProvider) FloatEvaluation(
 	flag string,
 	defaultValue float64,
 	evalCtx openfeature.FlattenedContext,
-) openfeature.FloatResolutionDetail {
-	return p.ofrepProvider.FloatEvaluation(ctx, flag, defaultValue, evalCtx)
+) (result openfeature.FloatResolutionDetail) {
+	start, source := time.Now(), EvaluationSourceNetwork
+	defer func() {
+		p.observeResolution(flag, "number", start, source, result.Value, &result.ProviderResolutionDetail)
+	}()
+
+	if eval := p.envOverride(flag); eval != nil {
+		if _, ok := eval.Value.(float64); ok {
+			source = EvaluationSourceLocal
+			return openfeature.FloatResolutionDetail{Value: eval.AsFloat(), ProviderResolutionDetail: overrideResolutionDetail}
+		}
+	}
+
+	if p.offlineMode {
+		source = EvaluationSourceLocal
+		eval, detail := p.offlineFlag(flag)
+		switch eval.Value.(type) {
+		case float64, int, int64:
+			return openfeature.FloatResolutionDetail{Value: eval.AsFloat(), ProviderResolutionDetail: detail}
+		}
+		return openfeature.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: offlineMismatch(flag, detail)}
+	}
+
+	cached, store := p.sharedCacheLookup(ctx, flag, evalCtx)
+	if cached != nil {
+		switch cached.Value.(type) {
+		case float64, int, int64:
+			source = EvaluationSourceCache
+			return openfeature.FloatResolutionDetail{Value: cached.AsFloat(), ProviderResolutionDetail: cachedResolutionDetail(*cached)}
+		}
+	}
+
+	ofrepCtx, cancel := p.requestContext(ctx)
+	result = p.ofrepProvider.FloatEvaluation(ofrepCtx, flag, defaultValue, p.ofrepContext(evalCtx))
+	cancel()
+	store(result.Value, result.ProviderResolutionDetail)
+	if eval, fallback, ok := p.errorFallback(flag, evalCtx, result.ProviderResolutionDetail); ok {
+		switch eval.Value.(type) {
+		case float64, int, int64:
+			source = EvaluationSourceCache
+			return openfeature.FloatResolutionDetail{Value: eval.AsFloat(), ProviderResolutionDetail: fallback}
+		}
+	}
+	return result
 }
 
 // IntEvaluation evaluates an integer flag.
@@ -598,8 +1409,50 @@ func (p *FlipswitchProvider) IntEvaluation(
 	flag string,
 	defaultValue int64,
 	evalCtx openfeature.FlattenedContext,
-) openfeature.IntResolutionDetail {
-	return p.ofrepProvider.IntEvaluation(ctx, flag, defaultValue, evalCtx)
+) (result openfeature.IntResolutionDetail) {
+	start, source := time.Now(), EvaluationSourceNetwork
+	defer func() {
+		p.observeResolution(flag, "integer", start, source, result.Value, &result.ProviderResolutionDetail)
+	}()
+
+	if eval := p.envOverride(flag); eval != nil {
+		if _, ok := eval.Value.(float64); ok {
+			source = EvaluationSourceLocal
+			return openfeature.IntResolutionDetail{Value: int64(eval.AsInt()), ProviderResolutionDetail: overrideResolutionDetail}
+		}
+	}
+
+	if p.offlineMode {
+		source = EvaluationSourceLocal
+		eval, detail := p.offlineFlag(flag)
+		switch eval.Value.(type) {
+		case int, int64, float64:
+			return openfeature.IntResolutionDetail{Value: int64(eval.AsInt()), ProviderResolutionDetail: detail}
+		}
+		return openfeature.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: offlineMismatch(flag, detail)}
+	}
+
+	cached, store := p.sharedCacheLookup(ctx, flag, evalCtx)
+	if cached != nil {
+		switch cached.Value.(type) {
+		case int, int64, float64:
+			source = EvaluationSourceCache
+			return openfeature.IntResolutionDetail{Value: int64(cached.AsInt()), ProviderResolutionDetail: cachedResolutionDetail(*cached)}
+		}
+	}
+
+	ofrepCtx, cancel := p.requestContext(ctx)
+	result = p.ofrepProvider.IntEvaluation(ofrepCtx, flag, defaultValue, p.ofrepContext(evalCtx))
+	cancel()
+	store(result.Value, result.ProviderResolutionDetail)
+	if eval, fallback, ok := p.errorFallback(flag, evalCtx, result.ProviderResolutionDetail); ok {
+		switch eval.Value.(type) {
+		case int, int64, float64:
+			source = EvaluationSourceCache
+			return openfeature.IntResolutionDetail{Value: int64(eval.AsInt()), ProviderResolutionDetail: fallback}
+		}
+	}
+	return result
 }
 
 // ObjectEvaluation evaluates an object flag.
@@ -608,14 +1461,72 @@ func (p *FlipswitchProvider) ObjectEvaluation(
 	flag string,
 	defaultValue interface{},
 	evalCtx openfeature.FlattenedContext,
-) openfeature.InterfaceResolutionDetail {
-	return p.ofrepProvider.ObjectEvaluation(ctx, flag, defaultValue, evalCtx)
+) (result openfeature.InterfaceResolutionDetail) {
+	start, source := time.Now(), EvaluationSourceNetwork
+	defer func() {
+		p.observeResolution(flag, "object", start, source, result.Value, &result.ProviderResolutionDetail)
+	}()
+
+	if eval := p.envOverride(flag); eval != nil {
+		source = EvaluationSourceLocal
+		return openfeature.InterfaceResolutionDetail{Value: eval.Value, ProviderResolutionDetail: overrideResolutionDetail}
+	}
+
+	if p.offlineMode {
+		source = EvaluationSourceLocal
+		eval, detail := p.offlineFlag(flag)
+		if eval.Value == nil {
+			return openfeature.InterfaceResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+		}
+		return openfeature.InterfaceResolutionDetail{Value: eval.Value, ProviderResolutionDetail: detail}
+	}
+
+	cached, store := p.sharedCacheLookup(ctx, flag, evalCtx)
+	if cached != nil {
+		source = EvaluationSourceCache
+		result = openfeature.InterfaceResolutionDetail{Value: cached.Value, ProviderResolutionDetail: cachedResolutionDetail(*cached)}
+		if invalid, ok := p.checkSchema(flag, defaultValue, result); ok {
+			return invalid
+		}
+		return result
+	}
+
+	ofrepCtx, cancel := p.requestContext(ctx)
+	result = p.ofrepProvider.ObjectEvaluation(ofrepCtx, flag, defaultValue, p.ofrepContext(evalCtx))
+	cancel()
+	p.learnSchema(flag, result.ProviderResolutionDetail)
+	if invalid, ok := p.checkSchema(flag, defaultValue, result); ok {
+		return invalid
+	}
+	store(result.Value, result.ProviderResolutionDetail)
+	if eval, fallback, ok := p.errorFallback(flag, evalCtx, result.ProviderResolutionDetail); ok {
+		source = EvaluationSourceCache
+		result = openfeature.InterfaceResolutionDetail{Value: eval.Value, ProviderResolutionDetail: fallback}
+		if invalid, ok := p.checkSchema(flag, defaultValue, result); ok {
+			return invalid
+		}
+	}
+	return result
 }
 
 // ===============================
 // Bulk Flag Evaluation (Direct HTTP - OFREP providers don't expose bulk API)
 // ===============================
 
+// flattenContext flattens evalCtx the way the OpenFeature client does: the
+// attributes are copied and a non-empty targeting key is stored under
+// "targetingKey", replacing any attribute of that name.
+func flattenContext(evalCtx openfeature.EvaluationContext) openfeature.FlattenedContext {
+	flat := openfeature.FlattenedContext{}
+	for key, value := range evalCtx.Attributes() {
+		flat[key] = value
+	}
+	if targetingKey := evalCtx.TargetingKey(); targetingKey != "" {
+		flat[openfeature.TargetingKey] = targetingKey
+	}
+	return flat
+}
+
 func transformContext(evalCtx openfeature.FlattenedContext) map[string]interface{} {
 	result := make(map[string]interface{})
 
@@ -626,17 +1537,24 @@ func transformContext(evalCtx openfeature.FlattenedContext) map[string]interface
 	return result
 }
 
+// inferType returns the flag type of value. A json.Number is an integer
+// unless it has a fraction or an exponent.
 func inferType(value interface{}) string {
 	if value == nil {
 		return "null"
 	}
-	switch value.(type) {
+	switch v := value.(type) {
 	case bool:
 		return "boolean"
-	case int, int64:
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
 		return "integer"
-	case float64:
+	case float32, float64:
 		return "number"
+	case json.Number:
+		if strings.ContainsAny(string(v), ".eE") {
+			return "number"
+		}
+		return "integer"
 	case string:
 		return "string"
 	case []interface{}:
@@ -683,117 +1601,467 @@ func isSuccess(statusCode int) bool {
 // Note: This method makes direct HTTP calls since OFREP providers don't expose
 // the bulk evaluation API.
 func (p *FlipswitchProvider) EvaluateAllFlags(evalCtx openfeature.FlattenedContext) []FlagEvaluation {
-	results := make([]FlagEvaluation, 0)
+	return p.EvaluateAllFlagsCtx(context.Background(), evalCtx)
+}
+
+// EvaluateAllFlagsCtx is like EvaluateAllFlags but sends the request with
+// ctx, and merges the baggage members named with WithBaggageAttributes into
+// the evaluation context. If ctx is cancelled or its deadline passes before
+// the response arrives, it returns what EvaluateAllFlags returns on a network
+// error.
+func (p *FlipswitchProvider) EvaluateAllFlagsCtx(ctx context.Context, evalCtx openfeature.FlattenedContext) []FlagEvaluation {
+	flags, _ := p.evaluateAllFlagsCtx(ctx, evalCtx)
+	return flags
+}
 
-	url := p.baseURL + "/ofrep/v1/evaluate/flags"
+// EvaluateAllFlagsE is like EvaluateAllFlags but also returns the error that
+// caused it to return no flags: ErrInvalidAPIKey, an *HTTPStatusError for
+// an unexpected status, or the wrapped network or parse error. If the server
+// is unavailable and the bootstrapped flags are served instead, they are
+// returned along with the error.
+func (p *FlipswitchProvider) EvaluateAllFlagsE(evalCtx openfeature.FlattenedContext) ([]FlagEvaluation, error) {
+	return p.evaluateAllFlagsCtx(context.Background(), evalCtx)
+}
 
-	body := map[string]interface{}{
-		"context": transformContext(evalCtx),
+// evaluateAllFlagsCtx implements EvaluateAllFlagsCtx and EvaluateAllFlagsE.
+func (p *FlipswitchProvider) evaluateAllFlagsCtx(ctx context.Context, evalCtx openfeature.FlattenedContext) ([]FlagEvaluation, error) {
+	start := time.Now()
+	evalCtx = p.withBaggage(ctx, evalCtx)
+	flags, err := p.fetchAllFlags(ctx, evalCtx)
+	if err != nil {
+		p.logger.Errorw("Error evaluating all flags", errorFields(err)...)
+		flags = make([]FlagEvaluation, 0)
+		if isUnavailable(err) && p.bootstrap.hasFlags() {
+			flags = p.bootstrap.all()
+		}
 	}
-	bodyBytes, _ := json.Marshal(body)
+	source := p.fetchSource()
+	if err != nil {
+		source = EvaluationSourceCache
+	}
+	flags = p.transformFlags(p.envOverrides.apply(flags))
+	p.observeAll(start, flags, source, err)
+	return flags, err
+}
+
+// EvaluateAllFlagsWithContext is like EvaluateAllFlags but takes an
+// unflattened evaluation context.
+func (p *FlipswitchProvider) EvaluateAllFlagsWithContext(evalCtx openfeature.EvaluationContext) []FlagEvaluation {
+	return p.EvaluateAllFlags(flattenContext(evalCtx))
+}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(bodyBytes))
+// EvaluateAllFlagsGrouped evaluates all flags like EvaluateAllFlags and
+// partitions the results by their evaluation reason (for example
+// "TARGETING_MATCH", "DEFAULT" or "ERROR"). Flags the server returned without
+// a reason are grouped under "".
+func (p *FlipswitchProvider) EvaluateAllFlagsGrouped(evalCtx openfeature.FlattenedContext) (map[string][]FlagEvaluation, error) {
+	flags, err := p.evaluateAllFlagsCtx(context.Background(), evalCtx)
 	if err != nil {
-		log.Printf("[Flipswitch] Error evaluating all flags: %v", err)
-		return results
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", p.apiKey)
-	p.setTelemetryHeaders(req)
+	grouped := make(map[string][]FlagEvaluation)
+	for _, flag := range flags {
+		grouped[flag.Reason] = append(grouped[flag.Reason], flag)
+	}
+	return grouped, nil
+}
 
-	resp, err := p.httpClient.Do(req)
+// Ready performs a real bulk evaluation and returns an error if it fails.
+// Unlike the API key check made during Init, it verifies that flag
+// evaluation works end to end, which makes it suitable for readiness probes.
+// Bootstrapped flags are never used to satisfy it.
+func (p *FlipswitchProvider) Ready(ctx context.Context) error {
+	_, err := p.fetchAllFlags(ctx, openfeature.FlattenedContext{"targetingKey": "_ready_"})
 	if err != nil {
-		log.Printf("[Flipswitch] Error evaluating all flags: %v", err)
-		return results
+		return fmt.Errorf("flipswitch not ready: %w", err)
 	}
-	defer resp.Body.Close()
+	return nil
+}
 
-	if !isSuccess(resp.StatusCode) {
-		log.Printf("[Flipswitch] Failed to evaluate all flags: %d", resp.StatusCode)
-		return results
+// HTTPStatusError reports an unexpected HTTP status from the Flipswitch
+// server.
+type HTTPStatusError struct {
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+	// ErrorCode is the OFREP error code in the response body, such as
+	// PARSE_ERROR, or empty if there was none.
+	ErrorCode string
+}
+
+func (e *HTTPStatusError) Error() string {
+	if e.ErrorCode != "" {
+		return "unexpected status: " + intToString(e.StatusCode) + " (" + e.ErrorCode + ")"
+	}
+	return "unexpected status: " + intToString(e.StatusCode)
+}
+
+// responseErrorCode returns the OFREP error code in an error response body,
+// or "" if there is none.
+func responseErrorCode(respBody []byte) string {
+	var data struct {
+		ErrorCode string `json:"errorCode"`
+	}
+	_ = json.Unmarshal(respBody, &data)
+	return data.ErrorCode
+}
+
+// isUnavailable reports whether err means the server could not be reached or
+// failed on its side, as opposed to rejecting the request. Like the typed
+// evaluations, a response with the error code GENERAL or PARSE_ERROR counts
+// as a failure on the server's side.
+func isUnavailable(err error) bool {
+	var se *HTTPStatusError
+	if errors.As(err, &se) {
+		switch openfeature.ErrorCode(se.ErrorCode) {
+		case openfeature.GeneralCode, openfeature.ParseErrorCode:
+			return true
+		}
+		return se.StatusCode >= 500
+	}
+	return !errors.Is(err, ErrInvalidAPIKey) && !errors.Is(err, ErrFlagNotFound) && !errors.Is(err, ErrEmptyFlagKey)
+}
+
+// fetchAllFlags performs the bulk evaluation request and parses the result.
+func (p *FlipswitchProvider) fetchAllFlags(ctx context.Context, evalCtx openfeature.FlattenedContext) ([]FlagEvaluation, error) {
+	return p.fetchAllFlagsAt(ctx, "", evalCtx)
+}
+
+// fetchAllFlagsAt is fetchAllFlags pinned to a config version, or the
+// current configuration if version is empty.
+func (p *FlipswitchProvider) fetchAllFlagsAt(ctx context.Context, version string, evalCtx openfeature.FlattenedContext) ([]FlagEvaluation, error) {
+	if p.offlineMode {
+		return p.bootstrap.all(), nil
+	}
+
+	var flags []FlagEvaluation
+	var err error
+	if p.bulkUnsupported.Load() {
+		flags, err = p.fetchFlagsIndividually(ctx, version, evalCtx)
+	} else {
+		flags, err = p.requestAllFlags(ctx, version, evalCtx)
+		if p.bulkUnsupportedFallback && isBulkUnsupported(err) {
+			p.logger.Infow("Bulk evaluation is not supported by the server, evaluating flags individually")
+			p.bulkUnsupported.Store(true)
+			flags, err = p.fetchFlagsIndividually(ctx, version, evalCtx)
+		}
+	}
+	if err != nil {
+		p.recordError(OperationBulkEvaluation, "", err)
+	} else if version == "" {
+		p.dropSeeds(flags)
+		p.saveSnapshot(evalCtx, flags)
+		p.markFetchRestored()
+	}
+	return flags, err
+}
+
+// requestAllFlags sends the bulk evaluation request for fetchAllFlagsAt.
+func (p *FlipswitchProvider) requestAllFlags(ctx context.Context, version string, evalCtx openfeature.FlattenedContext) ([]FlagEvaluation, error) {
+	resp, err := p.sendBulkRequest(ctx, version, evalCtx)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("[Flipswitch] Error reading response: %v", err)
-		return results
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	if err := p.verifyResponse(resp, respBody); err != nil {
+		return nil, err
+	}
+
+	return parseBulkResponse(respBody)
+}
+
+// sendBulkRequest sends the bulk evaluation request and returns the
+// successful response, whose body the caller must close.
+func (p *FlipswitchProvider) sendBulkRequest(ctx context.Context, version string, evalCtx openfeature.FlattenedContext) (*http.Response, error) {
+	url := p.evaluationURL("", version)
+
+	body := map[string]interface{}{
+		"context": p.outgoingContext(evalCtx),
+	}
+	bodyBytes, _ := json.Marshal(body)
+
+	resp, err := p.doRequest(ctx, url, bodyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		resp.Body.Close()
+		return nil, ErrInvalidAPIKey
+	}
+
+	if !isSuccess(resp.StatusCode) {
+		resp.Body.Close()
+		return nil, &HTTPStatusError{StatusCode: resp.StatusCode}
 	}
+	return resp, nil
+}
 
+// parseBulkResponse interprets a bulk evaluation response body, of the form
+// {"flags": [...]}.
+func parseBulkResponse(respBody []byte) ([]FlagEvaluation, error) {
 	var data map[string]interface{}
 	if err := json.Unmarshal(respBody, &data); err != nil {
-		log.Printf("[Flipswitch] Error parsing response: %v", err)
-		return results
+		return nil, fmt.Errorf("parsing response: %w", err)
 	}
 
+	results := make([]FlagEvaluation, 0)
 	if flags, ok := data["flags"].([]interface{}); ok {
 		for _, f := range flags {
-			if flag, ok := f.(map[string]interface{}); ok {
-				if key, ok := flag["key"].(string); ok {
-					results = append(results, FlagEvaluation{
-						Key:       key,
-						Value:     flag["value"],
-						ValueType: getFlagType(flag),
-						Reason:    getString(flag, "reason", ""),
-						Variant:   getString(flag, "variant", ""),
-					})
-				}
+			if eval, ok := bulkEntry(f); ok {
+				results = append(results, eval)
 			}
 		}
 	}
 
-	return results
+	return results, nil
+}
+
+// bulkEntry interprets one item of the flags array of a bulk evaluation
+// response. Items that are not objects with a string key are skipped.
+func bulkEntry(item interface{}) (FlagEvaluation, bool) {
+	flag, ok := item.(map[string]interface{})
+	if !ok {
+		return FlagEvaluation{}, false
+	}
+	key, ok := flag["key"].(string)
+	if !ok {
+		return FlagEvaluation{}, false
+	}
+	return FlagEvaluation{
+		Key:       key,
+		Value:     flag["value"],
+		ValueType: getFlagType(flag),
+		Reason:    getString(flag, "reason", ""),
+		Variant:   getString(flag, "variant", ""),
+	}, true
 }
 
 // EvaluateFlag evaluates a single flag and returns its evaluation result.
-// Returns nil if the flag doesn't exist.
+// Returns nil if the flag doesn't exist. An empty flag key is rejected with a
+// logged warning and nil, without making a request.
+//
+// Concurrent calls for the same flag key and context share a single HTTP
+// request. With WithCache, results are served from the cache until they
+// expire or are invalidated.
 //
 // Note: This method makes direct HTTP calls for demo purposes.
 // For standard flag evaluation, use the OpenFeature client methods.
 func (p *FlipswitchProvider) EvaluateFlag(flagKey string, evalCtx openfeature.FlattenedContext) *FlagEvaluation {
-	url := p.baseURL + "/ofrep/v1/evaluate/flags/" + flagKey
+	return p.EvaluateFlagCtx(context.Background(), flagKey, evalCtx)
+}
 
-	body := map[string]interface{}{
-		"context": transformContext(evalCtx),
+// EvaluateFlagCtx is like EvaluateFlag but sends the request with ctx, and
+// merges the baggage members named with WithBaggageAttributes into the
+// evaluation context. If ctx is cancelled or its deadline passes before the
+// response arrives, it returns what EvaluateFlag returns on a network error.
+func (p *FlipswitchProvider) EvaluateFlagCtx(ctx context.Context, flagKey string, evalCtx openfeature.FlattenedContext) *FlagEvaluation {
+	eval, _ := p.evaluateFlagCtx(ctx, flagKey, evalCtx)
+	return eval
+}
+
+// EvaluateFlagE is like EvaluateFlag but also returns the error that caused
+// it to return nil: ErrFlagNotFound if the server has no such flag,
+// ErrInvalidAPIKey, ErrEmptyFlagKey, an *HTTPStatusError for any other
+// unexpected status, or the wrapped network or parse error. If the server is
+// unavailable and a last-known or bootstrapped value is served instead, that
+// value is returned along with the error.
+func (p *FlipswitchProvider) EvaluateFlagE(flagKey string, evalCtx openfeature.FlattenedContext) (*FlagEvaluation, error) {
+	return p.evaluateFlagCtx(context.Background(), flagKey, evalCtx)
+}
+
+// evaluateFlagCtx implements EvaluateFlagCtx and EvaluateFlagE.
+func (p *FlipswitchProvider) evaluateFlagCtx(ctx context.Context, flagKey string, evalCtx openfeature.FlattenedContext) (*FlagEvaluation, error) {
+	start := time.Now()
+	evalCtx = p.withBaggage(ctx, evalCtx)
+	eval, source, err := p.evaluateFlagFrom(ctx, flagKey, evalCtx)
+	if err != nil && isUnavailable(err) {
+		eval = p.fallbackFlag(flagKey, evalCtx)
+		source = EvaluationSourceCache
+	}
+	eval = p.transformFlag(eval)
+	p.observeFlag(flagKey, start, eval, source, err)
+	return eval, err
+}
+
+// fallbackFlag returns the evaluation EvaluateFlag serves when the server is
+// unavailable: the last-known value if WithServeStaleOnError is set,
+// otherwise the bootstrapped value, or nil if there is neither.
+func (p *FlipswitchProvider) fallbackFlag(flagKey string, evalCtx openfeature.FlattenedContext) *FlagEvaluation {
+	if stale := p.staleFlag(flagKey, evalCtx); stale != nil {
+		return stale
+	}
+	return p.bootstrapFlag(flagKey)
+}
+
+// EvaluateFlagWithContext is like EvaluateFlag but takes an unflattened
+// evaluation context. The targeting key is sent as "targetingKey", taking
+// precedence over an attribute of the same name.
+func (p *FlipswitchProvider) EvaluateFlagWithContext(flagKey string, evalCtx openfeature.EvaluationContext) *FlagEvaluation {
+	return p.EvaluateFlag(flagKey, flattenContext(evalCtx))
+}
+
+// evaluateFlag evaluates a single flag, serving it from the cache when
+// enabled and deduplicating concurrent identical requests. Each caller
+// receives its own copy of the result.
+func (p *FlipswitchProvider) evaluateFlag(ctx context.Context, flagKey string, evalCtx openfeature.FlattenedContext) (*FlagEvaluation, error) {
+	eval, _, err := p.evaluateFlagFrom(ctx, flagKey, evalCtx)
+	return eval, err
+}
+
+// evaluateFlagFrom is evaluateFlag that also reports where the evaluation
+// came from.
+func (p *FlipswitchProvider) evaluateFlagFrom(ctx context.Context, flagKey string, evalCtx openfeature.FlattenedContext) (*FlagEvaluation, EvaluationSource, error) {
+	if flagKey == "" {
+		p.logger.Warnw("Ignoring evaluation of an empty flag key")
+		return nil, EvaluationSourceDefault, ErrEmptyFlagKey
+	}
+
+	start := time.Now()
+	eval, source, err := p.resolveFlag(ctx, flagKey, evalCtx)
+	p.metrics.observeEvaluation(flagKey, time.Since(start), err != nil)
+	return eval, source, err
+}
+
+func (p *FlipswitchProvider) resolveFlag(ctx context.Context, flagKey string, evalCtx openfeature.FlattenedContext) (*FlagEvaluation, EvaluationSource, error) {
+	requests := requestCacheFrom(ctx)
+	if eval := requests.get(flagKey, evalCtx); eval != nil {
+		return eval, EvaluationSourceCache, nil
+	}
+	eval, source, err := p.resolveSharedFlag(ctx, flagKey, evalCtx)
+	if err == nil {
+		requests.set(flagKey, evalCtx, *eval)
+	}
+	return eval, source, err
+}
+
+// resolveSharedFlag resolves a flag from the overrides, the shared cache or
+// the server, bypassing any request-scoped cache.
+func (p *FlipswitchProvider) resolveSharedFlag(ctx context.Context, flagKey string, evalCtx openfeature.FlattenedContext) (*FlagEvaluation, EvaluationSource, error) {
+	if eval := p.envOverride(flagKey); eval != nil {
+		return eval, EvaluationSourceLocal, nil
+	}
+	if p.killed(ctx, flagKey, evalCtx) {
+		return killedEvaluation(flagKey), EvaluationSourceLocal, nil
 	}
-	bodyBytes, _ := json.Marshal(body)
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(bodyBytes))
+	cached, ctxHash, generation := p.cachedEvaluation(flagKey, evalCtx)
+	if cached != nil {
+		return cached, EvaluationSourceCache, nil
+	}
+	if seed := p.seededFlag(flagKey); seed != nil {
+		return seed, EvaluationSourceCache, nil
+	}
+
+	eval, err := p.fetchShared(ctx, flagKey, evalCtx, ctxHash, generation)
 	if err != nil {
-		log.Printf("[Flipswitch] Error evaluating flag '%s': %v", flagKey, err)
-		return nil
+		return nil, EvaluationSourceDefault, err
 	}
+	result := *eval
+	return &result, p.fetchSource(), nil
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", p.apiKey)
-	p.setTelemetryHeaders(req)
+// fetchShared fetches a flag, sharing the request with concurrent identical
+// fetches unless evalCtx cannot be encoded, and keeps a successful result for later evaluations under ctxHash
+// and the cache generation read before the fetch.
+func (p *FlipswitchProvider) fetchShared(ctx context.Context, flagKey string, evalCtx openfeature.FlattenedContext, ctxHash string, generation uint64) (*FlagEvaluation, error) {
+	fetch := func(ctx context.Context) (*FlagEvaluation, error) {
+		eval, err := p.fetchFlag(ctx, flagKey, evalCtx)
+		if err != nil {
+			if !errors.Is(err, ErrFlagNotFound) {
+				p.logger.Errorw("Error evaluating flag", append([]any{"flagKey", flagKey}, errorFields(err)...)...)
+			}
+			return nil, err
+		}
+		p.cache.set(flagKey, ctxHash, *eval, generation)
+		p.lastKnown.set(flagKey, ctxHash, *eval)
+		p.initialFlags.drop(flagKey)
+		return eval, nil
+	}
 
-	resp, err := p.httpClient.Do(req)
+	key, err := evaluationKey(flagKey, evalCtx)
 	if err != nil {
-		log.Printf("[Flipswitch] Error evaluating flag '%s': %v", flagKey, err)
-		return nil
+		// Fetched on its own, as it cannot be told apart from other
+		// contexts
+		return fetch(ctx)
 	}
-	defer resp.Body.Close()
+	return p.flights.do(ctx, key, fetch)
+}
 
-	if resp.StatusCode == 404 {
-		return nil
+// fetchFlag performs the single flag evaluation request and parses the result.
+func (p *FlipswitchProvider) fetchFlag(ctx context.Context, flagKey string, evalCtx openfeature.FlattenedContext) (*FlagEvaluation, error) {
+	if p.offlineMode {
+		if eval := p.bootstrapFlag(flagKey); eval != nil {
+			return eval, nil
+		}
+		return nil, ErrFlagNotFound
 	}
 
-	if !isSuccess(resp.StatusCode) {
-		log.Printf("[Flipswitch] Failed to evaluate flag '%s': %d", flagKey, resp.StatusCode)
-		return nil
+	statusCode, respBody, err := p.postFlag(ctx, flagKey, p.outgoingContext(evalCtx))
+	if err == nil {
+		var eval *FlagEvaluation
+		if eval, err = parseFlagResponse(flagKey, statusCode, respBody); err == nil {
+			p.learnResponseSchema(flagKey, respBody)
+			return eval, nil
+		}
+	}
+	p.recordError(OperationEvaluation, flagKey, err)
+	return nil, err
+}
+
+// postFlag sends a single flag evaluation request with the given transformed
+// context and returns the raw response. If the response fails verification,
+// it is returned along with a *ResponseVerificationError.
+func (p *FlipswitchProvider) postFlag(ctx context.Context, flagKey string, sentContext map[string]interface{}) (int, []byte, error) {
+	return p.postFlagAt(ctx, flagKey, "", sentContext)
+}
+
+// postFlagAt is postFlag pinned to a config version, or the current
+// configuration if version is empty.
+func (p *FlipswitchProvider) postFlagAt(ctx context.Context, flagKey, version string, sentContext map[string]interface{}) (int, []byte, error) {
+	url := p.evaluationURL(flagKey, version)
+
+	body := map[string]interface{}{
+		"context": sentContext,
+	}
+	bodyBytes, _ := json.Marshal(body)
+
+	resp, err := p.doRequest(ctx, url, bodyBytes)
+	if err != nil {
+		return 0, nil, err
 	}
+	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("[Flipswitch] Error reading response: %v", err)
-		return nil
+		return resp.StatusCode, nil, fmt.Errorf("reading response: %w", err)
+	}
+	return resp.StatusCode, respBody, p.verifyResponse(resp, respBody)
+}
+
+// parseFlagResponse interprets a single flag evaluation response.
+func parseFlagResponse(flagKey string, statusCode int, respBody []byte) (*FlagEvaluation, error) {
+	if statusCode == 404 {
+		return nil, ErrFlagNotFound
+	}
+
+	if statusCode == 401 || statusCode == 403 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if !isSuccess(statusCode) {
+		return nil, &HTTPStatusError{StatusCode: statusCode, ErrorCode: responseErrorCode(respBody)}
 	}
 
 	var data map[string]interface{}
 	if err := json.Unmarshal(respBody, &data); err != nil {
-		log.Printf("[Flipswitch] Error parsing response: %v", err)
-		return nil
+		return nil, fmt.Errorf("parsing response: %w", err)
 	}
 
 	return &FlagEvaluation{
@@ -802,5 +2070,5 @@ func (p *FlipswitchProvider) EvaluateFlag(flagKey string, evalCtx openfeature.Fl
 		ValueType: getFlagType(data),
 		Reason:    getString(data, "reason", ""),
 		Variant:   getString(data, "variant", ""),
-	}
+	}, nil
 }
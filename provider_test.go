@@ -3,9 +3,14 @@ package flipswitch
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -98,6 +103,11 @@ func (d *TestDispatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(404)
 }
 
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
 func createTestProvider(server *httptest.Server) (*FlipswitchProvider, error) {
 	return NewProvider(
 		"test-api-key",
@@ -198,6 +208,132 @@ func TestInitialization_ShouldFailOnServerError(t *testing.T) {
 	}
 }
 
+func TestInitialization_ReadyAfterFirstSync_DelaysReadyEvent(t *testing.T) {
+	syncStarted := make(chan struct{})
+	releaseSync := make(chan struct{})
+	var syncOnce sync.Once
+	var singleCalls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		syncOnce.Do(func() { close(syncStarted) })
+		<-releaseSync
+		return 200, map[string]interface{}{
+			"flags": []interface{}{
+				map[string]interface{}{"key": "dark-mode", "value": true},
+			},
+		}
+	})
+	dispatcher.SetFlagResponse("dark-mode", func() (int, map[string]interface{}) {
+		atomic.AddInt32(&singleCalls, 1)
+		return 200, map[string]interface{}{"key": "dark-mode", "value": false}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithCache(time.Minute),
+		WithReadyAfterFirstSync(true),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	domain := "ready-after-first-sync"
+	ready := make(chan struct{}, 1)
+	callback := func(openfeature.EventDetails) {
+		select {
+		case ready <- struct{}{}:
+		default:
+		}
+	}
+	client := openfeature.NewClient(domain)
+	client.AddHandler(openfeature.ProviderReady, &callback)
+	defer client.RemoveHandler(openfeature.ProviderReady, &callback)
+
+	if err := openfeature.SetNamedProvider(domain, provider); err != nil {
+		t.Fatalf("Failed to set provider: %v", err)
+	}
+
+	select {
+	case <-syncStarted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the first sync to start")
+	}
+
+	select {
+	case <-ready:
+		t.Fatal("ProviderReady fired before the first sync completed")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if status := provider.Status(); status == openfeature.ReadyState {
+		t.Errorf("Expected provider not to be ready during the first sync, got %s", status)
+	}
+
+	close(releaseSync)
+
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for ProviderReady after the first sync")
+	}
+	if status := provider.Status(); status != openfeature.ReadyState {
+		t.Errorf("Expected ReadyState after the first sync, got %s", status)
+	}
+
+	// The synced flags populated the cache
+	if result := provider.EvaluateFlag("dark-mode", openfeature.FlattenedContext{}); result == nil || result.Value != true {
+		t.Errorf("Expected synced value true from the cache, got %+v", result)
+	}
+	if got := atomic.LoadInt32(&singleCalls); got != 0 {
+		t.Errorf("Expected no single flag requests, got %d", got)
+	}
+}
+
+func TestInitialization_ReadyAfterFirstSync_FailsOnBadSync(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ofrep/v1/evaluate/flags" {
+			// Auth succeeds, but no flags can be read
+			w.WriteHeader(200)
+			w.Write([]byte("not json"))
+			return
+		}
+		dispatcher.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Expected default Init to succeed after auth, got %v", err)
+	}
+	provider.Shutdown()
+
+	provider, err = NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithReadyAfterFirstSync(true),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err == nil {
+		t.Fatal("Expected Init to fail when the first sync fails")
+	}
+	if status := provider.Status(); status != openfeature.ErrorState {
+		t.Errorf("Expected ErrorState, got %s", status)
+	}
+}
+
 // ========================================
 // Metadata Tests
 // ========================================
@@ -214,6 +350,61 @@ func TestMetadata_ShouldReturnFlipswitch(t *testing.T) {
 	}
 }
 
+func TestEvaluation_DirectAndOfrepPathsAgree(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("bool-flag", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "bool-flag", "value": true, "variant": "on"}
+	})
+	dispatcher.SetFlagResponse("string-flag", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "string-flag", "value": "blue", "variant": "b"}
+	})
+	dispatcher.SetFlagResponse("int-flag", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "int-flag", "value": 42}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	// Requests from both paths go through the configured HTTP client
+	var requests int32
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithHTTPClient(client),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	ctx := context.Background()
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+
+	boolResult := provider.BooleanEvaluation(ctx, "bool-flag", false, evalCtx)
+	if direct := provider.EvaluateFlag("bool-flag", evalCtx); direct == nil || direct.AsBoolean() != boolResult.Value || direct.Variant != boolResult.Variant {
+		t.Errorf("Boolean paths disagree: OFREP %+v, direct %+v", boolResult, direct)
+	}
+
+	stringResult := provider.StringEvaluation(ctx, "string-flag", "", evalCtx)
+	if direct := provider.EvaluateFlag("string-flag", evalCtx); direct == nil || direct.AsString() != stringResult.Value {
+		t.Errorf("String paths disagree: OFREP %+v, direct %+v", stringResult, direct)
+	}
+
+	intResult := provider.IntEvaluation(ctx, "int-flag", 0, evalCtx)
+	if direct := provider.EvaluateFlag("int-flag", evalCtx); direct == nil || int64(direct.AsInt()) != intResult.Value {
+		t.Errorf("Int paths disagree: OFREP %+v, direct %+v", intResult, direct)
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 6 {
+		t.Errorf("Expected all 6 requests to use the configured client, got %d", got)
+	}
+}
+
 // ========================================
 // Bulk Evaluation Tests
 // ========================================
@@ -289,6 +480,130 @@ func TestEvaluateAllFlags_ShouldReturnEmptyListOnError(t *testing.T) {
 	}
 }
 
+func TestEvaluateAllFlagsGrouped_GroupsByReason(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{
+			"flags": []interface{}{
+				map[string]interface{}{"key": "flag-1", "value": true, "reason": "DEFAULT"},
+				map[string]interface{}{"key": "flag-2", "value": "test", "reason": "TARGETING_MATCH"},
+				map[string]interface{}{"key": "flag-3", "value": false, "reason": "DEFAULT"},
+				map[string]interface{}{"key": "flag-4", "value": nil, "reason": "ERROR"},
+				map[string]interface{}{"key": "flag-5", "value": 1},
+			},
+		}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	grouped, err := provider.EvaluateAllFlagsGrouped(openfeature.FlattenedContext{"targetingKey": "user-1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := map[string][]string{
+		"DEFAULT":         {"flag-1", "flag-3"},
+		"TARGETING_MATCH": {"flag-2"},
+		"ERROR":           {"flag-4"},
+		"":                {"flag-5"},
+	}
+	if len(grouped) != len(want) {
+		t.Fatalf("Expected %d groups, got %d: %+v", len(want), len(grouped), grouped)
+	}
+	for reason, keys := range want {
+		group := grouped[reason]
+		if len(group) != len(keys) {
+			t.Errorf("Reason %q: expected %d flags, got %d", reason, len(keys), len(group))
+			continue
+		}
+		for i, key := range keys {
+			if group[i].Key != key {
+				t.Errorf("Reason %q: expected flag %d to be '%s', got '%s'", reason, i, key, group[i].Key)
+			}
+		}
+	}
+}
+
+func TestEvaluateAllFlagsGrouped_ReturnsErrorOnFailure(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetInitFailure(500)
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	grouped, err := provider.EvaluateAllFlagsGrouped(openfeature.FlattenedContext{})
+	if err == nil {
+		t.Errorf("Expected an error, got groups %+v", grouped)
+	}
+}
+
+func TestEvaluateAllFlagsGrouped_MatchesEvaluateAllFlags(t *testing.T) {
+	t.Setenv("FSTEST_flag_1", "false")
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{
+			"flags": []interface{}{
+				map[string]interface{}{"key": "flag-1", "value": true, "reason": "DEFAULT"},
+				map[string]interface{}{"key": "flag-2", "value": "test", "reason": "TARGETING_MATCH"},
+			},
+		}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithEnvOverrides("FSTEST_"),
+		WithValueTransformer(func(flagKey string, value interface{}) interface{} {
+			if s, ok := value.(string); ok {
+				return strings.ToUpper(s)
+			}
+			return value
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	grouped, err := provider.EvaluateAllFlagsGrouped(evalCtx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var fromGroups []FlagEvaluation
+	for _, group := range grouped {
+		fromGroups = append(fromGroups, group...)
+	}
+	slices.SortFunc(fromGroups, func(a, b FlagEvaluation) int { return strings.Compare(a.Key, b.Key) })
+
+	flags := provider.EvaluateAllFlagsCtx(context.Background(), evalCtx)
+	if !reflect.DeepEqual(fromGroups, flags) {
+		t.Errorf("Expected the groups to hold %+v, got %+v", flags, fromGroups)
+	}
+	if override := grouped[string(openfeature.StaticReason)]; len(override) != 1 || override[0].Value != false {
+		t.Errorf("Expected the environment override in the STATIC group, got %+v", override)
+	}
+	if match := grouped["TARGETING_MATCH"]; len(match) != 1 || match[0].Value != "TEST" {
+		t.Errorf("Expected the transformed value in the TARGETING_MATCH group, got %+v", match)
+	}
+}
+
 // ========================================
 // Single Flag Evaluation Tests
 // ========================================
@@ -359,6 +674,40 @@ func TestEvaluateFlag_ShouldReturnNilForNonexistent(t *testing.T) {
 	}
 }
 
+func TestEvaluateFlag_EmptyKeyMakesNoRequest(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		NewTestDispatcher().ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithLogger(logger),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if result := provider.EvaluateFlag("", openfeature.FlattenedContext{}); result != nil {
+		t.Errorf("Expected nil, got %+v", result)
+	}
+	if _, err := provider.EvaluateFlagWithPolicy("", openfeature.FlattenedContext{}, NetworkOnly); !errors.Is(err, ErrEmptyFlagKey) {
+		t.Errorf("Expected ErrEmptyFlagKey, got %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 0 {
+		t.Errorf("Expected no requests, got %d", got)
+	}
+	if entry, ok := logger.find("Ignoring evaluation of an empty flag key"); !ok || entry.level != "warn" {
+		t.Errorf("Expected a warning to be logged, got %+v", logger.entries)
+	}
+}
+
 func TestEvaluateFlag_ShouldHandleBooleanValues(t *testing.T) {
 	dispatcher := NewTestDispatcher()
 	dispatcher.SetFlagResponse("bool-flag", func() (int, map[string]interface{}) {
@@ -448,6 +797,44 @@ func TestSseStatus_ShouldBeDisconnectedWhenRealtimeDisabled(t *testing.T) {
 	}
 }
 
+func TestSseRetryState_PassesThroughClient(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	clock := newFakeClock()
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithSseRetryBounds(time.Second, 10*time.Second),
+		WithSseReadTimeout(0),
+		WithPollingFallback(false),
+		withClock(clock),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if provider.GetSseRetryDelay() != 0 || provider.GetSseReconnectAttempts() != 0 {
+		t.Error("Expected zero values without an SSE connection")
+	}
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	clock.Advance(clock.nextScheduled(t))
+	clock.nextScheduled(t)
+	if got := provider.GetSseReconnectAttempts(); got != 2 {
+		t.Errorf("Expected 2 reconnect attempts, got %d", got)
+	}
+	if got := provider.GetSseRetryDelay(); got != 2*time.Second {
+		t.Errorf("Expected a retry delay of 2s, got %v", got)
+	}
+}
+
 // ========================================
 // Flag Change Listener Tests
 // ========================================
@@ -664,7 +1051,7 @@ func TestPollingFallback_DeactivatesOnSseReconnect(t *testing.T) {
 	}
 }
 
-func TestPollingFallback_DisabledWhenFalse(t *testing.T) {
+func TestPollingFallback_NotifiesStateChanges(t *testing.T) {
 	dispatcher := NewTestDispatcher()
 	server := httptest.NewServer(dispatcher)
 	defer server.Close()
@@ -674,45 +1061,109 @@ func TestPollingFallback_DisabledWhenFalse(t *testing.T) {
 		WithBaseURL(server.URL),
 		WithRealtime(false),
 		WithMaxSseRetries(2),
-		WithPollingFallback(false),
+		WithPollingFallback(true),
+		WithPollingInterval(1*time.Hour),
 	)
 	if err != nil {
 		t.Fatalf("Failed to create provider: %v", err)
 	}
 	defer provider.Shutdown()
 
-	err = provider.Init(openfeature.EvaluationContext{})
-	if err != nil {
-		t.Fatalf("Failed to initialize: %v", err)
-	}
+	var changes []bool
+	provider.OnFallbackStateChange(func(active bool) {
+		changes = append(changes, active)
+	})
 
-	// Simulate many SSE errors
-	for i := 0; i < 10; i++ {
+	// Further errors while polling must not notify again
+	for i := 0; i < 4; i++ {
 		provider.handleStatusChange(StatusError)
 	}
+	time.Sleep(200 * time.Millisecond)
+	provider.handleStatusChange(StatusConnected)
+	provider.handleStatusChange(StatusConnected)
 
-	if provider.IsPollingActive() {
-		t.Error("Expected polling to remain inactive when disabled")
+	if len(changes) != 2 || !changes[0] || changes[1] {
+		t.Errorf("Expected the handler to be called with true then false, got %v", changes)
 	}
 }
 
-// ========================================
-// Flag Change Handling Tests
-// ========================================
-
-func TestHandleFlagChange_NotifiesListeners(t *testing.T) {
+func TestPollingFallback_HandlerPanicIsRecovered(t *testing.T) {
 	dispatcher := NewTestDispatcher()
 	server := httptest.NewServer(dispatcher)
 	defer server.Close()
 
-	provider, err := createTestProvider(server)
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithMaxSseRetries(1),
+		WithPollingFallback(true),
+		WithPollingInterval(1*time.Hour),
+	)
 	if err != nil {
 		t.Fatalf("Failed to create provider: %v", err)
 	}
 	defer provider.Shutdown()
 
-	err = provider.Init(openfeature.EvaluationContext{})
-	if err != nil {
+	provider.OnFallbackStateChange(func(active bool) {
+		panic("handler failed")
+	})
+
+	provider.handleStatusChange(StatusError)
+	if !provider.IsPollingActive() {
+		t.Error("Expected polling to be active despite the panicking handler")
+	}
+}
+
+func TestPollingFallback_DisabledWhenFalse(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithMaxSseRetries(2),
+		WithPollingFallback(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	err = provider.Init(openfeature.EvaluationContext{})
+	if err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	// Simulate many SSE errors
+	for i := 0; i < 10; i++ {
+		provider.handleStatusChange(StatusError)
+	}
+
+	if provider.IsPollingActive() {
+		t.Error("Expected polling to remain inactive when disabled")
+	}
+}
+
+// ========================================
+// Flag Change Handling Tests
+// ========================================
+
+func TestHandleFlagChange_NotifiesListeners(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	err = provider.Init(openfeature.EvaluationContext{})
+	if err != nil {
 		t.Fatalf("Failed to initialize: %v", err)
 	}
 
@@ -1019,6 +1470,98 @@ func TestTransformContext_EmptyContext(t *testing.T) {
 	}
 }
 
+func TestFlattenContext_TargetingKeyAndAttributes(t *testing.T) {
+	evalCtx := openfeature.NewEvaluationContext("user-123", map[string]any{
+		"email": "test@example.com",
+		"plan":  "premium",
+	})
+
+	result := flattenContext(evalCtx)
+
+	if len(result) != 3 {
+		t.Errorf("Expected 3 entries, got %v", result)
+	}
+	if result["targetingKey"] != "user-123" {
+		t.Errorf("Expected targetingKey 'user-123', got '%v'", result["targetingKey"])
+	}
+	if result["email"] != "test@example.com" {
+		t.Errorf("Expected email 'test@example.com', got '%v'", result["email"])
+	}
+	if result["plan"] != "premium" {
+		t.Errorf("Expected plan 'premium', got '%v'", result["plan"])
+	}
+}
+
+func TestFlattenContext_TargetingKeyWinsOverAttribute(t *testing.T) {
+	evalCtx := openfeature.NewEvaluationContext("user-123", map[string]any{
+		"targetingKey": "attribute-key",
+	})
+
+	result := flattenContext(evalCtx)
+
+	if result["targetingKey"] != "user-123" {
+		t.Errorf("Expected dedicated targeting key 'user-123', got '%v'", result["targetingKey"])
+	}
+}
+
+func TestFlattenContext_TargetlessKeepsAttribute(t *testing.T) {
+	evalCtx := openfeature.NewTargetlessEvaluationContext(map[string]any{
+		"targetingKey": "attribute-key",
+	})
+
+	result := flattenContext(evalCtx)
+
+	if result["targetingKey"] != "attribute-key" {
+		t.Errorf("Expected attribute targeting key to be kept, got '%v'", result["targetingKey"])
+	}
+}
+
+func TestEvaluateFlagWithContext_SendsFlattenedContext(t *testing.T) {
+	var mu sync.Mutex
+	var sent []map[string]interface{}
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "my-flag", "value": true}
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Context map[string]interface{} `json:"context"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		sent = append(sent, body.Context)
+		mu.Unlock()
+		dispatcher.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	evalCtx := openfeature.NewEvaluationContext("user-123", map[string]any{
+		"targetingKey": "stale-key",
+		"plan":         "premium",
+	})
+
+	result := provider.EvaluateFlagWithContext("my-flag", evalCtx)
+	if result == nil || result.Value != true {
+		t.Fatalf("Expected my-flag to evaluate to true, got %+v", result)
+	}
+	provider.EvaluateAllFlagsWithContext(evalCtx)
+
+	if len(sent) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(sent))
+	}
+	for i, ctx := range sent {
+		if ctx["targetingKey"] != "user-123" || ctx["plan"] != "premium" || len(ctx) != 2 {
+			t.Errorf("Request %d: expected flattened context, got %v", i, ctx)
+		}
+	}
+}
+
 // ========================================
 // Type Inference Tests
 // ========================================
@@ -1053,6 +1596,39 @@ func TestInferType_Float(t *testing.T) {
 	}
 }
 
+func TestInferType_NumericTypes(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"int", int(1), "integer"},
+		{"int8", int8(1), "integer"},
+		{"int16", int16(1), "integer"},
+		{"int32", int32(1), "integer"},
+		{"int64", int64(1), "integer"},
+		{"uint", uint(1), "integer"},
+		{"uint8", uint8(1), "integer"},
+		{"uint16", uint16(1), "integer"},
+		{"uint32", uint32(1), "integer"},
+		{"uint64", uint64(1), "integer"},
+		{"float32", float32(1.5), "number"},
+		{"float64", float64(1.5), "number"},
+		{"json.Number integer", json.Number("42"), "integer"},
+		{"json.Number negative", json.Number("-7"), "integer"},
+		{"json.Number fraction", json.Number("4.2"), "number"},
+		{"json.Number exponent", json.Number("1e3"), "number"},
+		{"json.Number upper exponent", json.Number("1E-3"), "number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inferType(tt.value); got != tt.want {
+				t.Errorf("inferType(%T %v) = %q, want %q", tt.value, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestInferType_Null(t *testing.T) {
 	if inferType(nil) != "null" {
 		t.Errorf("Expected 'null', got '%s'", inferType(nil))
@@ -1239,6 +1815,80 @@ func TestWithHTTPClient(t *testing.T) {
 	}
 }
 
+func TestWithHTTPClient_UsedForSse(t *testing.T) {
+	sseHits := make(chan struct{}, 10)
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		sseHits <- struct{}{}
+		serveSseKeepAlive(w, r)
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	var mu sync.Mutex
+	var paths []string
+	customClient := &http.Client{
+		// Would cut the event stream off if the SSE client kept it
+		Timeout: 100 * time.Millisecond,
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			mu.Lock()
+			paths = append(paths, r.URL.Path)
+			mu.Unlock()
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	}
+	var middlewarePaths []string
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithHTTPClient(customClient),
+		WithPollingFallback(false),
+		WithEvaluationMiddleware(func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				mu.Lock()
+				middlewarePaths = append(middlewarePaths, r.URL.Path)
+				mu.Unlock()
+				return next.RoundTrip(r)
+			})
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	select {
+	case <-sseHits:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for SSE connection")
+	}
+
+	// Outlive the client's timeout
+	time.Sleep(300 * time.Millisecond)
+	if status := provider.GetSseStatus(); status != StatusConnected {
+		t.Errorf("Expected the stream to stay connected past the client timeout, got %s", status)
+	}
+	select {
+	case <-sseHits:
+		t.Error("Expected no reconnect")
+	default:
+	}
+	if customClient.Timeout != 100*time.Millisecond {
+		t.Errorf("Expected the custom client to be left unchanged, got timeout %v", customClient.Timeout)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Contains(paths, defaultSsePath) {
+		t.Errorf("Expected the SSE request to use the custom transport, got %v", paths)
+	}
+	if slices.Contains(middlewarePaths, defaultSsePath) {
+		t.Errorf("Expected evaluation middleware not to see the SSE request, got %v", middlewarePaths)
+	}
+}
+
 func TestWithPollingFallbackFalse(t *testing.T) {
 	provider, err := NewProvider("test-key", WithPollingFallback(false), WithRealtime(false))
 	if err != nil {
@@ -1275,6 +1925,38 @@ func TestWithMaxSseRetries(t *testing.T) {
 	}
 }
 
+func TestWithSseRetryBounds(t *testing.T) {
+	provider, err := NewProvider("test-key", WithSseRetryBounds(100*time.Millisecond, 5*time.Minute), WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	client := provider.newSseClient()
+	defer client.Close()
+	if client.minRetryDelay != 100*time.Millisecond || client.maxRetryDelay != 5*time.Minute {
+		t.Errorf("Expected SSE bounds 100ms..5m, got %v..%v", client.minRetryDelay, client.maxRetryDelay)
+	}
+	if client.retryDelay != 100*time.Millisecond {
+		t.Errorf("Expected the backoff to start at 100ms, got %v", client.retryDelay)
+	}
+}
+
+func TestWithSseRetryBounds_Invalid(t *testing.T) {
+	tests := map[string][2]time.Duration{
+		"min above max": {time.Minute, time.Second},
+		"zero min":      {0, time.Second},
+		"negative max":  {time.Second, -time.Second},
+	}
+	for name, bounds := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewProvider("test-key", WithSseRetryBounds(bounds[0], bounds[1])); err == nil {
+				t.Errorf("Expected an error for bounds %v..%v", bounds[0], bounds[1])
+			}
+		})
+	}
+}
+
 // ========================================
 // SSE Integration Tests
 // ========================================
@@ -1415,6 +2097,108 @@ func TestReconnectSse_NoOpWhenRealtimeDisabled(t *testing.T) {
 	}
 }
 
+func TestShutdownContext_WaitsForReconnectWithoutLeaks(t *testing.T) {
+	sseHit := make(chan struct{}, 10)
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case sseHit <- struct{}{}:
+		default:
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	before := runtime.NumGoroutine()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithPollingFallback(false),
+		// The client sleeps a long time before reconnecting
+		WithSseRetryBounds(time.Minute, time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	select {
+	case <-sseHit:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for SSE connection")
+	}
+	client := provider.currentSseClient()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := provider.ShutdownContext(ctx); err != nil {
+		t.Fatalf("Expected the SSE goroutine to exit in time, got %v", err)
+	}
+
+	client.mu.RLock()
+	running := client.running
+	client.mu.RUnlock()
+	if running {
+		t.Error("Expected the connection loop to have exited when ShutdownContext returned")
+	}
+
+	// Idle keep-alive connections have goroutines of their own
+	provider.httpClient.CloseIdleConnections()
+	http.DefaultTransport.(*http.Transport).CloseIdleConnections()
+	server.CloseClientConnections()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("Expected no leaked goroutines, had %d before and %d after", before, after)
+	}
+}
+
+func TestShutdownContext_ReturnsContextErrorWhenListenerBlocks(t *testing.T) {
+	frames := make(chan string, 1)
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetSseHandler(sseFramesHandler(frames))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key", WithBaseURL(server.URL), WithPollingFallback(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	provider.AddFlagChangeListener(func(FlagChangeEvent) {
+		close(entered)
+		<-release
+	})
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	frames <- flagUpdatedFrame("my-flag")
+	select {
+	case <-entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the listener")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := provider.ShutdownContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to pass while the listener blocks, got %v", err)
+	}
+	if provider.Status() != openfeature.NotReadyState {
+		t.Errorf("Expected the provider to be shut down anyway, got %v", provider.Status())
+	}
+	close(release)
+}
+
 func TestShutdown_WithActiveSseClient(t *testing.T) {
 	dispatcher := NewTestDispatcher()
 	sseHit := make(chan struct{}, 1)
@@ -1803,6 +2587,40 @@ func TestRemoveFlagChangeListener_Deprecated(t *testing.T) {
 	}
 }
 
+func TestRemoveFlagChangeListenerByID(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	var removedCalls, keptCalls int
+	handler := func(event FlagChangeEvent) { removedCalls++ }
+	removed := provider.AddFlagChangeListenerWithID(handler)
+	// The same handler registered twice is two independent listeners
+	provider.AddFlagChangeListenerWithID(func(event FlagChangeEvent) { keptCalls++ })
+	provider.AddFlagChangeListenerWithID(handler)
+
+	provider.RemoveFlagChangeListenerByID(removed)
+	provider.RemoveFlagChangeListenerByID(removed)
+
+	provider.handleFlagChange(FlagChangeEvent{FlagKey: "test"})
+
+	if removedCalls != 1 {
+		t.Errorf("Expected only the remaining registration of the handler to be called, got %d calls", removedCalls)
+	}
+	if keptCalls != 1 {
+		t.Errorf("Expected the other listener to still be called, got %d calls", keptCalls)
+	}
+	if got := len(provider.flagChangeListeners); got != 2 {
+		t.Errorf("Expected 2 registered listeners, got %d", got)
+	}
+}
+
 func TestAddFlagChangeListener_CancelFunc(t *testing.T) {
 	dispatcher := NewTestDispatcher()
 	server := httptest.NewServer(dispatcher)
@@ -2151,15 +2969,136 @@ func TestEvaluateFlag_NonSuccessNon404Status(t *testing.T) {
 	}
 }
 
-// ========================================
-// InferType Edge Cases
-// ========================================
+func TestEvaluateFlagE_NotFound(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
 
-func TestInferType_Unknown(t *testing.T) {
-	// Pass a type that doesn't match any switch case
-	ch := make(chan int)
-	if inferType(ch) != "unknown" {
-		t.Errorf("Expected 'unknown' for chan type, got '%s'", inferType(ch))
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	result, err := provider.EvaluateFlagE("nonexistent", openfeature.FlattenedContext{})
+	if result != nil {
+		t.Errorf("Expected nil, got %+v", result)
+	}
+	if !errors.Is(err, ErrFlagNotFound) {
+		t.Errorf("Expected ErrFlagNotFound, got %v", err)
+	}
+}
+
+func TestEvaluateFlagE_DistinguishesFailures(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "my-flag", "value": true, "reason": "STATIC"}
+	})
+	dispatcher.SetFlagResponse("bad-flag", func() (int, map[string]interface{}) {
+		return 400, map[string]interface{}{}
+	})
+	dispatcher.SetFlagResponse("broken", func() (int, map[string]interface{}) {
+		return 500, map[string]interface{}{}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	result, err := provider.EvaluateFlagE("my-flag", openfeature.FlattenedContext{})
+	if err != nil || result == nil || result.Value != true {
+		t.Errorf("Expected true without an error, got %+v, %v", result, err)
+	}
+
+	for _, key := range []string{"bad-flag", "broken"} {
+		result, err := provider.EvaluateFlagE(key, openfeature.FlattenedContext{})
+		var statusErr *HTTPStatusError
+		if result != nil || !errors.As(err, &statusErr) {
+			t.Errorf("%s: expected an *HTTPStatusError, got %+v, %v", key, result, err)
+		}
+		if errors.Is(err, ErrFlagNotFound) {
+			t.Errorf("%s: a failure must not look like a missing flag", key)
+		}
+	}
+
+	if _, err := provider.EvaluateFlagE("", openfeature.FlattenedContext{}); !errors.Is(err, ErrEmptyFlagKey) {
+		t.Errorf("Expected ErrEmptyFlagKey, got %v", err)
+	}
+}
+
+func TestEvaluateFlagE_ParseError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{not valid json`))
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key", WithBaseURL(server.URL), WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	_, err = provider.EvaluateFlagE("my-flag", openfeature.FlattenedContext{})
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("Expected a wrapped JSON syntax error, got %v", err)
+	}
+
+	flags, err := provider.EvaluateAllFlagsE(openfeature.FlattenedContext{})
+	if len(flags) != 0 || !errors.As(err, &syntaxErr) {
+		t.Errorf("Expected no flags and a wrapped JSON syntax error, got %v, %v", flags, err)
+	}
+}
+
+func TestEvaluateAllFlagsE(t *testing.T) {
+	status := 200
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		return status, map[string]interface{}{"flags": []interface{}{
+			map[string]interface{}{"key": "flag-a", "value": "x", "reason": "STATIC"},
+		}}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	flags, err := provider.EvaluateAllFlagsE(openfeature.FlattenedContext{})
+	if err != nil || len(flags) != 1 || flags[0].Key != "flag-a" {
+		t.Errorf("Expected flag-a without an error, got %+v, %v", flags, err)
+	}
+
+	status = 503
+	flags, err = provider.EvaluateAllFlagsE(openfeature.FlattenedContext{})
+	var statusErr *HTTPStatusError
+	if len(flags) != 0 || !errors.As(err, &statusErr) || statusErr.StatusCode != 503 {
+		t.Errorf("Expected no flags and a 503 *HTTPStatusError, got %+v, %v", flags, err)
+	}
+
+	// The original method keeps returning an empty slice
+	if flags := provider.EvaluateAllFlags(openfeature.FlattenedContext{}); flags == nil || len(flags) != 0 {
+		t.Errorf("Expected an empty slice, got %#v", flags)
+	}
+}
+
+// ========================================
+// InferType Edge Cases
+// ========================================
+
+func TestInferType_Unknown(t *testing.T) {
+	// Pass a type that doesn't match any switch case
+	ch := make(chan int)
+	if inferType(ch) != "unknown" {
+		t.Errorf("Expected 'unknown' for chan type, got '%s'", inferType(ch))
 	}
 
 	fn := func() {}
@@ -2193,8 +3132,8 @@ func TestScheduleReconnect_ContextCancellation(t *testing.T) {
 	delay := client.retryDelay
 	client.mu.RUnlock()
 
-	if delay != minRetryDelay {
-		t.Errorf("Expected retryDelay to stay at min (%v), got %v", minRetryDelay, delay)
+	if delay != defaultMinRetryDelay {
+		t.Errorf("Expected retryDelay to stay at min (%v), got %v", defaultMinRetryDelay, delay)
 	}
 }
 
@@ -2578,3 +3517,460 @@ func containsAt(s, substr string) bool {
 	}
 	return false
 }
+
+// ========================================
+// Scoped Bulk Invalidation Tests
+// ========================================
+
+func TestHandleFlagChange_AffectedKeysReachListeners(t *testing.T) {
+	provider, err := NewProvider("test-api-key", WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	var globalEvent FlagChangeEvent
+	provider.AddFlagChangeListener(func(event FlagChangeEvent) {
+		globalEvent = event
+	})
+
+	var affectedFired, unaffectedFired bool
+	provider.AddFlagKeyChangeListener("flag-a", func(event FlagChangeEvent) {
+		affectedFired = true
+	})
+	provider.AddFlagKeyChangeListener("flag-c", func(event FlagChangeEvent) {
+		unaffectedFired = true
+	})
+
+	// Route a config-updated frame through the SSE parser into the provider.
+	client := NewSseClient("http://localhost", "test-key", nil, provider.handleFlagChange, nil)
+	defer client.Close()
+	client.handleEvent("config-updated", `{"timestamp":"2024-06-15T12:00:00Z","affectedKeys":["flag-a","flag-b"]}`)
+
+	if len(globalEvent.AffectedKeys) != 2 {
+		t.Errorf("Expected global listener to receive 2 affected keys, got %v", globalEvent.AffectedKeys)
+	}
+	if !affectedFired {
+		t.Error("Expected listener for affected key flag-a to fire")
+	}
+	if unaffectedFired {
+		t.Error("Expected listener for unaffected key flag-c NOT to fire")
+	}
+
+	select {
+	case event := <-provider.EventChannel():
+		if len(event.FlagChanges) != 2 || event.FlagChanges[0] != "flag-a" {
+			t.Errorf("Expected FlagChanges [flag-a flag-b], got %v", event.FlagChanges)
+		}
+	default:
+		t.Error("Expected a ProviderConfigChange event")
+	}
+}
+
+// ========================================
+// Readiness Tests
+// ========================================
+
+func TestReady_SucceedsAgainstWorkingServer(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	if err := provider.Ready(context.Background()); err != nil {
+		t.Errorf("Expected Ready to succeed, got: %v", err)
+	}
+}
+
+func TestReady_FailsOnBulkServerErrorAfterAuthPasses(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	// Auth passed during Init; now the evaluation backend starts failing.
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		return 500, map[string]interface{}{"errorCode": "GENERAL"}
+	})
+
+	if err := provider.Ready(context.Background()); err == nil {
+		t.Error("Expected Ready to fail on a 500 bulk response")
+	}
+}
+
+func TestReady_IgnoresBootstrap(t *testing.T) {
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(unreachableURL()),
+		WithRealtime(false),
+		WithBootstrap([]FlagEvaluation{{Key: "dark-mode", Value: true}}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Ready(context.Background()); err == nil {
+		t.Error("Expected Ready to fail when the server is unreachable")
+	}
+}
+
+// ========================================
+// Status Channel Tests
+// ========================================
+
+func TestStatusChanges_DeliversTransitions(t *testing.T) {
+	provider, err := NewProvider("test-api-key", WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	statuses, cancel := provider.StatusChanges()
+	defer cancel()
+
+	provider.handleStatusChange(StatusConnecting)
+	provider.handleStatusChange(StatusConnected)
+	provider.handleStatusChange(StatusError)
+
+	expected := []ConnectionStatus{StatusConnecting, StatusConnected, StatusError}
+	for _, want := range expected {
+		select {
+		case got := <-statuses:
+			if got != want {
+				t.Errorf("Expected status %q, got %q", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for status %q", want)
+		}
+	}
+}
+
+func TestStatusChanges_ClosesOnCancel(t *testing.T) {
+	provider, err := NewProvider("test-api-key", WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	statuses, cancel := provider.StatusChanges()
+	cancel()
+	cancel() // idempotent
+
+	select {
+	case _, ok := <-statuses:
+		if ok {
+			t.Error("Expected channel to be closed after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for channel to close")
+	}
+
+	// Publishing after cancel must not panic.
+	provider.handleStatusChange(StatusConnecting)
+}
+
+func TestStatusChanges_DropsOldestWhenFull(t *testing.T) {
+	provider, err := NewProvider("test-api-key", WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	statuses, cancel := provider.StatusChanges()
+	defer cancel()
+
+	// Overflow the buffer by one; the first (connecting) status is dropped.
+	provider.handleStatusChange(StatusConnecting)
+	for i := 0; i < statusChannelBuffer; i++ {
+		provider.handleStatusChange(StatusDisconnected)
+	}
+
+	if got := <-statuses; got != StatusDisconnected {
+		t.Errorf("Expected oldest status to be dropped, got %q first", got)
+	}
+	if len(statuses) != statusChannelBuffer-1 {
+		t.Errorf("Expected %d buffered statuses, got %d", statusChannelBuffer-1, len(statuses))
+	}
+}
+
+// ========================================
+// Connection Status Listener Tests
+// ========================================
+
+func TestAddConnectionStatusListener_NotifiedOfSseTransitions(t *testing.T) {
+	var connections int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		// The first connection attempt fails, later ones succeed
+		if atomic.AddInt32(&connections, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		serveSseKeepAlive(w, r)
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithSseRetryBounds(10*time.Millisecond, 50*time.Millisecond),
+		WithPollingFallback(false),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	statuses := make(chan ConnectionStatus, 32)
+	provider.AddConnectionStatusListener(func(status ConnectionStatus) {
+		statuses <- status
+	})
+
+	expect := func(want ...ConnectionStatus) {
+		t.Helper()
+		for _, w := range want {
+			select {
+			case got := <-statuses:
+				if got != w {
+					t.Fatalf("Expected status %q, got %q", w, got)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Timed out waiting for status %q", w)
+			}
+		}
+	}
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	expect(StatusConnecting, StatusError, StatusConnecting, StatusConnected)
+
+	provider.ReconnectSse()
+	expect(StatusDisconnected, StatusConnecting, StatusConnected)
+
+	provider.Shutdown()
+	expect(StatusDisconnected)
+}
+
+func TestAddConnectionStatusListener_PanicIsolationAndCancel(t *testing.T) {
+	logger := &recordingLogger{}
+	provider, err := NewProvider("test-api-key", WithRealtime(false), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	var received []ConnectionStatus
+	provider.AddConnectionStatusListener(func(ConnectionStatus) {
+		panic("listener failure")
+	})
+	cancel := provider.AddConnectionStatusListener(func(status ConnectionStatus) {
+		received = append(received, status)
+	})
+
+	provider.handleStatusChange(StatusConnecting)
+	cancel()
+	cancel() // idempotent
+	provider.handleStatusChange(StatusConnected)
+
+	if len(received) != 1 || received[0] != StatusConnecting {
+		t.Errorf("Expected only the status before cancel, got %v", received)
+	}
+	if _, ok := logger.find("Error in connection status listener"); !ok {
+		t.Error("Expected the panic to be logged")
+	}
+}
+
+func TestSseEventMapping_CustomNamesDispatchToListeners(t *testing.T) {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "event: flag.updated\ndata: {\"flagKey\":\"dark-mode\",\"timestamp\":\"2024-06-15T12:00:00Z\"}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithPollingFallback(false),
+		WithSseEventMapping(map[string]ChangeType{"flag.updated": ChangeFlagUpdated}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	received := make(chan FlagChangeEvent, 1)
+	provider.AddFlagKeyChangeListener("dark-mode", func(event FlagChangeEvent) {
+		received <- event
+	})
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	select {
+	case event := <-received:
+		if event.FlagKey != "dark-mode" {
+			t.Errorf("Expected FlagKey 'dark-mode', got %q", event.FlagKey)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for mapped flag change event")
+	}
+}
+
+// ========================================
+// Context-Aware Evaluation Tests
+// ========================================
+
+// blockingServer serves my-flag and the bulk endpoint, signalling started
+// when a request arrives and blocking until release is closed.
+func blockingServer() (server *httptest.Server, started, release chan struct{}) {
+	started = make(chan struct{}, 10)
+	release = make(chan struct{})
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", func() (int, map[string]interface{}) {
+		started <- struct{}{}
+		<-release
+		return 200, map[string]interface{}{"key": "my-flag", "value": true}
+	})
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		started <- struct{}{}
+		<-release
+		return 200, map[string]interface{}{"flags": []interface{}{}}
+	})
+	return httptest.NewServer(dispatcher), started, release
+}
+
+// waitForGoroutines waits for the goroutine count to drop back to baseline.
+func waitForGoroutines(t *testing.T, baseline int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			t.Errorf("Expected goroutines to return to %d, got %d", baseline, runtime.NumGoroutine())
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestEvaluateCtx_CancelMidFlight(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		evaluate func(ctx context.Context, p *FlipswitchProvider) bool
+	}{
+		{"EvaluateFlagCtx", func(ctx context.Context, p *FlipswitchProvider) bool {
+			return p.EvaluateFlagCtx(ctx, "my-flag", openfeature.FlattenedContext{}) == nil
+		}},
+		{"EvaluateAllFlagsCtx", func(ctx context.Context, p *FlipswitchProvider) bool {
+			flags := p.EvaluateAllFlagsCtx(ctx, openfeature.FlattenedContext{})
+			return flags != nil && len(flags) == 0
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server, started, release := blockingServer()
+			defer server.Close()
+
+			provider, err := createTestProvider(server)
+			if err != nil {
+				t.Fatalf("Failed to create provider: %v", err)
+			}
+			defer provider.Shutdown()
+
+			baseline := runtime.NumGoroutine()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan bool, 1)
+			go func() { done <- tc.evaluate(ctx, provider) }()
+
+			select {
+			case <-started:
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for the request to reach the server")
+			}
+			cancel()
+
+			select {
+			case empty := <-done:
+				if !empty {
+					t.Error("Expected the network error result after cancellation")
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for the cancelled evaluation to return")
+			}
+
+			close(release)
+			server.CloseClientConnections()
+			provider.httpClient.CloseIdleConnections()
+			waitForGoroutines(t, baseline)
+		})
+	}
+}
+
+func TestEvaluateCtx_DeadlineReturnsNil(t *testing.T) {
+	server, _, release := blockingServer()
+	defer server.Close()
+	defer close(release)
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if result := provider.EvaluateFlagCtx(ctx, "my-flag", openfeature.FlattenedContext{}); result != nil {
+		t.Errorf("Expected nil after the deadline passed, got %+v", result)
+	}
+}
+
+func TestEvaluateCtx_CancelledContextServesBootstrap(t *testing.T) {
+	server, _, release := blockingServer()
+	defer server.Close()
+	defer close(release)
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithBootstrap([]FlagEvaluation{{Key: "my-flag", Value: false}}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result := provider.EvaluateFlagCtx(ctx, "my-flag", openfeature.FlattenedContext{})
+	if result == nil || result.Value != false {
+		t.Errorf("Expected the bootstrapped value like any network error, got %+v", result)
+	}
+}
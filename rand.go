@@ -0,0 +1,44 @@
+package flipswitch
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// lockedRand is a math/rand source that is safe for concurrent use.
+type lockedRand struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func newLockedRand(seed int64) *lockedRand {
+	return &lockedRand{rng: rand.New(rand.NewSource(seed))}
+}
+
+func newTimeSeededRand() *lockedRand {
+	return newLockedRand(time.Now().UnixNano())
+}
+
+// Int63n returns a non-negative pseudo-random number in [0, n).
+func (r *lockedRand) Int63n(n int64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Int63n(n)
+}
+
+// Uint64 returns a pseudo-random 64-bit value.
+func (r *lockedRand) Uint64() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Uint64()
+}
+
+// WithRandSeed makes all randomness inside the provider deterministic,
+// including SSE reconnect and startup jitter. It is intended for
+// reproducible tests; production code should not set it.
+func WithRandSeed(seed int64) Option {
+	return func(p *FlipswitchProvider) {
+		p.rng = newLockedRand(seed)
+	}
+}
@@ -0,0 +1,81 @@
+package flipswitch
+
+import (
+	"testing"
+	"time"
+)
+
+// randomOutputs returns the connection ID and reconnect jitter produced by a
+// provider created with the given options.
+func randomOutputs(t *testing.T, opts ...Option) (string, []time.Duration) {
+	t.Helper()
+
+	provider, err := NewProvider("test-api-key", append([]Option{WithRealtime(false)}, opts...)...)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	client := provider.newSseClient()
+	defer client.Close()
+
+	var delays []time.Duration
+	for _, base := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second} {
+		delays = append(delays, client.jitter(base))
+	}
+	return provider.connectionID, delays
+}
+
+func TestWithRandSeed_SameSeedIsDeterministic(t *testing.T) {
+	id1, delays1 := randomOutputs(t, WithRandSeed(42))
+	id2, delays2 := randomOutputs(t, WithRandSeed(42))
+
+	if id1 != id2 {
+		t.Errorf("Connection ID differs: %s vs %s", id1, id2)
+	}
+	for i := range delays1 {
+		if delays1[i] != delays2[i] {
+			t.Errorf("Delay %d differs: %v vs %v", i, delays1[i], delays2[i])
+		}
+	}
+}
+
+func TestWithRandSeed_DifferentSeedsDiffer(t *testing.T) {
+	id1, _ := randomOutputs(t, WithRandSeed(1))
+	id2, _ := randomOutputs(t, WithRandSeed(2))
+
+	if id1 == id2 {
+		t.Errorf("Expected different seeds to generate different connection IDs, both got %s", id1)
+	}
+}
+
+func TestWithRandSeed_StartupJitterIsDeterministic(t *testing.T) {
+	provider := func() *FlipswitchProvider {
+		provider, err := NewProvider("test-api-key", WithRealtime(false), WithRandSeed(42), WithStartupJitter(time.Minute))
+		if err != nil {
+			t.Fatalf("Failed to create provider: %v", err)
+		}
+		t.Cleanup(func() { provider.Shutdown() })
+		return provider
+	}
+
+	got, want := provider().startupDelay(), provider().startupDelay()
+	if got != want {
+		t.Errorf("Startup delay differs: %v vs %v", got, want)
+	}
+	if got < 0 || got >= time.Minute {
+		t.Errorf("Expected a startup delay within [0, 1m), got %v", got)
+	}
+}
+
+func TestSseClient_JitterStaysWithinBounds(t *testing.T) {
+	client := NewSseClient("http://localhost", "test-key", nil, nil, nil, withSseRand(newLockedRand(7)))
+	defer client.Close()
+
+	for i := 0; i < 100; i++ {
+		got := client.jitter(time.Second)
+		if got < 500*time.Millisecond || got > time.Second {
+			t.Fatalf("Expected jitter within [500ms, 1s], got %v", got)
+		}
+	}
+}
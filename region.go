@@ -0,0 +1,35 @@
+package flipswitch
+
+import "fmt"
+
+// regionBaseURLs maps Flipswitch region codes to their API endpoints.
+var regionBaseURLs = map[string]string{
+	"us": "https://us.api.flipswitch.io",
+	"eu": "https://eu.api.flipswitch.io",
+	"ap": "https://ap.api.flipswitch.io",
+}
+
+// WithRegion sets the base URL to the endpoint of a Flipswitch region
+// ("us", "eu" or "ap"). NewProvider returns an error for an unknown region.
+// An explicit WithBaseURL takes precedence.
+func WithRegion(region string) Option {
+	return func(p *FlipswitchProvider) {
+		p.region = region
+	}
+}
+
+// applyRegion resolves the configured region to a base URL unless one was
+// set explicitly.
+func (p *FlipswitchProvider) applyRegion() error {
+	if p.region == "" {
+		return nil
+	}
+	url, ok := regionBaseURLs[p.region]
+	if !ok {
+		return fmt.Errorf("unknown region %q", p.region)
+	}
+	if !p.baseURLSet {
+		p.baseURL = url
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package flipswitch
+
+import (
+	"testing"
+)
+
+func TestWithRegion_MapsKnownRegions(t *testing.T) {
+	tests := []struct {
+		region string
+		want   string
+	}{
+		{"us", "https://us.api.flipswitch.io"},
+		{"eu", "https://eu.api.flipswitch.io"},
+		{"ap", "https://ap.api.flipswitch.io"},
+	}
+
+	for _, tt := range tests {
+		provider, err := NewProvider("test-api-key", WithRegion(tt.region), WithRealtime(false))
+		if err != nil {
+			t.Fatalf("Unexpected error for region %q: %v", tt.region, err)
+		}
+		if provider.baseURL != tt.want {
+			t.Errorf("Region %q: expected base URL %q, got %q", tt.region, tt.want, provider.baseURL)
+		}
+	}
+}
+
+func TestWithRegion_UnknownRegionErrors(t *testing.T) {
+	_, err := NewProvider("test-api-key", WithRegion("mars"))
+	if err == nil {
+		t.Fatal("Expected an error for an unknown region")
+	}
+	if err.Error() != `unknown region "mars"` {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+}
+
+func TestWithRegion_ExplicitBaseURLWins(t *testing.T) {
+	for _, opts := range [][]Option{
+		{WithRegion("eu"), WithBaseURL("https://flags.example.com")},
+		{WithBaseURL("https://flags.example.com"), WithRegion("eu")},
+	} {
+		provider, err := NewProvider("test-api-key", opts...)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if provider.baseURL != "https://flags.example.com" {
+			t.Errorf("Expected explicit base URL to win, got %q", provider.baseURL)
+		}
+	}
+}
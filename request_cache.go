@@ -0,0 +1,74 @@
+package flipswitch
+
+import (
+	"context"
+	"sync"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// requestCacheKey is the context key under which WithRequestCache stores the
+// request-scoped cache.
+type requestCacheKey struct{}
+
+// requestCache holds the evaluations made with one request-scoped context.
+// Entries never expire and are not invalidated by SSE events. A nil cache
+// stores nothing.
+type requestCache struct {
+	mu      sync.Mutex
+	entries map[cacheKey]FlagEvaluation
+}
+
+// WithRequestCache returns a copy of ctx that carries a request-scoped
+// evaluation cache. Every single flag evaluation made with the returned
+// context (EvaluateFlagCtx and the typed OpenFeature evaluations) returns the
+// value from the first successful evaluation of the same flag and evaluation
+// context, even if the flag changes or the global cache is invalidated in
+// between, so a request sees one consistent snapshot. It works with or
+// without WithCache. Failed evaluations are not remembered, and
+// EvaluateAllFlagsCtx is not affected. If ctx already carries a request
+// cache, ctx is returned unchanged.
+func WithRequestCache(ctx context.Context) context.Context {
+	if requestCacheFrom(ctx) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, requestCacheKey{}, &requestCache{entries: make(map[cacheKey]FlagEvaluation)})
+}
+
+// requestCacheFrom returns the request-scoped cache carried by ctx, or nil.
+func requestCacheFrom(ctx context.Context) *requestCache {
+	if ctx == nil {
+		return nil
+	}
+	c, _ := ctx.Value(requestCacheKey{}).(*requestCache)
+	return c
+}
+
+// get returns a copy of the evaluation remembered for flagKey and evalCtx,
+// or nil.
+func (c *requestCache) get(flagKey string, evalCtx openfeature.FlattenedContext) *FlagEvaluation {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	eval, ok := c.entries[cacheKey{flagKey: flagKey, ctxHash: contextHash(evalCtx)}]
+	if !ok {
+		return nil
+	}
+	return &eval
+}
+
+// set remembers eval for flagKey and evalCtx unless an evaluation is already
+// remembered.
+func (c *requestCache) set(flagKey string, evalCtx openfeature.FlattenedContext, eval FlagEvaluation) {
+	if c == nil {
+		return
+	}
+	key := cacheKey{flagKey: flagKey, ctxHash: contextHash(evalCtx)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.entries[key] = eval
+	}
+}
@@ -0,0 +1,75 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestWithRequestCache_ConsistentAcrossInvalidation(t *testing.T) {
+	flag, setValue := switchingFlag("my-flag", "before")
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", flag)
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createCachingProvider(t, server, time.Minute)
+	defer provider.Shutdown()
+
+	ctx := WithRequestCache(context.Background())
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+
+	first := provider.EvaluateFlagCtx(ctx, "my-flag", evalCtx)
+	if first == nil || first.AsString() != "before" {
+		t.Fatalf("Expected the first read to return before, got %+v", first)
+	}
+
+	setValue("after")
+	provider.handleFlagChange(FlagChangeEvent{FlagKey: "my-flag"})
+
+	second := provider.EvaluateFlagCtx(ctx, "my-flag", evalCtx)
+	if second == nil || second.AsString() != first.AsString() {
+		t.Errorf("Expected the second read in the request to match the first, got %+v", second)
+	}
+	if detail := provider.StringEvaluation(ctx, "my-flag", "default", evalCtx); detail.Value != "before" {
+		t.Errorf("Expected the typed evaluation in the request to match, got %q", detail.Value)
+	}
+
+	// Outside the request the invalidation takes effect
+	if fresh := provider.EvaluateFlag("my-flag", evalCtx); fresh == nil || fresh.AsString() != "after" {
+		t.Errorf("Expected a read outside the request to see the change, got %+v", fresh)
+	}
+}
+
+func TestWithRequestCache_WithoutGlobalCache(t *testing.T) {
+	flag, setValue := switchingFlag("my-flag", "before")
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", flag)
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	ctx := WithRequestCache(context.Background())
+	if detail := provider.StringEvaluation(ctx, "my-flag", "default", openfeature.FlattenedContext{}); detail.Value != "before" {
+		t.Fatalf("Expected before, got %q", detail.Value)
+	}
+	setValue("after")
+
+	if detail := provider.StringEvaluation(ctx, "my-flag", "default", openfeature.FlattenedContext{}); detail.Value != "before" {
+		t.Errorf("Expected the request to keep its first value, got %q", detail.Value)
+	}
+	if result := provider.EvaluateFlagCtx(ctx, "my-flag", openfeature.FlattenedContext{}); result == nil || result.AsString() != "before" {
+		t.Errorf("Expected EvaluateFlagCtx to share the request cache, got %+v", result)
+	}
+	if WithRequestCache(ctx) != ctx {
+		t.Error("Expected WithRequestCache to reuse an existing request cache")
+	}
+}
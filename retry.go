@@ -0,0 +1,215 @@
+package flipswitch
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultRequestTimeout bounds each direct evaluation call unless
+// WithRequestTimeout says otherwise.
+const defaultRequestTimeout = 10 * time.Second
+
+// defaultRetryableStatusCodes are the HTTP statuses retried when evaluation
+// retries are enabled and no custom set is configured.
+var defaultRetryableStatusCodes = []int{429, 500, 502, 503, 504}
+
+// maxRetryAfter caps the wait requested by a Retry-After header, so a
+// misconfigured server cannot stall an evaluation indefinitely.
+const maxRetryAfter = 30 * time.Second
+
+// WithEvaluationRetries enables retrying direct evaluation requests that fail
+// with a transport error or a retryable status code. maxAttempts includes the
+// first attempt; the delay starts at baseDelay and doubles between attempts.
+// A 429 or 503 response with a Retry-After header waits as long as the header
+// asks instead, up to 30 seconds. Retries stop when the request context is
+// done. Retries are disabled by default.
+func WithEvaluationRetries(maxAttempts int, baseDelay time.Duration) Option {
+	return func(p *FlipswitchProvider) {
+		p.retryMaxAttempts = maxAttempts
+		p.retryBaseDelay = baseDelay
+	}
+}
+
+// WithRequestTimeout bounds each evaluation call to d until the response has
+// been read: the direct HTTP calls made by EvaluateFlag, EvaluateAllFlags and
+// the other direct methods, including any retries, and the OFREP requests
+// behind the typed OpenFeature evaluations. A call that runs out of time
+// fails like a network error. The default is 10 seconds; zero removes the
+// limit, leaving only the caller's context and the HTTP client's own
+// timeout. The SSE connection is not affected.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(p *FlipswitchProvider) {
+		p.requestTimeout = d
+	}
+}
+
+// WithPerAttemptTimeout bounds each evaluation request attempt to d. An
+// attempt that takes longer is abandoned and, if retries are enabled and the
+// caller's context allows, retried, so a single stalled attempt cannot use up
+// the whole retry budget. Zero means no per-attempt limit, the default.
+func WithPerAttemptTimeout(d time.Duration) Option {
+	return func(p *FlipswitchProvider) {
+		p.perAttemptTimeout = d
+	}
+}
+
+// WithRetryableStatusCodes replaces the set of HTTP status codes that trigger
+// a retry. The default is 429, 500, 502, 503 and 504. Transport errors are
+// always retried. Has no effect unless WithEvaluationRetries is set.
+func WithRetryableStatusCodes(codes ...int) Option {
+	return func(p *FlipswitchProvider) {
+		p.retryableStatusCodes = statusCodeSet(codes)
+	}
+}
+
+func statusCodeSet(codes []int) map[int]bool {
+	set := make(map[int]bool, len(codes))
+	for _, code := range codes {
+		set[code] = true
+	}
+	return set
+}
+
+// doRequest POSTs an evaluation request body to url, retrying transport
+// errors and retryable statuses according to the provider's retry settings.
+// The whole call is bounded by the request timeout until the response body is
+// closed. The caller must close the returned response body.
+func (p *FlipswitchProvider) doRequest(ctx context.Context, url string, body []byte) (*http.Response, error) {
+	if p.offlineMode {
+		return nil, errOfflineMode
+	}
+	if p.requestTimeout <= 0 {
+		return p.retryRequest(ctx, url, body)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.requestTimeout)
+	resp, err := p.retryRequest(ctx, url, body)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: cancel}
+	return resp, nil
+}
+
+// requestContext bounds ctx by the request timeout, if there is one, for
+// calls that read the whole response before returning.
+func (p *FlipswitchProvider) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.requestTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, p.requestTimeout)
+}
+
+// retryRequest runs the attempts of a doRequest call.
+func (p *FlipswitchProvider) retryRequest(ctx context.Context, url string, body []byte) (*http.Response, error) {
+	maxAttempts := p.retryMaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	p.capture.record(body)
+
+	delay := p.retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := p.doAttempt(ctx, url, body)
+		if attempt >= maxAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+		wait := delay
+		if err == nil {
+			if !p.retryableStatusCodes[resp.StatusCode] {
+				return resp, nil
+			}
+			if after, ok := retryAfter(resp, p.clock.Now()); ok {
+				wait = after
+			}
+			// Drain so the connection can be reused for the next attempt
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			p.logger.Debugw("Retrying evaluation request", "url", url, "attempt", attempt, "statusCode", resp.StatusCode, "wait", wait)
+		} else {
+			p.logger.Debugw("Retrying evaluation request", "url", url, "attempt", attempt, "error", err)
+		}
+
+		select {
+		case <-p.clock.After(wait):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+// retryAfter returns the wait requested by the Retry-After header of a 429
+// or 503 response, given in seconds or as an HTTP date relative to now, and
+// capped at maxRetryAfter. It reports false if there is no usable header.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = max(date.Sub(now), 0)
+	} else {
+		return 0, false
+	}
+	return min(wait, maxRetryAfter), true
+}
+
+// doAttempt performs a single evaluation request. The attempt holds a
+// concurrency slot, and is bounded by the per-attempt timeout, until its
+// response body is closed.
+func (p *FlipswitchProvider) doAttempt(ctx context.Context, url string, body []byte) (*http.Response, error) {
+	releaseSlot, err := p.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+	if p.perAttemptTimeout > 0 {
+		attemptCtx, cancel = context.WithTimeout(ctx, p.perAttemptTimeout)
+	}
+	release := func() {
+		cancel()
+		releaseSlot()
+	}
+
+	req, err := http.NewRequestWithContext(attemptCtx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", p.apiKey)
+	setProtocolHeader(req)
+	p.setUserAgent(req)
+	p.setTelemetryHeaders(req)
+	p.setCustomHeaders(req)
+	if p.connectionIDOnEvaluations {
+		req.Header.Set(connectionIDHeader, p.connectionID)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
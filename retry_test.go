@@ -0,0 +1,489 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// failingThenOK returns a flag response func that responds with statusCode
+// for the first failures calls and succeeds afterwards, counting every call.
+func failingThenOK(calls *int32, failures int32, statusCode int) func() (int, map[string]interface{}) {
+	return func() (int, map[string]interface{}) {
+		if atomic.AddInt32(calls, 1) <= failures {
+			return statusCode, map[string]interface{}{}
+		}
+		return 200, map[string]interface{}{"key": "my-flag", "value": true}
+	}
+}
+
+func TestRetry_DisabledByDefault(t *testing.T) {
+	var calls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", failingThenOK(&calls, 1, 503))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{}); result != nil {
+		t.Errorf("Expected nil without retries, got %+v", result)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+}
+
+func TestRetry_DefaultRetryableStatusIsRetried(t *testing.T) {
+	var calls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", failingThenOK(&calls, 1, 503))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithEvaluationRetries(3, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	if result == nil || result.Value != true {
+		t.Errorf("Expected retried evaluation to succeed, got %+v", result)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 requests, got %d", got)
+	}
+}
+
+func TestRetryableStatusCodes_CustomCodeIsRetried(t *testing.T) {
+	var calls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", failingThenOK(&calls, 2, 408))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithEvaluationRetries(3, time.Millisecond),
+		WithRetryableStatusCodes(408, 425),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	if result == nil || result.Value != true {
+		t.Errorf("Expected 408 to be retried until success, got %+v", result)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+}
+
+func TestRetryableStatusCodes_DefaultCodeCanBeExcluded(t *testing.T) {
+	var calls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", failingThenOK(&calls, 1, 429))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithEvaluationRetries(3, time.Millisecond),
+		WithRetryableStatusCodes(500, 502, 503, 504),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{}); result != nil {
+		t.Errorf("Expected 429 not to be retried, got %+v", result)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+}
+
+func TestRetry_StopsAtMaxAttempts(t *testing.T) {
+	var calls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		atomic.AddInt32(&calls, 1)
+		return 502, map[string]interface{}{}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithEvaluationRetries(3, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if flags := provider.EvaluateAllFlags(openfeature.FlattenedContext{}); len(flags) != 0 {
+		t.Errorf("Expected empty result, got %d flags", len(flags))
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+}
+
+func TestRetry_BulkFailsTwiceThenSucceeds(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			// A connection reset is a transport error
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"flags": [{"key": "my-flag", "value": true}]}`))
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithEvaluationRetries(3, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	flags, err := provider.EvaluateAllFlagsE(openfeature.FlattenedContext{})
+	if err != nil || len(flags) != 1 || flags[0].Value != true {
+		t.Errorf("Expected the third attempt to succeed, got %+v, %v", flags, err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+}
+
+func TestRetry_NonRetryableStatusFailsFast(t *testing.T) {
+	for _, statusCode := range []int{400, 401, 404} {
+		var calls int32
+		dispatcher := NewTestDispatcher()
+		dispatcher.SetFlagResponse("my-flag", failingThenOK(&calls, 1, statusCode))
+		server := httptest.NewServer(dispatcher)
+
+		provider, err := NewProvider(
+			"test-api-key",
+			WithBaseURL(server.URL),
+			WithRealtime(false),
+			WithEvaluationRetries(3, time.Millisecond),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create provider: %v", err)
+		}
+
+		if result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{}); result != nil {
+			t.Errorf("%d: expected nil, got %+v", statusCode, result)
+		}
+		if got := atomic.LoadInt32(&calls); got != 1 {
+			t.Errorf("%d: expected 1 request, got %d", statusCode, got)
+		}
+		provider.Shutdown()
+		server.Close()
+	}
+}
+
+func TestRetry_HonorsRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"key": "my-flag", "value": true}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithEvaluationRetries(2, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	start := time.Now()
+	result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	if result == nil || result.Value != true {
+		t.Errorf("Expected the retry to succeed, got %+v", result)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected the retry to wait for Retry-After, took %v", elapsed)
+	}
+}
+
+func TestRetry_StopsWhenContextIsCancelled(t *testing.T) {
+	var calls int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", failingThenOK(&calls, 10, 503))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithEvaluationRetries(10, time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if result := provider.EvaluateFlagCtx(ctx, "my-flag", openfeature.FlattenedContext{}); result != nil {
+		t.Errorf("Expected nil, got %+v", result)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected cancellation to stop the retries, took %v", elapsed)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		statusCode int
+		header     string
+		want       time.Duration
+		wantOK     bool
+	}{
+		{"seconds", 503, "2", 2 * time.Second, true},
+		{"http date", 429, now.Add(5 * time.Second).Format(http.TimeFormat), 5 * time.Second, true},
+		{"date in the past", 429, now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"capped", 503, "3600", maxRetryAfter, true},
+		{"missing", 503, "", 0, false},
+		{"invalid", 503, "soon", 0, false},
+		{"negative", 503, "-1", 0, false},
+		{"other status", 502, "2", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.statusCode, Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("Retry-After", tt.header)
+			}
+			got, ok := retryAfter(resp, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("retryAfter() = %v, %v; want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPerAttemptTimeout_StalledAttemptIsRetried(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", func() (int, map[string]interface{}) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			<-release
+		}
+		return 200, map[string]interface{}{"key": "my-flag", "value": true}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+	// Unblock the stalled handler before the server shuts down
+	defer close(release)
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithEvaluationRetries(3, time.Millisecond),
+		WithPerAttemptTimeout(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	result, err := provider.evaluateFlag(ctx, "my-flag", openfeature.FlattenedContext{})
+	if err != nil {
+		t.Fatalf("Expected the retried attempt to succeed, got %v", err)
+	}
+	if !result.AsBoolean() {
+		t.Errorf("Expected true, got %v", result.Value)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 requests, got %d", got)
+	}
+	if ctx.Err() != nil {
+		t.Errorf("Expected success within the overall deadline")
+	}
+}
+
+// stalledServer serves my-flag and the bulk endpoint, both blocking until
+// the returned release channel is closed.
+func stalledServer() (*httptest.Server, chan struct{}) {
+	release := make(chan struct{})
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", func() (int, map[string]interface{}) {
+		<-release
+		return 200, map[string]interface{}{"key": "my-flag", "value": true}
+	})
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		<-release
+		return 200, map[string]interface{}{"flags": []interface{}{}}
+	})
+	return httptest.NewServer(dispatcher), release
+}
+
+func TestRequestTimeout_EvaluateFlagReturnsNil(t *testing.T) {
+	server, release := stalledServer()
+	defer server.Close()
+	defer close(release)
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithRequestTimeout(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	start := time.Now()
+	if result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{}); result != nil {
+		t.Errorf("Expected nil when the request times out, got %+v", result)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the timeout to end the call, took %v", elapsed)
+	}
+}
+
+func TestRequestTimeout_EvaluateAllFlagsReturnsEmpty(t *testing.T) {
+	server, release := stalledServer()
+	defer server.Close()
+	defer close(release)
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithRequestTimeout(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	start := time.Now()
+	flags := provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+	if flags == nil || len(flags) != 0 {
+		t.Errorf("Expected an empty slice when the request times out, got %v", flags)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the timeout to end the call, took %v", elapsed)
+	}
+}
+
+func TestRequestTimeout_TypedEvaluationReturnsDefault(t *testing.T) {
+	server, release := stalledServer()
+	defer server.Close()
+	defer close(release)
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithRequestTimeout(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	start := time.Now()
+	result := provider.BooleanEvaluation(context.Background(), "my-flag", false, openfeature.FlattenedContext{})
+	if result.Value != false || result.Reason != openfeature.ErrorReason {
+		t.Errorf("Expected the default with an error when the request times out, got %+v", result)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the timeout to end the call, took %v", elapsed)
+	}
+}
+
+func TestRequestTimeout_ServesBootstrapOnTimeout(t *testing.T) {
+	server, release := stalledServer()
+	defer server.Close()
+	defer close(release)
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithRequestTimeout(50*time.Millisecond),
+		WithBootstrap([]FlagEvaluation{{Key: "my-flag", Value: false}}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	if result == nil || result.Value != false {
+		t.Errorf("Expected the bootstrapped value like any network error, got %+v", result)
+	}
+}
+
+func TestRequestTimeout_DefaultIsSet(t *testing.T) {
+	provider, err := NewProvider("test-api-key", WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	if provider.requestTimeout != defaultRequestTimeout {
+		t.Errorf("Expected default timeout %v, got %v", defaultRequestTimeout, provider.requestTimeout)
+	}
+}
@@ -0,0 +1,318 @@
+package flipswitch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// schemaMetadataKey is the flag metadata entry holding a value's JSON schema.
+const schemaMetadataKey = "schema"
+
+// ErrSchemaMismatch is returned when a flag's value does not match the JSON
+// schema in its metadata.
+var ErrSchemaMismatch = errors.New("flag value does not match its schema")
+
+// WithSchemaValidation validates the values of object flags against the JSON
+// schema in the flag's "schema" metadata, given either as an object or as a
+// JSON string. A value that does not conform is not served: ObjectEvaluation
+// returns the default value with reason ERROR and error code PARSE_ERROR, and
+// EvaluateObjectInto returns an error wrapping ErrSchemaMismatch, so a
+// malformed configuration flag cannot reach downstream code. Values served
+// from the cache, a bootstrap value or a last known value are checked
+// against the schema most recently received for the flag. Flags without a
+// schema are served unchanged.
+//
+// The keywords type, enum, const, properties, required,
+// additionalProperties, items, minItems, maxItems, minLength, maxLength,
+// pattern, minimum, maximum, exclusiveMinimum and exclusiveMaximum are
+// supported; other keywords are ignored.
+func WithSchemaValidation(enabled bool) Option {
+	return func(p *FlipswitchProvider) {
+		p.schemaValidation = enabled
+	}
+}
+
+// schemaStore holds the schema of each flag as last received in its
+// metadata, so values that carry no metadata, from the cache or a fallback,
+// can be validated too.
+type schemaStore struct {
+	schemas map[string]interface{}
+	mu      sync.RWMutex
+}
+
+// set records the schema in metadata for flagKey, or forgets the flag's
+// schema if metadata has none.
+func (s *schemaStore) set(flagKey string, metadata map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	raw, ok := metadata[schemaMetadataKey]
+	if !ok {
+		delete(s.schemas, flagKey)
+		return
+	}
+	if s.schemas == nil {
+		s.schemas = make(map[string]interface{})
+	}
+	s.schemas[flagKey] = raw
+}
+
+func (s *schemaStore) get(flagKey string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	raw, ok := s.schemas[flagKey]
+	return raw, ok
+}
+
+// learnSchema records the schema in the metadata of a fresh resolution of
+// flag. Failed resolutions leave the recorded schema alone.
+func (p *FlipswitchProvider) learnSchema(flag string, detail openfeature.ProviderResolutionDetail) {
+	if !p.schemaValidation || detail.Reason == openfeature.ErrorReason || detail.ResolutionDetail().ErrorCode != "" {
+		return
+	}
+	p.schemas.set(flag, detail.FlagMetadata)
+}
+
+// learnResponseSchema is learnSchema for a direct single flag response.
+func (p *FlipswitchProvider) learnResponseSchema(flag string, respBody []byte) {
+	if !p.schemaValidation {
+		return
+	}
+	var data struct {
+		Metadata map[string]interface{} `json:"metadata"`
+	}
+	if err := json.Unmarshal(respBody, &data); err == nil {
+		p.schemas.set(flag, data.Metadata)
+	}
+}
+
+// validateFlagSchema checks value against the recorded schema of flag. It
+// returns an error wrapping ErrSchemaMismatch if the value does not conform.
+func (p *FlipswitchProvider) validateFlagSchema(flag string, value interface{}) error {
+	if !p.schemaValidation {
+		return nil
+	}
+	raw, ok := p.schemas.get(flag)
+	if !ok {
+		return nil
+	}
+	schema, err := parseSchema(raw)
+	if err == nil {
+		err = validateSchema(value, schema, "$")
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSchemaMismatch, err)
+	}
+	return nil
+}
+
+// checkSchema validates the value of a successful object resolution against
+// the recorded schema of flag. If the value does not conform, it returns the
+// resolution to serve instead and true.
+func (p *FlipswitchProvider) checkSchema(flag string, defaultValue interface{}, result openfeature.InterfaceResolutionDetail) (openfeature.InterfaceResolutionDetail, bool) {
+	if !p.schemaValidation || result.Reason == openfeature.ErrorReason || result.ResolutionDetail().ErrorCode != "" {
+		return result, false
+	}
+	err := p.validateFlagSchema(flag, result.Value)
+	if err == nil {
+		return result, false
+	}
+
+	p.logger.Warnw("Flag value does not match its schema, serving the default", "flagKey", flag, "error", err)
+	return openfeature.InterfaceResolutionDetail{
+		Value: defaultValue,
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+			ResolutionError: openfeature.NewParseErrorResolutionError(fmt.Sprintf("flag %q: %v", flag, err)),
+			Reason:          openfeature.ErrorReason,
+			FlagMetadata:    result.FlagMetadata,
+		},
+	}, true
+}
+
+// parseSchema returns the schema in a metadata entry, which is either
+// already decoded or a JSON string.
+func parseSchema(raw interface{}) (map[string]interface{}, error) {
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case string:
+		var schema map[string]interface{}
+		if err := json.Unmarshal([]byte(v), &schema); err != nil {
+			return nil, fmt.Errorf("invalid schema: %w", err)
+		}
+		return schema, nil
+	}
+	return nil, fmt.Errorf("invalid schema: want object or JSON string, got %T", raw)
+}
+
+// validateSchema checks value, found at path, against schema.
+func validateSchema(value interface{}, schema map[string]interface{}, path string) error {
+	if types, ok := schema["type"]; ok && !matchesSchemaType(value, types) {
+		return fmt.Errorf("%s: want type %v, got %s", path, types, jsonType(value))
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			if reflect.DeepEqual(value, allowed) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: value is not one of %v", path, enum)
+		}
+	}
+	if want, ok := schema["const"]; ok && !reflect.DeepEqual(value, want) {
+		return fmt.Errorf("%s: value must be %v", path, want)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return validateSchemaObject(v, schema, path)
+	case []interface{}:
+		if n, ok := schemaNumber(schema, "minItems"); ok && float64(len(v)) < n {
+			return fmt.Errorf("%s: want at least %v items, got %d", path, n, len(v))
+		}
+		if n, ok := schemaNumber(schema, "maxItems"); ok && float64(len(v)) > n {
+			return fmt.Errorf("%s: want at most %v items, got %d", path, n, len(v))
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := validateSchema(item, items, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		length := float64(len([]rune(v)))
+		if n, ok := schemaNumber(schema, "minLength"); ok && length < n {
+			return fmt.Errorf("%s: want at least %v characters", path, n)
+		}
+		if n, ok := schemaNumber(schema, "maxLength"); ok && length > n {
+			return fmt.Errorf("%s: want at most %v characters", path, n)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("%s: invalid pattern %q: %w", path, pattern, err)
+			}
+			if !re.MatchString(v) {
+				return fmt.Errorf("%s: %q does not match %q", path, v, pattern)
+			}
+		}
+	case float64:
+		if n, ok := schemaNumber(schema, "minimum"); ok && v < n {
+			return fmt.Errorf("%s: %v is less than %v", path, v, n)
+		}
+		if n, ok := schemaNumber(schema, "maximum"); ok && v > n {
+			return fmt.Errorf("%s: %v is greater than %v", path, v, n)
+		}
+		if n, ok := schemaNumber(schema, "exclusiveMinimum"); ok && v <= n {
+			return fmt.Errorf("%s: %v is not greater than %v", path, v, n)
+		}
+		if n, ok := schemaNumber(schema, "exclusiveMaximum"); ok && v >= n {
+			return fmt.Errorf("%s: %v is not less than %v", path, v, n)
+		}
+	}
+	return nil
+}
+
+// validateSchemaObject checks the object keywords of schema against obj.
+func validateSchemaObject(obj map[string]interface{}, schema map[string]interface{}, path string) error {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok {
+				if _, present := obj[key]; !present {
+					return fmt.Errorf("%s: missing required property %q", path, key)
+				}
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	// Validate in a stable order so the reported error is deterministic
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if propSchema, ok := properties[key].(map[string]interface{}); ok {
+			if err := validateSchema(obj[key], propSchema, path+"."+key); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, declared := properties[key]; declared {
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return fmt.Errorf("%s: unexpected property %q", path, key)
+			}
+		case map[string]interface{}:
+			if err := validateSchema(obj[key], additional, path+"."+key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// matchesSchemaType reports whether value has the type named by types,
+// which is a type name or a list of them.
+func matchesSchemaType(value interface{}, types interface{}) bool {
+	switch t := types.(type) {
+	case string:
+		return matchesTypeName(value, t)
+	case []interface{}:
+		for _, name := range t {
+			if s, ok := name.(string); ok && matchesTypeName(value, s) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesTypeName(value interface{}, name string) bool {
+	if name == "integer" {
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	}
+	return jsonType(value) == name
+}
+
+// jsonType returns the JSON Schema type name of a decoded JSON value.
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// schemaNumber returns a numeric keyword of schema.
+func schemaNumber(schema map[string]interface{}, keyword string) (float64, bool) {
+	n, ok := schema[keyword].(float64)
+	return n, ok
+}
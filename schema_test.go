@@ -0,0 +1,212 @@
+package flipswitch
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+var limitsSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []interface{}{"rps", "mode"},
+	"properties": map[string]interface{}{
+		"rps":  map[string]interface{}{"type": "integer", "minimum": float64(1)},
+		"mode": map[string]interface{}{"enum": []interface{}{"soft", "hard"}},
+	},
+	"additionalProperties": false,
+}
+
+// schemaServer serves the object flag "limits" with the given value and
+// schema metadata.
+func schemaServer(value interface{}, schema interface{}) *httptest.Server {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("limits", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{
+			"key":      "limits",
+			"value":    value,
+			"reason":   "TARGETING_MATCH",
+			"metadata": map[string]interface{}{"schema": schema},
+		}
+	})
+	return httptest.NewServer(dispatcher)
+}
+
+func evaluateLimits(t *testing.T, server *httptest.Server, opts ...Option) openfeature.InterfaceResolutionDetail {
+	t.Helper()
+	provider, err := NewProvider("test-api-key", append([]Option{WithBaseURL(server.URL), WithRealtime(false)}, opts...)...)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+	return provider.ObjectEvaluation(context.Background(), "limits", "default", openfeature.FlattenedContext{})
+}
+
+func TestSchemaValidation_ConformingValueIsServed(t *testing.T) {
+	value := map[string]interface{}{"rps": float64(100), "mode": "soft"}
+	server := schemaServer(value, limitsSchema)
+	defer server.Close()
+
+	result := evaluateLimits(t, server, WithSchemaValidation(true))
+	if result.Reason != openfeature.TargetingMatchReason || result.ResolutionDetail().ErrorCode != "" {
+		t.Fatalf("Expected a successful resolution, got %+v", result.ProviderResolutionDetail)
+	}
+	if got, ok := result.Value.(map[string]interface{}); !ok || got["mode"] != "soft" {
+		t.Errorf("Expected the flag value, got %v", result.Value)
+	}
+}
+
+func TestSchemaValidation_NonConformingValueServesDefault(t *testing.T) {
+	value := map[string]interface{}{"rps": float64(0.5), "mode": "soft"}
+	server := schemaServer(value, limitsSchema)
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	result := evaluateLimits(t, server, WithSchemaValidation(true), WithLogger(logger))
+	if result.Value != "default" {
+		t.Errorf("Expected the default value, got %v", result.Value)
+	}
+	if result.Reason != openfeature.ErrorReason || result.ResolutionDetail().ErrorCode != openfeature.ParseErrorCode {
+		t.Errorf("Expected ERROR/PARSE_ERROR, got %+v", result.ResolutionDetail())
+	}
+	if _, ok := logger.find("Flag value does not match its schema, serving the default"); !ok {
+		t.Error("Expected a warning to be logged")
+	}
+}
+
+func TestSchemaValidation_SchemaAsJSONString(t *testing.T) {
+	schema := `{"type":"object","required":["rps"]}`
+	server := schemaServer(map[string]interface{}{"mode": "soft"}, schema)
+	defer server.Close()
+
+	if result := evaluateLimits(t, server, WithSchemaValidation(true)); result.ResolutionDetail().ErrorCode != openfeature.ParseErrorCode {
+		t.Errorf("Expected PARSE_ERROR for a missing required property, got %+v", result.ResolutionDetail())
+	}
+}
+
+func TestSchemaValidation_DisabledByDefault(t *testing.T) {
+	value := map[string]interface{}{"unexpected": true}
+	server := schemaServer(value, limitsSchema)
+	defer server.Close()
+
+	result := evaluateLimits(t, server)
+	if result.ResolutionDetail().ErrorCode != "" || result.Value == "default" {
+		t.Errorf("Expected the value to be served without validation, got %+v", result)
+	}
+}
+
+func TestSchemaValidation_CachedValueIsChecked(t *testing.T) {
+	value := map[string]interface{}{"rps": float64(0.5), "mode": "soft"}
+	server := schemaServer(value, limitsSchema)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithCache(time.Minute),
+		WithSchemaValidation(true),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	// A direct evaluation caches the value without checking it
+	evalCtx := openfeature.FlattenedContext{}
+	if flag := provider.EvaluateFlag("limits", evalCtx); flag == nil {
+		t.Fatal("Expected the flag to be evaluated")
+	}
+
+	result := provider.ObjectEvaluation(context.Background(), "limits", "default", evalCtx)
+	if result.Value != "default" || result.ResolutionDetail().ErrorCode != openfeature.ParseErrorCode {
+		t.Errorf("Expected the cached value to be rejected, got %+v", result)
+	}
+}
+
+func TestSchemaValidation_FallbackValueIsChecked(t *testing.T) {
+	var failing int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("limits", func() (int, map[string]interface{}) {
+		if atomic.LoadInt32(&failing) == 1 {
+			return 503, map[string]interface{}{}
+		}
+		return 200, map[string]interface{}{
+			"key":      "limits",
+			"value":    map[string]interface{}{"rps": float64(0.5), "mode": "soft"},
+			"metadata": map[string]interface{}{"schema": limitsSchema},
+		}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createStaleProvider(t, server, WithSchemaValidation(true))
+	defer provider.Shutdown()
+
+	evalCtx := openfeature.FlattenedContext{}
+	if flag := provider.EvaluateFlag("limits", evalCtx); flag == nil {
+		t.Fatal("Expected the flag to be evaluated")
+	}
+	atomic.StoreInt32(&failing, 1)
+
+	result := provider.ObjectEvaluation(context.Background(), "limits", "default", evalCtx)
+	if result.Value != "default" || result.ResolutionDetail().ErrorCode != openfeature.ParseErrorCode {
+		t.Errorf("Expected the last known value to be rejected, got %+v", result)
+	}
+}
+
+func TestSchemaValidation_EvaluateObjectInto(t *testing.T) {
+	value := map[string]interface{}{"rps": float64(0.5), "mode": "soft"}
+	server := schemaServer(value, limitsSchema)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key", WithBaseURL(server.URL), WithRealtime(false), WithSchemaValidation(true))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	var out struct {
+		Mode string `json:"mode"`
+	}
+	err = provider.EvaluateObjectInto("limits", openfeature.FlattenedContext{}, &out)
+	if !errors.Is(err, ErrSchemaMismatch) {
+		t.Errorf("Expected ErrSchemaMismatch, got %v", err)
+	}
+	if out.Mode != "" {
+		t.Errorf("Expected out to be left untouched, got %+v", out)
+	}
+}
+
+func TestValidateSchema_Keywords(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  interface{}
+		schema map[string]interface{}
+		valid  bool
+	}{
+		{"type list", nil, map[string]interface{}{"type": []interface{}{"string", "null"}}, true},
+		{"wrong type", "x", map[string]interface{}{"type": "number"}, false},
+		{"integer", float64(3), map[string]interface{}{"type": "integer"}, true},
+		{"not integer", 3.5, map[string]interface{}{"type": "integer"}, false},
+		{"const", "a", map[string]interface{}{"const": "b"}, false},
+		{"items", []interface{}{"a", float64(1)}, map[string]interface{}{"items": map[string]interface{}{"type": "string"}}, false},
+		{"minItems", []interface{}{}, map[string]interface{}{"minItems": float64(1)}, false},
+		{"maxLength", "abcd", map[string]interface{}{"maxLength": float64(3)}, false},
+		{"pattern", "abc", map[string]interface{}{"pattern": "^a"}, true},
+		{"pattern mismatch", "abc", map[string]interface{}{"pattern": "^b"}, false},
+		{"exclusiveMaximum", float64(10), map[string]interface{}{"exclusiveMaximum": float64(10)}, false},
+		{"additionalProperties schema", map[string]interface{}{"x": "y"},
+			map[string]interface{}{"additionalProperties": map[string]interface{}{"type": "number"}}, false},
+		{"unknown keyword", "x", map[string]interface{}{"format": "email"}, true},
+	}
+	for _, tt := range tests {
+		err := validateSchema(tt.value, tt.schema, "$")
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: expected valid=%v, got %v", tt.name, tt.valid, err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package flipswitch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// snapshotEntry is a flag as written to the snapshot file, in the format of
+// a bulk evaluation response item so that parseBulkResponse reads it back.
+type snapshotEntry struct {
+	Key      string            `json:"key"`
+	Value    interface{}       `json:"value"`
+	Reason   string            `json:"reason,omitempty"`
+	Variant  string            `json:"variant,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// snapshotFlagTypes maps value types to the flagType metadata that
+// getFlagType maps back. Other types are inferred from the value.
+var snapshotFlagTypes = map[string]string{
+	"boolean": "boolean",
+	"string":  "string",
+	"integer": "integer",
+	"number":  "decimal",
+}
+
+// WithSnapshotFile keeps the flags of the most recent successful bulk
+// evaluation for the refresh context, the one passed to Init or set with
+// SetRefreshContext, in the file at path, so they survive restarts. After
+// every successful bulk evaluation for that context, whether by
+// EvaluateAllFlags, polling, WithAutoRefresh or WithReadyAfterFirstSync,
+// the flags are written to the file, atomically and only if they changed;
+// bulk evaluations for other contexts are never written. On Init, flags in
+// the file are loaded as fallback values, served like WithBootstrap values
+// when the server cannot be reached and never in place of a live
+// evaluation. Flags given with WithBootstrap or WithInitialFlags take
+// precedence. A missing file is ignored; an unreadable one is logged and
+// ignored.
+func WithSnapshotFile(path string) Option {
+	return func(p *FlipswitchProvider) {
+		p.snapshotFile = path
+	}
+}
+
+// prepareSnapshot creates the bootstrap store the snapshot file is loaded
+// into, so Init can fill it while evaluations may already be running.
+func (p *FlipswitchProvider) prepareSnapshot() {
+	if p.snapshotFile != "" && p.bootstrap == nil {
+		p.bootstrap = newBootstrapStore(nil)
+	}
+}
+
+// loadSnapshot adds the flags in the snapshot file, if there is one, to the
+// bootstrap flags they do not override.
+func (p *FlipswitchProvider) loadSnapshot() {
+	if p.snapshotFile == "" {
+		return
+	}
+	data, err := os.ReadFile(p.snapshotFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	var flags []FlagEvaluation
+	if err == nil {
+		flags, err = parseBulkResponse(data)
+	}
+	if err != nil {
+		p.logger.Warnw("Ignoring unreadable flag snapshot", append([]any{"path", p.snapshotFile}, errorFields(err)...)...)
+		return
+	}
+
+	for _, flag := range flags {
+		if _, ok := p.bootstrap.get(flag.Key); !ok {
+			p.bootstrap.put(flag)
+		}
+	}
+
+	p.snapshotMu.Lock()
+	p.snapshotLast = data
+	p.snapshotMu.Unlock()
+	p.logger.Infow("Loaded flag snapshot", "path", p.snapshotFile, "flags", len(flags))
+}
+
+// saveSnapshot writes flags to the snapshot file if they were evaluated for
+// the refresh context, unless they are what the file already holds. Failures
+// are logged.
+func (p *FlipswitchProvider) saveSnapshot(evalCtx openfeature.FlattenedContext, flags []FlagEvaluation) {
+	if p.snapshotFile == "" || !p.isRefreshContext(evalCtx) {
+		return
+	}
+	entries := make([]snapshotEntry, len(flags))
+	for i, flag := range flags {
+		entries[i] = snapshotEntry{Key: flag.Key, Value: flag.Value, Reason: flag.Reason, Variant: flag.Variant}
+		if flagType, ok := snapshotFlagTypes[flag.ValueType]; ok {
+			entries[i].Metadata = map[string]string{"flagType": flagType}
+		}
+	}
+	data, err := json.Marshal(map[string]interface{}{"flags": entries})
+	if err != nil {
+		p.logger.Warnw("Failed to encode flag snapshot", errorFields(err)...)
+		return
+	}
+
+	p.snapshotMu.Lock()
+	defer p.snapshotMu.Unlock()
+	if bytes.Equal(data, p.snapshotLast) {
+		return
+	}
+	if err := writeFileAtomic(p.snapshotFile, data); err != nil {
+		p.logger.Warnw("Failed to write flag snapshot", append([]any{"path", p.snapshotFile}, errorFields(err)...)...)
+		return
+	}
+	p.snapshotLast = data
+}
+
+// writeFileAtomic replaces the file at path with data by writing a
+// temporary file in the same directory and renaming it over path, so
+// readers never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing temporary file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("syncing temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing temporary file: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,233 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// snapshotServer serves a bulk evaluation with a flag of each scalar type
+// and counts single flag requests.
+func snapshotServer(calls *int32) *httptest.Server {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"flags": []interface{}{
+			map[string]interface{}{"key": "dark-mode", "value": true, "reason": "TARGETING_MATCH", "variant": "on"},
+			map[string]interface{}{"key": "welcome", "value": "hi"},
+			map[string]interface{}{"key": "max-items", "value": 25, "metadata": map[string]interface{}{"flagType": "integer"}},
+			map[string]interface{}{"key": "ratio", "value": 0.5},
+		}}
+	})
+	dispatcher.SetFlagResponse("dark-mode", countingFlag("dark-mode", calls))
+	return httptest.NewServer(dispatcher)
+}
+
+func TestWithSnapshotFile_RestoresFlagsOnInit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+
+	var calls int32
+	server := snapshotServer(&calls)
+	defer server.Close()
+
+	writer, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithSnapshotFile(path),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer writer.Shutdown()
+	if err := writer.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	live := writer.EvaluateAllFlags(openfeature.FlattenedContext{})
+	if len(live) != 4 {
+		t.Fatalf("Expected 4 flags, got %d", len(live))
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Expected the snapshot to be written: %v", err)
+	}
+
+	// A restart while the server is down serves the snapshot
+	reader, err := NewProvider("test-api-key",
+		WithBaseURL(unreachableURL()),
+		WithRealtime(false),
+		WithSnapshotFile(path),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer reader.Shutdown()
+
+	if err := reader.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Expected Init to succeed from the snapshot, got: %v", err)
+	}
+	if reader.Status() != openfeature.StaleState {
+		t.Errorf("Expected status %q, got %q", openfeature.StaleState, reader.Status())
+	}
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	for _, want := range live {
+		got := reader.EvaluateFlag(want.Key, evalCtx)
+		if got == nil || got.Value != want.Value || got.ValueType != want.ValueType || got.Variant != want.Variant {
+			t.Errorf("Expected the snapshot to restore %+v, got %+v", want, got)
+		}
+	}
+	if got := reader.IntEvaluation(context.Background(), "max-items", 0, evalCtx).Value; got != 25 {
+		t.Errorf("Expected 25 from the snapshot, got %d", got)
+	}
+}
+
+func TestWithSnapshotFile_LiveValuesPreferred(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	data := []byte(`{"flags":[{"key":"dark-mode","value":false,"variant":"off"}]}`)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls int32
+	server := snapshotServer(&calls)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithSnapshotFile(path),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	flag := provider.EvaluateFlag("dark-mode", openfeature.FlattenedContext{"targetingKey": "user-1"})
+	if flag == nil || flag.Value != true {
+		t.Errorf("Expected the live value while the server is up, got %+v", flag)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected 1 flag request, got %d", n)
+	}
+}
+
+func TestWithSnapshotFile_OnlyRefreshContextIsWritten(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+
+	var calls int32
+	server := snapshotServer(&calls)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithSnapshotFile(path),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	// Before Init there is no refresh context
+	provider.EvaluateAllFlags(openfeature.FlattenedContext{"targetingKey": "user-1"})
+	if _, err := os.Stat(path); err == nil {
+		t.Fatal("Expected no snapshot before Init")
+	}
+
+	if err := provider.Init(openfeature.NewEvaluationContext("user-1", nil)); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	provider.EvaluateAllFlags(openfeature.FlattenedContext{"targetingKey": "user-2"})
+	if _, err := os.Stat(path); err == nil {
+		t.Fatal("Expected another user's flags not to be written")
+	}
+
+	provider.EvaluateAllFlags(openfeature.FlattenedContext{"targetingKey": "user-1"})
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected the refresh context's flags to be written: %v", err)
+	}
+}
+
+func TestWithSnapshotFile_ConcurrentWritesLeaveOneFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "flags.json")
+
+	var calls int32
+	server := snapshotServer(&calls)
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithSnapshotFile(path),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+		}()
+	}
+	wg.Wait()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "flags.json" {
+		t.Errorf("Expected only the snapshot file, got %v", entries)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flags, err := parseBulkResponse(data); err != nil || len(flags) != 4 {
+		t.Errorf("Expected a complete snapshot of 4 flags, got %d (%v)", len(flags), err)
+	}
+}
+
+func TestWithSnapshotFile_UnreadableFileIsIgnored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	if err := os.WriteFile(path, []byte(`{"flags":`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(NewTestDispatcher())
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithSnapshotFile(path),
+		WithLogger(logger),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+		t.Fatalf("Expected Init to succeed despite the unreadable snapshot, got: %v", err)
+	}
+	if _, ok := logger.find("Ignoring unreadable flag snapshot"); !ok {
+		t.Error("Expected a warning about the unreadable snapshot")
+	}
+}
@@ -3,7 +3,10 @@ package flipswitch
 import (
 	"bufio"
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"strings"
 	"sync"
@@ -12,9 +15,10 @@ import (
 	"encoding/json"
 )
 
+// Default bounds of the SSE reconnect backoff, see WithSseRetryBounds.
 const (
-	minRetryDelay = 1 * time.Second
-	maxRetryDelay = 30 * time.Second
+	defaultMinRetryDelay = 1 * time.Second
+	defaultMaxRetryDelay = 30 * time.Second
 )
 
 // SseClient handles SSE connections for real-time flag change notifications.
@@ -26,12 +30,110 @@ type SseClient struct {
 	onStatusChange   ConnectionStatusHandler
 	httpClient       *http.Client
 
+	// connectionID is sent on every connection attempt so that the server
+	// can correlate the client's sessions across reconnects
+	connectionID string
+
+	// lastEventID is the id of the most recent event received, sent as
+	// Last-Event-ID on reconnect so the server can replay missed events
+	lastEventID string
+
+	rng        *lockedRand
+	clock      clock
+	onError    func(error)
+	recorder   io.Writer
+	logger     Logger
+	eventTypes map[string]ChangeType
 	status     ConnectionStatus
 	retryDelay time.Duration
 	closed     bool
+	running    bool
 	mu         sync.RWMutex
 	ctx        context.Context
 	cancel     context.CancelFunc
+
+	// Bounds of the reconnect backoff
+	minRetryDelay time.Duration
+	maxRetryDelay time.Duration
+
+	// reconnectAttempts counts the reconnects scheduled since the last
+	// successful connection
+	reconnectAttempts int
+
+	// headers are extra headers sent on every connection attempt
+	headers http.Header
+
+	// path is the path of the event stream, relative to baseURL
+	path string
+
+	// userAgent is sent as User-Agent on every connection attempt
+	userAgent string
+
+	// readTimeout is how long a connection may go without receiving data
+	// before it is dropped; zero disables the check
+	readTimeout time.Duration
+
+	// loops tracks the connection goroutine started by Connect, so that
+	// CloseContext can wait for it to exit
+	loops sync.WaitGroup
+}
+
+// SseOption is a functional option for configuring an SseClient.
+type SseOption func(*SseClient)
+
+// withSseRand sets the random source used for reconnect jitter.
+func withSseRand(rng *lockedRand) SseOption {
+	return func(c *SseClient) {
+		c.rng = rng
+	}
+}
+
+// withSseErrorHandler sets a function called with every connection and read
+// error, for the provider's error history.
+func withSseErrorHandler(handler func(error)) SseOption {
+	return func(c *SseClient) {
+		c.onError = handler
+	}
+}
+
+// withSseRetryBounds sets the bounds of the reconnect backoff.
+func withSseRetryBounds(min, max time.Duration) SseOption {
+	return func(c *SseClient) {
+		c.minRetryDelay = min
+		c.maxRetryDelay = max
+	}
+}
+
+// withSseEventMapping sets custom event names recognised in addition to the
+// defaults.
+func withSseEventMapping(mapping map[string]ChangeType) SseOption {
+	return func(c *SseClient) {
+		c.eventTypes = mapping
+	}
+}
+
+// WithSseRecorder copies the raw bytes of every SSE stream the client reads
+// to w, so that a live stream can be saved and replayed later with
+// ReplaySseClient.
+func WithSseRecorder(w io.Writer) SseOption {
+	return func(c *SseClient) {
+		c.recorder = w
+	}
+}
+
+// WithSseHTTPClient makes the client connect with a copy of client, so that
+// its transport (proxy, TLS configuration) applies to the event stream. The
+// copy has no Timeout, which would cut the stream off. A nil client keeps the
+// default.
+func WithSseHTTPClient(client *http.Client) SseOption {
+	return func(c *SseClient) {
+		if client == nil {
+			return
+		}
+		streaming := *client
+		streaming.Timeout = 0
+		c.httpClient = &streaming
+	}
 }
 
 // NewSseClient creates a new SSE client.
@@ -41,9 +143,10 @@ func NewSseClient(
 	telemetryHeaders map[string]string,
 	onFlagChange FlagChangeHandler,
 	onStatusChange ConnectionStatusHandler,
+	opts ...SseOption,
 ) *SseClient {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &SseClient{
+	c := &SseClient{
 		baseURL:          strings.TrimSuffix(baseURL, "/"),
 		apiKey:           apiKey,
 		telemetryHeaders: telemetryHeaders,
@@ -52,26 +155,57 @@ func NewSseClient(
 		httpClient: &http.Client{
 			Timeout: 0, // No timeout for SSE
 		},
+		rng:        newTimeSeededRand(),
+		clock:      realClock{},
+		logger:     stdLogger{},
 		status:     StatusDisconnected,
-		retryDelay: minRetryDelay,
+		retryDelay: defaultMinRetryDelay,
 		ctx:        ctx,
 		cancel:     cancel,
+
+		minRetryDelay: defaultMinRetryDelay,
+		maxRetryDelay: defaultMaxRetryDelay,
+
+		path:        defaultSsePath,
+		userAgent:   defaultUserAgent,
+		readTimeout: defaultSseReadTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.retryDelay = c.minRetryDelay
+
+	if c.connectionID == "" {
+		c.connectionID = newUUID(c.rng)
 	}
+
+	return c
 }
 
-// Connect starts the SSE connection in a background goroutine.
+// Connect starts the SSE connection in a background goroutine. Calling it
+// again while the client is connecting or connected does nothing.
 func (c *SseClient) Connect() {
 	c.mu.Lock()
-	if c.closed {
+	if c.closed || c.running {
 		c.mu.Unlock()
 		return
 	}
+	c.running = true
+	c.loops.Add(1)
 	c.mu.Unlock()
 
 	go c.connectLoop()
 }
 
 func (c *SseClient) connectLoop() {
+	defer c.loops.Done()
+	defer func() {
+		c.mu.Lock()
+		c.running = false
+		c.mu.Unlock()
+	}()
+
 	for {
 		c.mu.RLock()
 		closed := c.closed
@@ -87,8 +221,15 @@ func (c *SseClient) connectLoop() {
 			closed := c.closed
 			c.mu.RUnlock()
 
+			if !closed && errors.Is(err, ErrInvalidAPIKey) {
+				c.logger.Errorw("SSE connection rejected the API key, not reconnecting", errorFields(err)...)
+				c.reportError(err)
+				c.updateStatus(StatusUnauthorized)
+				return
+			}
 			if !closed {
-				log.Printf("[Flipswitch] WARN: SSE connection error: %v", err)
+				c.logger.Warnw("SSE connection error", errorFields(err)...)
+				c.reportError(err)
 				c.updateStatus(StatusError)
 				c.scheduleReconnect()
 			}
@@ -99,16 +240,37 @@ func (c *SseClient) connectLoop() {
 func (c *SseClient) connect() error {
 	c.updateStatus(StatusConnecting)
 
-	url := c.baseURL + "/api/v1/flags/events"
+	url := c.baseURL + c.path
+
+	// The watchdog cancels the connection once nothing has been received
+	// for the read timeout, from the attempt itself until the stream ends
+	ctx := c.ctx
+	var watchdog *sseWatchdog
+	if c.readTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(c.ctx)
+		defer cancel()
+		watchdog = startWatchdog(c.clock, c.readTimeout, cancel)
+		defer watchdog.stop()
+	}
 
-	req, err := http.NewRequestWithContext(c.ctx, "GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
 	}
 
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
 	req.Header.Set("X-API-Key", c.apiKey)
+	setProtocolHeader(req)
+	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Accept", "text/event-stream")
 	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set(connectionIDHeader, c.connectionID)
+	if lastEventID := c.getLastEventID(); lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
 
 	// Set telemetry headers
 	for key, value := range c.telemetryHeaders {
@@ -117,77 +279,217 @@ func (c *SseClient) connect() error {
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		if watchdog.stop() {
+			return ErrSseReadTimeout
+		}
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return &sseError{statusCode: resp.StatusCode}
+		return &SseStatusError{StatusCode: resp.StatusCode}
+	}
+	// A proxy can answer 200 with something else entirely, such as a login
+	// page, which would otherwise be read as a stream without events
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != "text/event-stream" {
+		return &SseContentTypeError{ContentType: contentType}
 	}
 
-	log.Println("[Flipswitch] SSE connection established")
+	c.logger.Debugw("SSE connection established")
 	c.updateStatus(StatusConnected)
 
-	c.mu.Lock()
-	c.retryDelay = minRetryDelay
-	c.mu.Unlock()
+	var body io.Reader = resp.Body
+	if watchdog != nil {
+		body = &watchedReader{r: body, watchdog: watchdog}
+	}
+	if c.recorder != nil {
+		body = io.TeeReader(body, c.recorder)
+	}
 
-	reader := bufio.NewReader(resp.Body)
-	var eventType, eventData string
+	err = readEventStream(c.ctx, bufio.NewReader(body), c.handleEvent, c.setLastEventID)
+	if err == nil {
+		return nil
+	}
+	if watchdog.stop() {
+		// Reported by connectLoop, which also reconnects
+		return ErrSseReadTimeout
+	}
+
+	c.mu.RLock()
+	closed := c.closed
+	c.mu.RUnlock()
+
+	if !closed {
+		if errors.Is(err, io.EOF) {
+			// Clean close by the server (e.g. connection rotation),
+			// so reconnect quickly
+			c.logger.Debugw("SSE connection closed")
+			c.mu.Lock()
+			c.retryDelay = c.minRetryDelay
+			c.mu.Unlock()
+		} else {
+			// Read failure - keep escalating the backoff
+			c.logger.Warnw("SSE connection read error", "error", err)
+			c.reportError(err)
+		}
+		c.updateStatus(StatusDisconnected)
+		c.scheduleReconnect()
+	}
+	return nil
+}
+
+// readEvents parses SSE frames from reader and calls dispatch for each
+// complete event. It returns nil if ctx is cancelled, or the read error
+// (io.EOF for a clean end of stream) that ended the stream.
+func readEvents(ctx context.Context, reader *bufio.Reader, dispatch func(eventType, data string)) error {
+	return readEventStream(ctx, reader, dispatch, nil)
+}
+
+// readEventStream is readEvents that also tracks event ids. Once the stream
+// has carried an "id:" field, setID is called with the last event id at the
+// end of every frame, before the frame's event is dispatched. As in the
+// EventSource spec, the id carries over to later frames until replaced, an
+// empty id clears it and an id containing NUL is ignored. setID may be nil.
+//
+// A frame may carry several "data:" lines, which are joined with "\n" into
+// one payload. Lines starting with ":" are comments and are ignored.
+func readEventStream(ctx context.Context, reader *bufio.Reader, dispatch func(eventType, data string), setID func(id string)) error {
+	lines := &lineReader{reader: reader}
+	var eventType, eventID string
+	var dataLines []string
+	seenID := false
 
 	for {
 		select {
-		case <-c.ctx.Done():
+		case <-ctx.Done():
 			return nil
 		default:
 		}
 
-		line, err := reader.ReadString('\n')
+		line, err := lines.readLine()
 		if err != nil {
-			c.mu.RLock()
-			closed := c.closed
-			c.mu.RUnlock()
-
-			if !closed {
-				log.Println("[Flipswitch] SSE connection closed")
-				c.updateStatus(StatusDisconnected)
-				c.scheduleReconnect()
-			}
-			return nil
+			return err
 		}
 
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
 		line = strings.TrimSpace(line)
 
 		if strings.HasPrefix(line, "event:") {
 			eventType = strings.TrimSpace(line[6:])
 		} else if strings.HasPrefix(line, "data:") {
-			eventData = strings.TrimSpace(line[5:])
-		} else if line == "" && eventData != "" {
-			c.handleEvent(eventType, eventData)
+			dataLines = append(dataLines, strings.TrimSpace(line[5:]))
+		} else if strings.HasPrefix(line, "id:") {
+			if id := strings.TrimSpace(line[3:]); !strings.ContainsRune(id, 0) {
+				eventID = id
+				seenID = true
+			}
+		} else if line == "" {
+			if seenID && setID != nil {
+				setID(eventID)
+			}
+			if data := strings.Join(dataLines, "\n"); data != "" {
+				dispatch(eventType, data)
+			}
 			eventType = ""
-			eventData = ""
+			dataLines = dataLines[:0]
 		}
 	}
 }
 
-type sseError struct {
-	statusCode int
+// lineReader splits an SSE stream into lines terminated by "\n", "\r\n" or
+// a bare "\r", as the SSE specification allows.
+type lineReader struct {
+	reader *bufio.Reader
+	// skipLF is set after a "\r" so that a "\n" immediately following it
+	// is treated as part of the same line ending. Tracking this instead of
+	// peeking avoids blocking on a live stream after a bare "\r".
+	skipLF bool
+	line   []byte
 }
 
-func (e *sseError) Error() string {
-	return "SSE connection failed with status: " + intToString(e.statusCode)
+// readLine returns the next line without its terminator.
+func (l *lineReader) readLine() (string, error) {
+	l.line = l.line[:0]
+	for {
+		b, err := l.reader.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		if l.skipLF {
+			l.skipLF = false
+			if b == '\n' {
+				continue
+			}
+		}
+		switch b {
+		case '\n':
+			return string(l.line), nil
+		case '\r':
+			l.skipLF = true
+			return string(l.line), nil
+		}
+		l.line = append(l.line, b)
+	}
+}
+
+// SseStatusError is reported when the SSE endpoint answers a connection
+// attempt with a status other than 200 OK. A 401 or 403 means the API key
+// was rejected: the error then matches ErrInvalidAPIKey with errors.Is, and
+// the client stops reconnecting. Other statuses are retried.
+type SseStatusError struct {
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+}
+
+func (e *SseStatusError) Error() string {
+	return "SSE connection failed with status: " + intToString(e.StatusCode)
+}
+
+// Unwrap returns ErrInvalidAPIKey for a 401 or 403 response.
+func (e *SseStatusError) Unwrap() error {
+	if e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden {
+		return ErrInvalidAPIKey
+	}
+	return nil
+}
+
+// SseContentTypeError is reported when a connection attempt is answered with
+// a response that is not an event stream, such as a proxy's login page. It is
+// retried.
+type SseContentTypeError struct {
+	// ContentType is the Content-Type of the response.
+	ContentType string
+}
+
+func (e *SseContentTypeError) Error() string {
+	return fmt.Sprintf("SSE connection returned content type %q, want text/event-stream", e.ContentType)
+}
+
+// changeType classifies eventType using the custom mapping, falling back to
+// the default event names.
+func (c *SseClient) changeType(eventType string) ChangeType {
+	if changeType, ok := c.eventTypes[eventType]; ok {
+		return changeType
+	}
+	return ChangeType(eventType)
 }
 
 func (c *SseClient) handleEvent(eventType, data string) {
-	if eventType == "heartbeat" {
+	changeType := c.changeType(eventType)
+
+	if changeType == ChangeHeartbeat {
+		c.logger.Debugw("SSE heartbeat received")
 		return
 	}
 
-	if eventType == "flag-updated" {
+	if changeType == ChangeFlagUpdated {
 		// Single flag was modified
 		var parsed FlagUpdatedEvent
 		if err := json.Unmarshal([]byte(data), &parsed); err != nil {
-			log.Printf("[Flipswitch] Failed to parse flag-updated event: %v", err)
+			c.logger.Errorw("Failed to parse SSE event", "eventType", eventType, "error", err)
 			return
 		}
 
@@ -199,34 +501,35 @@ func (c *SseClient) handleEvent(eventType, data string) {
 		if c.onFlagChange != nil {
 			c.onFlagChange(event)
 		}
-	} else if eventType == "config-updated" {
+	} else if changeType == ChangeConfigUpdated {
 		// Configuration changed, always refresh all flags
 		var parsed ConfigUpdatedEvent
 		if err := json.Unmarshal([]byte(data), &parsed); err != nil {
-			log.Printf("[Flipswitch] Failed to parse config-updated event: %v", err)
+			c.logger.Errorw("Failed to parse SSE event", "eventType", eventType, "error", err)
 			return
 		}
 
 		event := FlagChangeEvent{
-			FlagKey:   "", // Empty indicates all flags should be refreshed
-			Timestamp: parsed.Timestamp,
+			FlagKey:      "", // Empty indicates all flags should be refreshed
+			Timestamp:    parsed.Timestamp,
+			AffectedKeys: parsed.AffectedKeys,
 		}
 
 		if c.onFlagChange != nil {
 			c.onFlagChange(event)
 		}
-	} else if eventType == "api-key-rotated" {
+	} else if changeType == ChangeApiKeyRotated {
 		// API key was rotated or rotation was aborted
 		var parsed ApiKeyRotatedEvent
 		if err := json.Unmarshal([]byte(data), &parsed); err != nil {
-			log.Printf("[Flipswitch] Failed to parse api-key-rotated event: %v", err)
+			c.logger.Errorw("Failed to parse SSE event", "eventType", eventType, "error", err)
 			return
 		}
 
 		if parsed.ValidUntil == "" {
-			log.Println("[Flipswitch] API key rotation was aborted")
+			c.logger.Infow("API key rotation was aborted")
 		} else {
-			log.Printf("[Flipswitch] WARNING: API key was rotated. Current key valid until: %s", parsed.ValidUntil)
+			c.logger.Warnw("API key was rotated", "validUntil", parsed.ValidUntil)
 		}
 		// No cache invalidation - this is just informational
 	}
@@ -242,27 +545,50 @@ func (c *SseClient) scheduleReconnect() {
 		return
 	}
 
-	log.Printf("[Flipswitch] Scheduling SSE reconnect in %v", delay)
+	c.mu.Lock()
+	c.reconnectAttempts++
+	c.mu.Unlock()
+
+	delay = c.jitter(delay)
+	c.logger.Debugw("Scheduling SSE reconnect", "delay", delay)
 
 	select {
-	case <-time.After(delay):
+	case <-c.clock.After(delay):
 	case <-c.ctx.Done():
 		return
 	}
 
+	c.backoff()
+}
+
+// backoff doubles the reconnect delay, up to the maximum.
+func (c *SseClient) backoff() {
 	c.mu.Lock()
-	if c.retryDelay < maxRetryDelay {
+	defer c.mu.Unlock()
+	if c.retryDelay < c.maxRetryDelay {
 		c.retryDelay = c.retryDelay * 2
-		if c.retryDelay > maxRetryDelay {
-			c.retryDelay = maxRetryDelay
+		if c.retryDelay > c.maxRetryDelay {
+			c.retryDelay = c.maxRetryDelay
 		}
 	}
-	c.mu.Unlock()
+}
+
+// jitter spreads delay over [delay/2, delay] so that clients disconnected
+// at the same moment do not all reconnect in lockstep.
+func (c *SseClient) jitter(delay time.Duration) time.Duration {
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(c.rng.Int63n(int64(half)+1))
 }
 
 func (c *SseClient) updateStatus(status ConnectionStatus) {
 	c.mu.Lock()
 	c.status = status
+	if status == StatusConnected {
+		c.reconnectAttempts = 0
+	}
 	c.mu.Unlock()
 
 	if c.onStatusChange != nil {
@@ -270,6 +596,13 @@ func (c *SseClient) updateStatus(status ConnectionStatus) {
 	}
 }
 
+// reportError passes err to the error handler, if one is set.
+func (c *SseClient) reportError(err error) {
+	if c.onError != nil {
+		c.onError(err)
+	}
+}
+
 // GetStatus returns the current connection status.
 func (c *SseClient) GetStatus() ConnectionStatus {
 	c.mu.RLock()
@@ -277,12 +610,64 @@ func (c *SseClient) GetStatus() ConnectionStatus {
 	return c.status
 }
 
+// RetryDelay returns the base delay before the next reconnect attempt. It
+// doubles with each failed attempt up to the maximum and is reset to the
+// minimum when the server closes the stream cleanly. The actual wait is
+// jittered to between half and all of it.
+func (c *SseClient) RetryDelay() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.retryDelay
+}
+
+// ReconnectAttempts returns the number of reconnects scheduled since the last
+// successful connection.
+func (c *SseClient) ReconnectAttempts() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.reconnectAttempts
+}
+
+func (c *SseClient) getLastEventID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastEventID
+}
+
+func (c *SseClient) setLastEventID(id string) {
+	c.mu.Lock()
+	c.lastEventID = id
+	c.mu.Unlock()
+}
+
 // Close closes the SSE connection and stops reconnection attempts.
 func (c *SseClient) Close() {
 	c.mu.Lock()
 	c.closed = true
+	c.lastEventID = ""
 	c.mu.Unlock()
 
 	c.cancel()
 	c.updateStatus(StatusDisconnected)
 }
+
+// CloseContext closes the client like Close, then waits until its
+// connection goroutine has exited, including any reconnect it was waiting to
+// make. It returns ctx.Err() if ctx is done first. Flag change and status
+// handlers run on that goroutine, so CloseContext must not be called from
+// one of them without a deadline.
+func (c *SseClient) CloseContext(ctx context.Context) error {
+	c.Close()
+
+	done := make(chan struct{})
+	go func() {
+		c.loops.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
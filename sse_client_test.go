@@ -1,10 +1,17 @@
 package flipswitch
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -43,6 +50,28 @@ func TestSseClient_Close(t *testing.T) {
 	}
 }
 
+func TestSseClient_WithSseHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	transport := &http.Transport{}
+	custom := &http.Client{Transport: transport, Timeout: 5 * time.Second}
+	client := NewSseClient("http://localhost", "test-key", nil, nil, nil, WithSseHTTPClient(custom))
+	defer client.Close()
+
+	if client.httpClient == custom {
+		t.Fatal("expected the client to be copied")
+	}
+	if client.httpClient.Transport != transport {
+		t.Error("expected the custom transport to be kept")
+	}
+	if client.httpClient.Timeout != 0 {
+		t.Errorf("expected no timeout on the streaming client, got %v", client.httpClient.Timeout)
+	}
+	if custom.Timeout != 5*time.Second {
+		t.Errorf("expected the custom client to be left unchanged, got timeout %v", custom.Timeout)
+	}
+}
+
 func TestSseClient_ClosePreventReconnect(t *testing.T) {
 	t.Parallel()
 
@@ -195,38 +224,33 @@ func TestSseClient_StatusChangeCallback(t *testing.T) {
 func TestSseClient_ExponentialBackoff(t *testing.T) {
 	t.Parallel()
 
-	client := NewSseClient("http://localhost", "test-key", nil, nil, nil)
+	client := NewSseClient("http://localhost", "test-key", nil, nil, nil,
+		withSseRetryBounds(100*time.Millisecond, time.Second))
 	defer client.Close()
 
-	// Initial delay should be minRetryDelay (1s).
+	// Initial delay should be the injected minimum.
 	client.mu.RLock()
-	if client.retryDelay != minRetryDelay {
-		t.Errorf("expected initial retryDelay %v, got %v", minRetryDelay, client.retryDelay)
+	if client.retryDelay != 100*time.Millisecond {
+		t.Errorf("expected initial retryDelay %v, got %v", 100*time.Millisecond, client.retryDelay)
 	}
 	client.mu.RUnlock()
 
-	// Simulate the backoff doubling that scheduleReconnect performs,
-	// but without actually waiting. We directly manipulate the delay
-	// the same way scheduleReconnect does after the wait.
+	// Apply the backoff that scheduleReconnect performs after each wait,
+	// without actually waiting.
 	expectedDelays := []time.Duration{
-		2 * time.Second,
-		4 * time.Second,
-		8 * time.Second,
-		16 * time.Second,
-		30 * time.Second, // capped at maxRetryDelay
-		30 * time.Second, // stays at max
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second, // capped at the injected maximum
+		time.Second, // stays at max
 	}
 
 	for i, want := range expectedDelays {
-		client.mu.Lock()
-		if client.retryDelay < maxRetryDelay {
-			client.retryDelay = client.retryDelay * 2
-			if client.retryDelay > maxRetryDelay {
-				client.retryDelay = maxRetryDelay
-			}
-		}
+		client.backoff()
+
+		client.mu.RLock()
 		got := client.retryDelay
-		client.mu.Unlock()
+		client.mu.RUnlock()
 
 		if got != want {
 			t.Errorf("step %d: expected retryDelay %v, got %v", i, want, got)
@@ -234,6 +258,18 @@ func TestSseClient_ExponentialBackoff(t *testing.T) {
 	}
 }
 
+func TestSseClient_DefaultRetryBounds(t *testing.T) {
+	t.Parallel()
+
+	client := NewSseClient("http://localhost", "test-key", nil, nil, nil)
+	defer client.Close()
+
+	if client.minRetryDelay != defaultMinRetryDelay || client.maxRetryDelay != defaultMaxRetryDelay {
+		t.Errorf("expected default bounds %v..%v, got %v..%v",
+			defaultMinRetryDelay, defaultMaxRetryDelay, client.minRetryDelay, client.maxRetryDelay)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Integration Tests
 // ---------------------------------------------------------------------------
@@ -295,6 +331,81 @@ func TestSseClient_Integration_Connection(t *testing.T) {
 	}
 }
 
+func TestSseClient_Integration_ConnectIsIdempotent(t *testing.T) {
+	t.Parallel()
+
+	var connections int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&connections, 1)
+		serveSseKeepAlive(w, r)
+	}))
+	defer server.Close()
+
+	connected := make(chan struct{}, 10)
+	client := NewSseClient(server.URL, "test-key", nil, nil,
+		func(status ConnectionStatus) {
+			if status == StatusConnected {
+				connected <- struct{}{}
+			}
+		})
+	defer client.Close()
+
+	client.Connect()
+	client.Connect()
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for connected status")
+	}
+	client.Connect()
+
+	// Give a duplicate loop time to open a second connection
+	time.Sleep(100 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&connections); got != 1 {
+		t.Errorf("expected 1 connection, got %d", got)
+	}
+	if len(connected) != 0 {
+		t.Errorf("expected a single connected status, got %d more", len(connected))
+	}
+}
+
+func TestSseClient_Integration_SendsLastEventIDOnReconnect(t *testing.T) {
+	t.Parallel()
+
+	var connections int32
+	lastEventIDs := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastEventIDs <- r.Header.Get("Last-Event-ID")
+		if atomic.AddInt32(&connections, 1) > 1 {
+			serveSseKeepAlive(w, r)
+			return
+		}
+		// The first stream carries an identified event and then ends,
+		// so the client reconnects
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "id: 42\n"+sseFrame("flag-updated", `{"flagKey":"my-flag"}`))
+	}))
+	defer server.Close()
+
+	client := NewSseClient(server.URL, "test-key", nil, func(FlagChangeEvent) {}, nil)
+	defer client.Close()
+	client.Connect()
+
+	for i, want := range []string{"", "42"} {
+		select {
+		case got := <-lastEventIDs:
+			if got != want {
+				t.Errorf("connection %d: expected Last-Event-ID %q, got %q", i+1, want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for connection %d", i+1)
+		}
+	}
+}
+
 func TestSseClient_Integration_FlagUpdatedEvent(t *testing.T) {
 	t.Parallel()
 
@@ -544,3 +655,582 @@ func TestSseClient_Integration_ErrorOnNon200(t *testing.T) {
 		t.Errorf("expected status %q, got %q", StatusError, got)
 	}
 }
+
+func TestSseClient_Integration_StopsOnRejectedAPIKey(t *testing.T) {
+	t.Parallel()
+
+	for _, statusCode := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		t.Run(intToString(statusCode), func(t *testing.T) {
+			t.Parallel()
+
+			var connections int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&connections, 1)
+				w.WriteHeader(statusCode)
+			}))
+			defer server.Close()
+
+			statusCh := make(chan ConnectionStatus, 10)
+			errs := make(chan error, 10)
+			client := NewSseClient(server.URL, "test-key", nil, nil,
+				func(status ConnectionStatus) {
+					statusCh <- status
+				},
+				withSseRetryBounds(10*time.Millisecond, 10*time.Millisecond),
+				withSseErrorHandler(func(err error) {
+					errs <- err
+				}),
+			)
+			defer client.Close()
+
+			client.Connect()
+
+			deadline := time.After(5 * time.Second)
+			for done := false; !done; {
+				select {
+				case s := <-statusCh:
+					if s == StatusError {
+						t.Fatal("expected no retryable error status")
+					}
+					done = s == StatusUnauthorized
+				case <-deadline:
+					t.Fatal("timed out waiting for the unauthorized status")
+				}
+			}
+
+			err := <-errs
+			var statusErr *SseStatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != statusCode {
+				t.Errorf("expected an *SseStatusError with status %d, got %v", statusCode, err)
+			}
+			if !errors.Is(err, ErrInvalidAPIKey) {
+				t.Errorf("expected the error to match ErrInvalidAPIKey, got %v", err)
+			}
+
+			// Well past the retry delay, nothing more happens
+			time.Sleep(100 * time.Millisecond)
+			if n := atomic.LoadInt32(&connections); n != 1 {
+				t.Errorf("expected a single connection attempt, got %d", n)
+			}
+			if got := client.GetStatus(); got != StatusUnauthorized {
+				t.Errorf("expected status %q, got %q", StatusUnauthorized, got)
+			}
+		})
+	}
+}
+
+func TestSseStatusError_OnlyAuthFailuresMatchInvalidAPIKey(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		statusCode int
+		want       bool
+	}{
+		{http.StatusUnauthorized, true},
+		{http.StatusForbidden, true},
+		{http.StatusTooManyRequests, false},
+		{http.StatusServiceUnavailable, false},
+	} {
+		err := error(&SseStatusError{StatusCode: tt.statusCode})
+		if got := errors.Is(err, ErrInvalidAPIKey); got != tt.want {
+			t.Errorf("status %d: expected errors.Is(err, ErrInvalidAPIKey) = %v, got %v", tt.statusCode, tt.want, got)
+		}
+	}
+}
+
+func TestSseClient_HandleEvent_ConfigUpdatedWithAffectedKeys(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan FlagChangeEvent, 1)
+	client := NewSseClient("http://localhost", "test-key", nil,
+		func(event FlagChangeEvent) {
+			received <- event
+		}, nil)
+	defer client.Close()
+
+	client.handleEvent("config-updated", `{"timestamp":"2024-06-15T12:00:00Z","affectedKeys":["flag-a","flag-b"]}`)
+
+	select {
+	case event := <-received:
+		if event.FlagKey != "" {
+			t.Errorf("expected empty FlagKey for config-updated, got %q", event.FlagKey)
+		}
+		if len(event.AffectedKeys) != 2 || event.AffectedKeys[0] != "flag-a" || event.AffectedKeys[1] != "flag-b" {
+			t.Errorf("expected AffectedKeys [flag-a flag-b], got %v", event.AffectedKeys)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for config-updated event")
+	}
+}
+
+func TestSseClient_HandleEvent_ConfigUpdatedWithoutAffectedKeys(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan FlagChangeEvent, 1)
+	client := NewSseClient("http://localhost", "test-key", nil,
+		func(event FlagChangeEvent) {
+			received <- event
+		}, nil)
+	defer client.Close()
+
+	client.handleEvent("config-updated", `{"timestamp":"2024-06-15T12:00:00Z"}`)
+
+	select {
+	case event := <-received:
+		if len(event.AffectedKeys) != 0 {
+			t.Errorf("expected no AffectedKeys, got %v", event.AffectedKeys)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for config-updated event")
+	}
+}
+
+// countingSseServer starts a server whose SSE handler reports each connection
+// number on connCh and then delegates to handle.
+func countingSseServer(t *testing.T, connCh chan<- int, handle func(connNum int, w http.ResponseWriter, r *http.Request)) *httptest.Server {
+	t.Helper()
+
+	var (
+		mu          sync.Mutex
+		connections int
+	)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		connections++
+		connNum := connections
+		mu.Unlock()
+		connCh <- connNum
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		handle(connNum, w, r)
+	}))
+}
+
+func TestSseClient_Integration_CleanEOFResetsBackoff(t *testing.T) {
+	t.Parallel()
+
+	connCh := make(chan int, 10)
+	server := countingSseServer(t, connCh, func(connNum int, w http.ResponseWriter, r *http.Request) {
+		if connNum == 1 {
+			// Returning ends the chunked stream cleanly, so the client sees io.EOF.
+			return
+		}
+		<-r.Context().Done()
+	})
+	defer server.Close()
+
+	const minDelay = 100 * time.Millisecond
+	clock := newFakeClock()
+	client := NewSseClient(server.URL, "test-key", nil, nil, nil,
+		withSseRetryBounds(minDelay, time.Minute), withSseReadTimeout(0), withSseClock(clock))
+	// Pretend earlier failures escalated the backoff well above the minimum.
+	client.mu.Lock()
+	client.retryDelay = 20 * time.Second
+	client.mu.Unlock()
+	defer client.Close()
+
+	client.Connect()
+	<-connCh
+
+	// The reconnect waits for the jittered minimum, not the escalated delay
+	delay := clock.nextScheduled(t)
+	if delay < minDelay/2 || delay > minDelay {
+		t.Fatalf("expected a reconnect delay within [%v, %v] after clean EOF, got %v", minDelay/2, minDelay, delay)
+	}
+	select {
+	case <-connCh:
+		t.Fatal("expected no reconnect before the delay has passed")
+	default:
+	}
+	clock.Advance(delay)
+
+	select {
+	case <-connCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a reconnect once the delay has passed")
+	}
+
+	client.mu.RLock()
+	retryDelay := client.retryDelay
+	client.mu.RUnlock()
+
+	// Reset to the minimum, then doubled once after the reconnect wait.
+	if retryDelay != 2*minDelay {
+		t.Errorf("expected retryDelay %v after clean EOF, got %v", 2*minDelay, retryDelay)
+	}
+}
+
+func TestSseClient_Integration_ReconnectAttemptsResetOnConnect(t *testing.T) {
+	t.Parallel()
+
+	var fail int32 = 1
+	connCh := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connCh <- struct{}{}
+		if atomic.LoadInt32(&fail) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	clock := newFakeClock()
+	connected := make(chan struct{}, 1)
+	client := NewSseClient(server.URL, "test-key", nil, nil,
+		func(status ConnectionStatus) {
+			if status == StatusConnected {
+				connected <- struct{}{}
+			}
+		},
+		withSseRetryBounds(100*time.Millisecond, time.Second), withSseReadTimeout(0), withSseClock(clock))
+	defer client.Close()
+
+	if got := client.ReconnectAttempts(); got != 0 {
+		t.Fatalf("expected no reconnect attempts before connecting, got %d", got)
+	}
+
+	client.Connect()
+	for attempt, wantDelay := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		<-connCh
+		delay := clock.nextScheduled(t)
+		if got := client.ReconnectAttempts(); got != attempt+1 {
+			t.Errorf("attempt %d: expected %d reconnect attempts, got %d", attempt+1, attempt+1, got)
+		}
+		if got := client.RetryDelay(); got != wantDelay {
+			t.Errorf("attempt %d: expected retry delay %v, got %v", attempt+1, wantDelay, got)
+		}
+		if attempt == 2 {
+			atomic.StoreInt32(&fail, 0)
+		}
+		clock.Advance(delay)
+	}
+
+	<-connCh
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the connection")
+	}
+	if got := client.ReconnectAttempts(); got != 0 {
+		t.Errorf("expected reconnect attempts to reset on connect, got %d", got)
+	}
+	if got := client.RetryDelay(); got != 800*time.Millisecond {
+		t.Errorf("expected the retry delay to be kept, got %v", got)
+	}
+}
+
+func TestSseClient_Integration_ReadErrorEscalatesBackoff(t *testing.T) {
+	t.Parallel()
+
+	connCh := make(chan int, 10)
+	server := countingSseServer(t, connCh, func(connNum int, w http.ResponseWriter, r *http.Request) {
+		if connNum == 1 {
+			// Abort the connection mid-stream so the client sees a read error.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		<-r.Context().Done()
+	})
+	defer server.Close()
+
+	client := NewSseClient(server.URL, "test-key", nil, nil, nil)
+	client.mu.Lock()
+	client.retryDelay = 100 * time.Millisecond
+	client.mu.Unlock()
+	defer client.Close()
+
+	client.Connect()
+
+	<-connCh
+	select {
+	case <-connCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reconnect after read error")
+	}
+
+	client.mu.RLock()
+	delay := client.retryDelay
+	client.mu.RUnlock()
+
+	// The backoff keeps escalating instead of resetting to the minimum.
+	if delay != 200*time.Millisecond {
+		t.Errorf("expected retryDelay %v after read error, got %v", 200*time.Millisecond, delay)
+	}
+}
+
+func TestSseClient_Integration_WrongContentTypeIsConnectionError(t *testing.T) {
+	t.Parallel()
+
+	var connections int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&connections, 1)
+		// A proxy login page instead of the event stream
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "<html><body>Please sign in</body></html>")
+	}))
+	defer server.Close()
+
+	errs := make(chan error, 10)
+	var mu sync.Mutex
+	var statuses []ConnectionStatus
+	client := NewSseClient(server.URL, "test-key", nil, nil,
+		func(status ConnectionStatus) {
+			mu.Lock()
+			statuses = append(statuses, status)
+			mu.Unlock()
+		},
+		withSseRetryBounds(50*time.Millisecond, 100*time.Millisecond),
+		withSseErrorHandler(func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		}),
+	)
+	defer client.Close()
+
+	client.Connect()
+
+	select {
+	case err := <-errs:
+		var ctErr *SseContentTypeError
+		if !errors.As(err, &ctErr) || ctErr.ContentType != "text/html; charset=utf-8" {
+			t.Errorf("expected a content type error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the connection error")
+	}
+
+	// The client backs off and tries again instead of hanging on the page
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&connections) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for reconnect after a wrong content type")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, status := range statuses {
+		if status == StatusConnected {
+			t.Fatalf("expected the connection never to be reported connected, got %v", statuses)
+		}
+	}
+	if len(statuses) < 2 || statuses[1] != StatusError {
+		t.Errorf("expected connecting then error, got %v", statuses)
+	}
+}
+
+func TestSseClient_HandleEvent_CustomEventMapping(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan FlagChangeEvent, 10)
+	client := NewSseClient("http://localhost", "test-key", nil,
+		func(event FlagChangeEvent) {
+			received <- event
+		}, nil, withSseEventMapping(map[string]ChangeType{
+			"flag.updated":   ChangeFlagUpdated,
+			"config.updated": ChangeConfigUpdated,
+			"ping":           ChangeHeartbeat,
+		}))
+	defer client.Close()
+
+	client.handleEvent("ping", `{"flagKey":"ignored"}`)
+	client.handleEvent("flag.updated", `{"flagKey":"flag-a","timestamp":"2024-06-15T12:00:00Z"}`)
+	client.handleEvent("config.updated", `{"timestamp":"2024-06-15T12:00:01Z"}`)
+	// Unmapped default names are still recognised
+	client.handleEvent("flag-updated", `{"flagKey":"flag-b","timestamp":"2024-06-15T12:00:02Z"}`)
+
+	want := []string{"flag-a", "", "flag-b"}
+	for i, key := range want {
+		select {
+		case event := <-received:
+			if event.FlagKey != key {
+				t.Errorf("event %d: expected FlagKey %q, got %q", i, key, event.FlagKey)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for event %d", i)
+		}
+	}
+
+	select {
+	case event := <-received:
+		t.Errorf("unexpected extra event: %+v", event)
+	default:
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Line Ending Tests
+// ---------------------------------------------------------------------------
+
+type dispatchedEvent struct {
+	eventType string
+	data      string
+}
+
+func readAllEvents(t *testing.T, stream string) []dispatchedEvent {
+	t.Helper()
+	var events []dispatchedEvent
+	err := readEvents(context.Background(), bufio.NewReader(strings.NewReader(stream)), func(eventType, data string) {
+		events = append(events, dispatchedEvent{eventType, data})
+	})
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF at end of stream, got %v", err)
+	}
+	return events
+}
+
+func TestReadEvents_LineEndings(t *testing.T) {
+	t.Parallel()
+
+	want := []dispatchedEvent{
+		{"flag-updated", `{"flagKey":"flag-a"}`},
+		{"config-updated", `{"timestamp":"2024-06-15T12:00:00Z"}`},
+	}
+
+	tests := []struct {
+		name   string
+		stream string
+	}{
+		{"LF", "event: flag-updated\ndata: {\"flagKey\":\"flag-a\"}\n\nevent: config-updated\ndata: {\"timestamp\":\"2024-06-15T12:00:00Z\"}\n\n"},
+		{"CRLF", "event: flag-updated\r\ndata: {\"flagKey\":\"flag-a\"}\r\n\r\nevent: config-updated\r\ndata: {\"timestamp\":\"2024-06-15T12:00:00Z\"}\r\n\r\n"},
+		{"BareCR", "event: flag-updated\rdata: {\"flagKey\":\"flag-a\"}\r\revent: config-updated\rdata: {\"timestamp\":\"2024-06-15T12:00:00Z\"}\r\r"},
+		{"Mixed", "event: flag-updated\r\ndata: {\"flagKey\":\"flag-a\"}\r\revent: config-updated\ndata: {\"timestamp\":\"2024-06-15T12:00:00Z\"}\r\n\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := readAllEvents(t, tt.stream)
+			if len(got) != len(want) {
+				t.Fatalf("expected %d events, got %d: %+v", len(want), len(got), got)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("event %d: expected %+v, got %+v", i, want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadEvents_BareCRDispatchesWithoutWaitingForMoreData(t *testing.T) {
+	t.Parallel()
+
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	received := make(chan dispatchedEvent, 1)
+	go readEvents(context.Background(), bufio.NewReader(pr), func(eventType, data string) {
+		received <- dispatchedEvent{eventType, data}
+	})
+
+	// The stream stays open after the final "\r"
+	go pw.Write([]byte("event: flag-updated\rdata: {\"flagKey\":\"flag-a\"}\r\r"))
+
+	select {
+	case event := <-received:
+		if event.eventType != "flag-updated" {
+			t.Errorf("expected flag-updated, got %q", event.eventType)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out: event after bare CR was not dispatched")
+	}
+}
+
+func TestReadEventStream_TracksEventIDs(t *testing.T) {
+	t.Parallel()
+
+	stream := "data: {}\n\n" +
+		"id: 1\ndata: {}\n\n" +
+		"data: {}\n\n" +
+		"id: 2\n\n" +
+		"id: bad\x00\ndata: {}\n\n" +
+		"id:\ndata: {}\n\n"
+
+	var ids []string
+	err := readEventStream(context.Background(), bufio.NewReader(strings.NewReader(stream)),
+		func(eventType, data string) {}, func(id string) { ids = append(ids, id) })
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF at end of stream, got %v", err)
+	}
+
+	// No id before the first "id:" field; ids carry over between frames,
+	// an id-only frame still sets it, NUL is ignored and empty clears it
+	want := []string{"1", "1", "2", "2", ""}
+	if len(ids) != len(want) {
+		t.Fatalf("expected ids %q, got %q", want, ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("frame %d: expected id %q, got %q", i, want[i], ids[i])
+		}
+	}
+}
+
+func TestSseClient_CloseResetsLastEventID(t *testing.T) {
+	t.Parallel()
+
+	client := NewSseClient("http://localhost", "test-key", nil, nil, nil)
+	client.setLastEventID("42")
+	client.Close()
+
+	if got := client.getLastEventID(); got != "" {
+		t.Errorf("expected Close to reset the last event id, got %q", got)
+	}
+}
+
+func TestReadEvents_MultiLineDataAndComments(t *testing.T) {
+	t.Parallel()
+
+	stream := ": connected\n" +
+		"event: flag-updated\n" +
+		"data: {\"flagKey\":\n" +
+		": comment between data lines\n" +
+		"data: \"dark-mode\",\n" +
+		"data: \"timestamp\": \"2024-01-01T00:00:00Z\"}\n\n" +
+		": keep-alive\n\n" +
+		"event: heartbeat\n" +
+		"data: {}\n\n"
+
+	type event struct{ eventType, data string }
+	var events []event
+	err := readEvents(context.Background(), bufio.NewReader(strings.NewReader(stream)), func(eventType, data string) {
+		events = append(events, event{eventType, data})
+	})
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF at end of stream, got %v", err)
+	}
+
+	want := []event{
+		{"flag-updated", "{\"flagKey\":\n\"dark-mode\",\n\"timestamp\": \"2024-01-01T00:00:00Z\"}"},
+		{"heartbeat", "{}"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected events %q, got %q", want, events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event %d: expected %q, got %q", i, want[i], events[i])
+		}
+	}
+
+	var payload FlagChangeEvent
+	if err := json.Unmarshal([]byte(events[0].data), &payload); err != nil || payload.FlagKey != "dark-mode" {
+		t.Errorf("expected the joined payload to parse, got %+v, %v", payload, err)
+	}
+}
@@ -0,0 +1,53 @@
+package flipswitch
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ReplaySseClient replays a recorded SSE stream, dispatching its events
+// through the same parsing and event handling as a live SseClient. It is
+// intended for deterministic tests of flag-reactive code.
+type ReplaySseClient struct {
+	client     *SseClient
+	source     io.Reader
+	frameDelay time.Duration
+}
+
+// NewReplaySseClient creates a client that replays the SSE stream read from
+// source, such as one captured with WithSseRecorder. frameDelay is waited
+// after each dispatched event.
+func NewReplaySseClient(source io.Reader, frameDelay time.Duration, onFlagChange FlagChangeHandler) *ReplaySseClient {
+	return &ReplaySseClient{
+		client:     NewSseClient("", "", nil, onFlagChange, nil),
+		source:     source,
+		frameDelay: frameDelay,
+	}
+}
+
+// Replay dispatches every event in the recorded stream, in order, and
+// returns once the stream is exhausted. It returns ctx.Err() if ctx is
+// cancelled first, or the error that prevented reading the stream.
+func (r *ReplaySseClient) Replay(ctx context.Context) error {
+	defer r.client.Close()
+
+	err := readEvents(ctx, bufio.NewReader(r.source), func(eventType, data string) {
+		r.client.handleEvent(eventType, data)
+		if r.frameDelay > 0 {
+			select {
+			case <-time.After(r.frameDelay):
+			case <-ctx.Done():
+			}
+		}
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
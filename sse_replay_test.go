@@ -0,0 +1,157 @@
+package flipswitch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const recordedStream = "event: flag-updated\n" +
+	"data: {\"flagKey\":\"flag-a\",\"timestamp\":\"2024-06-15T12:00:00Z\"}\n\n" +
+	"event: heartbeat\n" +
+	"data: {}\n\n" +
+	"event: config-updated\n" +
+	"data: {\"timestamp\":\"2024-06-15T12:00:01Z\"}\n\n" +
+	"event: flag-updated\n" +
+	"data: {\"flagKey\":\"flag-b\",\"timestamp\":\"2024-06-15T12:00:02Z\"}\n\n"
+
+func TestReplaySseClient_DispatchesInOrder(t *testing.T) {
+	t.Parallel()
+
+	var events []FlagChangeEvent
+	client := NewReplaySseClient(strings.NewReader(recordedStream), 0,
+		func(event FlagChangeEvent) {
+			events = append(events, event)
+		})
+
+	if err := client.Replay(context.Background()); err != nil {
+		t.Fatalf("unexpected replay error: %v", err)
+	}
+
+	want := []FlagChangeEvent{
+		{FlagKey: "flag-a", Timestamp: "2024-06-15T12:00:00Z"},
+		{FlagKey: "", Timestamp: "2024-06-15T12:00:01Z"},
+		{FlagKey: "flag-b", Timestamp: "2024-06-15T12:00:02Z"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %d: %+v", len(want), len(events), events)
+	}
+	for i := range want {
+		if events[i].FlagKey != want[i].FlagKey || events[i].Timestamp != want[i].Timestamp {
+			t.Errorf("event %d: expected %+v, got %+v", i, want[i], events[i])
+		}
+	}
+}
+
+func TestReplaySseClient_FrameDelay(t *testing.T) {
+	t.Parallel()
+
+	var times []time.Time
+	client := NewReplaySseClient(strings.NewReader(recordedStream), 30*time.Millisecond,
+		func(event FlagChangeEvent) {
+			times = append(times, time.Now())
+		})
+
+	if err := client.Replay(context.Background()); err != nil {
+		t.Fatalf("unexpected replay error: %v", err)
+	}
+
+	if len(times) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(times))
+	}
+	// The heartbeat between the first and second events also waits a frame.
+	if gap := times[1].Sub(times[0]); gap < 60*time.Millisecond {
+		t.Errorf("expected at least 60ms between first and second events, got %v", gap)
+	}
+	if gap := times[2].Sub(times[1]); gap < 30*time.Millisecond {
+		t.Errorf("expected at least 30ms between second and third events, got %v", gap)
+	}
+}
+
+func TestReplaySseClient_ContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	count := 0
+	client := NewReplaySseClient(strings.NewReader(recordedStream), time.Hour,
+		func(event FlagChangeEvent) {
+			count++
+			cancel()
+		})
+
+	err := client.Replay(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 event before cancellation, got %d", count)
+	}
+}
+
+func TestSseClient_Integration_RecorderCapturesStream(t *testing.T) {
+	t.Parallel()
+
+	frames := sseFrame("flag-updated", `{"flagKey":"flag-a","timestamp":"2024-06-15T12:00:00Z"}`) +
+		sseFrame("config-updated", `{"timestamp":"2024-06-15T12:00:01Z"}`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(frames))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var recorded bytes.Buffer
+	received := make(chan FlagChangeEvent, 10)
+	client := NewSseClient(server.URL, "test-key", nil,
+		func(event FlagChangeEvent) {
+			received <- event
+		}, nil, WithSseRecorder(writerFunc(func(p []byte) (int, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return recorded.Write(p)
+		})))
+	defer client.Close()
+
+	client.Connect()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for event %d", i+1)
+		}
+	}
+
+	mu.Lock()
+	got := recorded.String()
+	mu.Unlock()
+	if got != frames {
+		t.Errorf("expected recorded stream %q, got %q", frames, got)
+	}
+
+	// The recording replays to the same events.
+	var replayed []FlagChangeEvent
+	replay := NewReplaySseClient(strings.NewReader(got), 0, func(event FlagChangeEvent) {
+		replayed = append(replayed, event)
+	})
+	if err := replay.Replay(context.Background()); err != nil {
+		t.Fatalf("unexpected replay error: %v", err)
+	}
+	if len(replayed) != 2 || replayed[0].FlagKey != "flag-a" || replayed[1].FlagKey != "" {
+		t.Errorf("unexpected replayed events: %+v", replayed)
+	}
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
@@ -0,0 +1,113 @@
+package flipswitch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// defaultSseReadTimeout is how long an SSE connection may go without
+// receiving anything, heartbeats included, before it is considered dead.
+const defaultSseReadTimeout = 45 * time.Second
+
+// ErrSseReadTimeout is reported when an SSE connection receives nothing,
+// not even a heartbeat, within the read timeout set with WithSseReadTimeout.
+// The connection is then dropped and re-established.
+var ErrSseReadTimeout = errors.New("SSE connection received no data within the read timeout")
+
+// WithSseReadTimeout sets how long the SSE connection may go without
+// receiving any data, heartbeats included, before it is treated as dead
+// (default 45s). A silently dropped connection, for example behind a proxy
+// that keeps the TCP connection open, would otherwise look connected
+// forever. On a timeout the status changes to StatusError and the client
+// reconnects. Zero or less disables the check.
+func WithSseReadTimeout(timeout time.Duration) Option {
+	return func(p *FlipswitchProvider) {
+		p.sseReadTimeout = timeout
+	}
+}
+
+// withSseReadTimeout sets the SSE client's read timeout.
+func withSseReadTimeout(timeout time.Duration) SseOption {
+	return func(c *SseClient) {
+		c.readTimeout = timeout
+	}
+}
+
+// sseWatchdog cancels a connection on which nothing has been read for
+// timeout. Rather than resetting a timer on every read, reads only record
+// the time, and the timer re-arms itself for the remaining time when it
+// fires early.
+type sseWatchdog struct {
+	clock   clock
+	timeout time.Duration
+	cancel  context.CancelFunc
+
+	mu      sync.Mutex
+	last    time.Time
+	timer   timer
+	expired bool
+	stopped bool
+}
+
+// startWatchdog starts watching a connection, calling cancel once nothing
+// has been read for timeout.
+func startWatchdog(clk clock, timeout time.Duration, cancel context.CancelFunc) *sseWatchdog {
+	w := &sseWatchdog{clock: clk, timeout: timeout, cancel: cancel, last: clk.Now()}
+	w.mu.Lock()
+	w.timer = clk.AfterFunc(timeout, w.check)
+	w.mu.Unlock()
+	return w
+}
+
+func (w *sseWatchdog) check() {
+	w.mu.Lock()
+	if w.stopped {
+		w.mu.Unlock()
+		return
+	}
+	if idle := w.clock.Now().Sub(w.last); idle < w.timeout {
+		w.timer = w.clock.AfterFunc(w.timeout-idle, w.check)
+		w.mu.Unlock()
+		return
+	}
+	w.expired = true
+	w.mu.Unlock()
+	w.cancel()
+}
+
+// touch records that data was read.
+func (w *sseWatchdog) touch() {
+	w.mu.Lock()
+	w.last = w.clock.Now()
+	w.mu.Unlock()
+}
+
+// stop stops watching and reports whether the connection timed out. A nil
+// watchdog never times out.
+func (w *sseWatchdog) stop() bool {
+	if w == nil {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopped = true
+	w.timer.Stop()
+	return w.expired
+}
+
+// watchedReader records every successful read from r on a watchdog.
+type watchedReader struct {
+	r        io.Reader
+	watchdog *sseWatchdog
+}
+
+func (r *watchedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.watchdog.touch()
+	}
+	return n, err
+}
@@ -0,0 +1,106 @@
+package flipswitch
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSseWatchdog_ReadsPostponeExpiry(t *testing.T) {
+	clock := newFakeClock()
+	var cancelled int32
+	watchdog := startWatchdog(clock, time.Second, func() { atomic.StoreInt32(&cancelled, 1) })
+	if d := clock.nextScheduled(t); d != time.Second {
+		t.Fatalf("Expected the watchdog to wait 1s, got %v", d)
+	}
+
+	clock.Advance(600 * time.Millisecond)
+	watchdog.touch()
+	clock.Advance(400 * time.Millisecond)
+
+	// The timer fired early and re-armed for the rest of the timeout
+	if d := clock.nextScheduled(t); d != 600*time.Millisecond {
+		t.Fatalf("Expected the watchdog to re-arm for 600ms, got %v", d)
+	}
+	if atomic.LoadInt32(&cancelled) != 0 {
+		t.Fatal("Expected no cancel while data keeps arriving")
+	}
+
+	clock.Advance(600 * time.Millisecond)
+	if atomic.LoadInt32(&cancelled) != 1 {
+		t.Error("Expected the connection to be cancelled after a full idle timeout")
+	}
+	if !watchdog.stop() {
+		t.Error("Expected stop to report the timeout")
+	}
+}
+
+func TestSseWatchdog_StopPreventsExpiry(t *testing.T) {
+	clock := newFakeClock()
+	var cancelled int32
+	watchdog := startWatchdog(clock, time.Second, func() { atomic.StoreInt32(&cancelled, 1) })
+	clock.nextScheduled(t)
+
+	if watchdog.stop() {
+		t.Error("Expected no timeout before the deadline")
+	}
+	clock.Advance(2 * time.Second)
+	if atomic.LoadInt32(&cancelled) != 0 {
+		t.Error("Expected a stopped watchdog not to cancel")
+	}
+}
+
+func TestSseClient_Integration_SilentConnectionReconnects(t *testing.T) {
+	t.Parallel()
+
+	connCh := make(chan int, 10)
+	server := countingSseServer(t, connCh, func(connNum int, w http.ResponseWriter, r *http.Request) {
+		// Headers were flushed; say nothing more, like a dead proxy
+		<-r.Context().Done()
+	})
+	defer server.Close()
+
+	statuses := make(chan ConnectionStatus, 20)
+	errs := make(chan error, 10)
+	client := NewSseClient(server.URL, "test-key", nil, nil,
+		func(status ConnectionStatus) { statuses <- status },
+		withSseReadTimeout(100*time.Millisecond),
+		withSseRetryBounds(10*time.Millisecond, 20*time.Millisecond),
+		withSseErrorHandler(func(err error) { errs <- err }),
+	)
+	defer client.Close()
+
+	client.Connect()
+	<-connCh
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrSseReadTimeout) {
+			t.Errorf("Expected ErrSseReadTimeout, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the read timeout")
+	}
+
+	select {
+	case <-connCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a reconnect after the read timeout")
+	}
+
+	var seen []ConnectionStatus
+	for len(statuses) > 0 {
+		seen = append(seen, <-statuses)
+	}
+	want := []ConnectionStatus{StatusConnecting, StatusConnected, StatusError}
+	if len(seen) < len(want) {
+		t.Fatalf("Expected statuses to start with %v, got %v", want, seen)
+	}
+	for i, status := range want {
+		if seen[i] != status {
+			t.Fatalf("Expected statuses to start with %v, got %v", want, seen)
+		}
+	}
+}
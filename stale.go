@@ -0,0 +1,136 @@
+package flipswitch
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// StaleReason is the evaluation reason of a last-known value served by
+// WithServeStaleOnError after a live evaluation failed.
+const StaleReason openfeature.Reason = "STALE"
+
+// defaultLastKnownEntries caps the last-known values when WithCacheMaxEntries
+// does not.
+const defaultLastKnownEntries = 10000
+
+// lastKnownStore keeps the most recent successful evaluation of each flag
+// and context, without expiry. Once it holds maxEntries evaluations the least
+// recently used one is evicted. A nil store keeps nothing.
+type lastKnownStore struct {
+	entries map[string]map[string]lastKnownEntry // flag key -> context hash -> entry
+	// recency orders entries from most to least recently used
+	recency    *list.List
+	maxEntries int
+	mu         sync.Mutex
+}
+
+// lastKnownEntry is a last-known evaluation and its position in the
+// store's recency list.
+type lastKnownEntry struct {
+	eval FlagEvaluation
+	elem *list.Element
+}
+
+func newLastKnownStore() *lastKnownStore {
+	return &lastKnownStore{
+		entries:    make(map[string]map[string]lastKnownEntry),
+		recency:    list.New(),
+		maxEntries: defaultLastKnownEntries,
+	}
+}
+
+// WithServeStaleOnError makes the provider serve the last successfully
+// evaluated value of a flag for the same context when a live evaluation fails
+// with a network, server or parse error, instead of the caller's default. The
+// last-known value is used regardless of cache TTL or invalidation, and takes
+// precedence over WithBootstrap values. Only when a flag has never been
+// evaluated successfully for the context does the evaluation fall back as
+// usual. Last-known values are capped like the cache, by
+// WithCacheMaxEntries, or at 10000 flag and context pairs if that is not
+// set. Disabled by default.
+func WithServeStaleOnError(enabled bool) Option {
+	return func(p *FlipswitchProvider) {
+		if enabled {
+			p.lastKnown = newLastKnownStore()
+		} else {
+			p.lastKnown = nil
+		}
+	}
+}
+
+func (s *lastKnownStore) get(flagKey, ctxHash string) (FlagEvaluation, bool) {
+	if s == nil {
+		return FlagEvaluation{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[flagKey][ctxHash]
+	if !ok {
+		return FlagEvaluation{}, false
+	}
+	s.recency.MoveToFront(entry.elem)
+	return entry.eval, true
+}
+
+func (s *lastKnownStore) set(flagKey, ctxHash string, eval FlagEvaluation) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	byContext, ok := s.entries[flagKey]
+	if !ok {
+		byContext = make(map[string]lastKnownEntry)
+		s.entries[flagKey] = byContext
+	}
+	entry, ok := byContext[ctxHash]
+	if ok {
+		s.recency.MoveToFront(entry.elem)
+	} else {
+		entry.elem = s.recency.PushFront(cacheKey{flagKey: flagKey, ctxHash: ctxHash})
+	}
+	entry.eval = eval
+	byContext[ctxHash] = entry
+
+	for s.maxEntries > 0 && s.recency.Len() > s.maxEntries {
+		oldest := s.recency.Remove(s.recency.Back()).(cacheKey)
+		delete(s.entries[oldest.flagKey], oldest.ctxHash)
+		if len(s.entries[oldest.flagKey]) == 0 {
+			delete(s.entries, oldest.flagKey)
+		}
+	}
+}
+
+// staleFlag returns a copy of the last-known evaluation of flagKey for
+// evalCtx with reason STALE, or nil if there is none.
+func (p *FlipswitchProvider) staleFlag(flagKey string, evalCtx openfeature.FlattenedContext) *FlagEvaluation {
+	if p.lastKnown == nil {
+		return nil
+	}
+	eval, ok := p.lastKnown.get(flagKey, contextHash(evalCtx))
+	if !ok {
+		return nil
+	}
+	eval.Reason = string(StaleReason)
+	return &eval
+}
+
+// errorFallback returns the evaluation to serve in place of a failed typed
+// resolution, and its resolution detail: the last-known value if
+// WithServeStaleOnError is set, otherwise the bootstrapped value.
+func (p *FlipswitchProvider) errorFallback(flag string, evalCtx openfeature.FlattenedContext, detail openfeature.ProviderResolutionDetail) (FlagEvaluation, openfeature.ProviderResolutionDetail, bool) {
+	if detail.Reason == openfeature.ErrorReason {
+		switch detail.ResolutionDetail().ErrorCode {
+		case openfeature.GeneralCode, openfeature.ParseErrorCode:
+			if stale := p.staleFlag(flag, evalCtx); stale != nil {
+				return *stale, openfeature.ProviderResolutionDetail{Reason: StaleReason, Variant: stale.Variant}, true
+			}
+		}
+	}
+	if eval, ok := p.bootstrapFallback(flag, detail); ok {
+		return eval, cachedResolutionDetail(eval), true
+	}
+	return FlagEvaluation{}, openfeature.ProviderResolutionDetail{}, false
+}
@@ -0,0 +1,216 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// flakyFlag returns a flag response func serving value until failing is set,
+// then 503.
+func flakyFlag(flagKey string, value interface{}, failing *int32) func() (int, map[string]interface{}) {
+	return func() (int, map[string]interface{}) {
+		if atomic.LoadInt32(failing) == 1 {
+			return 503, map[string]interface{}{}
+		}
+		return 200, map[string]interface{}{"key": flagKey, "value": value, "variant": "on"}
+	}
+}
+
+func createStaleProvider(t *testing.T, server *httptest.Server, opts ...Option) *FlipswitchProvider {
+	t.Helper()
+	provider, err := NewProvider(
+		"test-api-key",
+		append([]Option{
+			WithBaseURL(server.URL),
+			WithRealtime(false),
+			WithServeStaleOnError(true),
+		}, opts...)...,
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	return provider
+}
+
+func TestServeStaleOnError_EvaluateFlagServesLastKnown(t *testing.T) {
+	var failing int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", flakyFlag("my-flag", "blue", &failing))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createStaleProvider(t, server)
+	defer provider.Shutdown()
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1"}
+	if result := provider.EvaluateFlag("my-flag", evalCtx); result == nil || result.AsString() != "blue" {
+		t.Fatalf("Expected live value, got %+v", result)
+	}
+
+	atomic.StoreInt32(&failing, 1)
+
+	result := provider.EvaluateFlag("my-flag", evalCtx)
+	if result == nil || result.AsString() != "blue" {
+		t.Fatalf("Expected last-known value, got %+v", result)
+	}
+	if result.Reason != string(StaleReason) {
+		t.Errorf("Expected reason STALE, got %q", result.Reason)
+	}
+
+	// Nothing is known for another context
+	if result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{"targetingKey": "user-2"}); result != nil {
+		t.Errorf("Expected nil without a last-known value, got %+v", result)
+	}
+}
+
+func TestServeStaleOnError_TypedEvaluationServesLastKnown(t *testing.T) {
+	var failing int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", flakyFlag("my-flag", true, &failing))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createStaleProvider(t, server)
+	defer provider.Shutdown()
+
+	ctx := context.Background()
+	if detail := provider.BooleanEvaluation(ctx, "my-flag", false, openfeature.FlattenedContext{}); !detail.Value {
+		t.Fatalf("Expected live value true, got %+v", detail)
+	}
+
+	atomic.StoreInt32(&failing, 1)
+
+	detail := provider.BooleanEvaluation(ctx, "my-flag", false, openfeature.FlattenedContext{})
+	if !detail.Value {
+		t.Errorf("Expected last-known value true instead of the default, got %+v", detail)
+	}
+	if detail.Reason != StaleReason {
+		t.Errorf("Expected reason STALE, got %q", detail.Reason)
+	}
+	if detail.Variant != "on" {
+		t.Errorf("Expected last-known variant, got %q", detail.Variant)
+	}
+}
+
+func TestServeStaleOnError_IgnoresCacheTTL(t *testing.T) {
+	var failing int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", flakyFlag("my-flag", "blue", &failing))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createStaleProvider(t, server, WithCache(time.Minute))
+	defer provider.Shutdown()
+
+	now := time.Now()
+	provider.cache.now = func() time.Time { return now }
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+
+	// Expire the cached entry, then fail the live request
+	now = now.Add(time.Hour)
+	atomic.StoreInt32(&failing, 1)
+
+	result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	if result == nil || result.AsString() != "blue" {
+		t.Errorf("Expected last-known value after the TTL, got %+v", result)
+	}
+}
+
+func TestServeStaleOnError_NoLastKnownFallsBackToDefault(t *testing.T) {
+	var failing int32 = 1
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", flakyFlag("my-flag", true, &failing))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createStaleProvider(t, server)
+	defer provider.Shutdown()
+
+	if result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{}); result != nil {
+		t.Errorf("Expected nil without a last-known value, got %+v", result)
+	}
+	detail := provider.BooleanEvaluation(context.Background(), "my-flag", false, openfeature.FlattenedContext{})
+	if detail.Value {
+		t.Error("Expected the default value without a last-known value")
+	}
+	if detail.Reason != openfeature.ErrorReason {
+		t.Errorf("Expected reason ERROR, got %q", detail.Reason)
+	}
+}
+
+func TestServeStaleOnError_DisabledByDefault(t *testing.T) {
+	var failing int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", flakyFlag("my-flag", "blue", &failing))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	atomic.StoreInt32(&failing, 1)
+
+	if result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{}); result != nil {
+		t.Errorf("Expected nil without WithServeStaleOnError, got %+v", result)
+	}
+}
+
+func TestServeStaleOnError_ParseErrorServesLastKnown(t *testing.T) {
+	var failing int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", func() (int, map[string]interface{}) {
+		if atomic.LoadInt32(&failing) == 1 {
+			return 400, map[string]interface{}{"key": "my-flag", "errorCode": "PARSE_ERROR"}
+		}
+		return 200, map[string]interface{}{"key": "my-flag", "value": "blue"}
+	})
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createStaleProvider(t, server)
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	atomic.StoreInt32(&failing, 1)
+
+	result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	if result == nil || result.AsString() != "blue" || result.Reason != string(StaleReason) {
+		t.Errorf("Expected the last-known value after a parse error, got %+v", result)
+	}
+}
+
+func TestServeStaleOnError_CappedByCacheMaxEntries(t *testing.T) {
+	var failing int32
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", flakyFlag("my-flag", "blue", &failing))
+	server := httptest.NewServer(dispatcher)
+	defer server.Close()
+
+	provider := createStaleProvider(t, server, WithCacheMaxEntries(2))
+	defer provider.Shutdown()
+
+	users := []string{"user-1", "user-2", "user-3"}
+	for _, user := range users {
+		provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{"targetingKey": user})
+	}
+	atomic.StoreInt32(&failing, 1)
+
+	// The least recently used value was evicted
+	if result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{"targetingKey": "user-1"}); result != nil {
+		t.Errorf("Expected no last-known value for user-1, got %+v", result)
+	}
+	for _, user := range users[1:] {
+		if result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{"targetingKey": user}); result == nil {
+			t.Errorf("Expected a last-known value for %s", user)
+		}
+	}
+}
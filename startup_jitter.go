@@ -0,0 +1,44 @@
+package flipswitch
+
+import (
+	"context"
+	"time"
+)
+
+// WithStartupJitter makes Init wait a random duration of up to max before
+// its first request, so that a fleet of instances started at the same
+// moment (for example by an autoscaling event) does not validate its API
+// keys and open SSE connections in lockstep. The wait ends early if the
+// context given to InitWithContext is cancelled or its deadline passes.
+// Zero, the default, disables the jitter.
+func WithStartupJitter(max time.Duration) Option {
+	return func(p *FlipswitchProvider) {
+		p.startupJitter = max
+	}
+}
+
+// waitStartupJitter waits for the startup jitter, returning ctx's error if
+// it is done first.
+func (p *FlipswitchProvider) waitStartupJitter(ctx context.Context) error {
+	delay := p.startupDelay()
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	p.logger.Debugw("Delaying initialization", "delay", delay)
+
+	select {
+	case <-p.clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// startupDelay draws how long Init waits before its first request, which is
+// zero when the startup jitter is disabled.
+func (p *FlipswitchProvider) startupDelay() time.Duration {
+	if p.startupJitter <= 0 {
+		return 0
+	}
+	return time.Duration(p.rng.Int63n(int64(p.startupJitter)))
+}
@@ -0,0 +1,118 @@
+package flipswitch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestStartupJitter_DelaysFirstRequestWithinBound(t *testing.T) {
+	const (
+		maxJitter = 500 * time.Millisecond
+		seed      = 42
+	)
+	want := time.Duration(newLockedRand(seed).Int63n(int64(maxJitter)))
+
+	var requests int32
+	dispatcher := NewTestDispatcher()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		dispatcher.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	clock := newFakeClock()
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithRandSeed(seed),
+		// A generated connection ID would draw from the seeded source first
+		WithConnectionID("test-connection"),
+		WithStartupJitter(maxJitter),
+		withClock(clock),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	initErr := make(chan error, 1)
+	go func() { initErr <- provider.Init(openfeature.EvaluationContext{}) }()
+
+	if delay := clock.nextScheduled(t); delay != want {
+		t.Errorf("Expected a startup delay of %v, got %v", want, delay)
+	}
+	clock.Advance(want - time.Nanosecond)
+	time.Sleep(50 * time.Millisecond)
+	if got := atomic.LoadInt32(&requests); got != 0 {
+		t.Fatalf("Expected no requests before the delay, got %d", got)
+	}
+
+	clock.Advance(time.Nanosecond)
+	if err := <-initErr; err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got == 0 {
+		t.Error("Expected the first request once the delay passed")
+	}
+}
+
+func TestStartupJitter_CancelledContextShortCircuits(t *testing.T) {
+	var requests int32
+	dispatcher := NewTestDispatcher()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		dispatcher.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithStartupJitter(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = provider.InitWithContext(ctx, openfeature.EvaluationContext{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to end initialization, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected Init to return at the deadline, took %v", elapsed)
+	}
+	if got := atomic.LoadInt32(&requests); got != 0 {
+		t.Errorf("Expected no requests, got %d", got)
+	}
+	if provider.Status() == openfeature.ReadyState {
+		t.Error("Expected the provider not to be ready")
+	}
+}
+
+func TestStartupJitter_OffByDefault(t *testing.T) {
+	provider, err := NewProvider("test-api-key", WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if delay := provider.startupDelay(); delay != 0 {
+		t.Errorf("Expected no startup delay by default, got %v", delay)
+	}
+}
+
+func TestProvider_IsContextAwareStateHandler(t *testing.T) {
+	var _ openfeature.ContextAwareStateHandler = &FlipswitchProvider{}
+}
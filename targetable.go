@@ -0,0 +1,32 @@
+package flipswitch
+
+import (
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Targetable is implemented by application types, such as users or
+// accounts, that can be evaluated against directly with EvaluateFlagFor
+// instead of building an evaluation context by hand.
+type Targetable interface {
+	// TargetingKey returns the key that identifies the subject, usually its ID.
+	TargetingKey() string
+	// Attributes returns additional attributes used for targeting. It may
+	// return nil.
+	Attributes() map[string]interface{}
+}
+
+// EvaluateFlagFor is like EvaluateFlag but builds the evaluation context
+// from t. The targeting key is sent as "targetingKey", taking precedence
+// over an attribute of the same name. A nil t evaluates with an empty
+// context.
+func (p *FlipswitchProvider) EvaluateFlagFor(flagKey string, t Targetable) *FlagEvaluation {
+	return p.EvaluateFlag(flagKey, targetableContext(t))
+}
+
+// targetableContext builds the evaluation context for t.
+func targetableContext(t Targetable) openfeature.FlattenedContext {
+	if t == nil {
+		return openfeature.FlattenedContext{}
+	}
+	return flattenContext(openfeature.NewEvaluationContext(t.TargetingKey(), t.Attributes()))
+}
@@ -0,0 +1,60 @@
+package flipswitch
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testUser struct {
+	id   string
+	plan string
+}
+
+func (u testUser) TargetingKey() string { return u.id }
+
+func (u testUser) Attributes() map[string]interface{} {
+	return map[string]interface{}{"plan": u.plan, "targetingKey": "ignored"}
+}
+
+func TestEvaluateFlagFor_SendsTargetableContext(t *testing.T) {
+	server, sent := contextRecordingServer()
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	result := provider.EvaluateFlagFor("my-flag", testUser{id: "user-42", plan: "pro"})
+	if result == nil || !result.AsBoolean() {
+		t.Fatalf("Expected my-flag to evaluate, got %+v", result)
+	}
+
+	contexts := sent()
+	if len(contexts) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(contexts))
+	}
+	want := map[string]interface{}{"targetingKey": "user-42", "plan": "pro"}
+	if !reflect.DeepEqual(contexts[0], want) {
+		t.Errorf("Expected context %v, got %v", want, contexts[0])
+	}
+}
+
+func TestEvaluateFlagFor_NilTargetable(t *testing.T) {
+	server, sent := contextRecordingServer()
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if result := provider.EvaluateFlagFor("my-flag", nil); result == nil {
+		t.Fatal("Expected my-flag to evaluate with an empty context")
+	}
+	if contexts := sent(); len(contexts) != 1 || len(contexts[0]) != 0 {
+		t.Errorf("Expected an empty context, got %v", contexts)
+	}
+}
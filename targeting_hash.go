@@ -0,0 +1,45 @@
+package flipswitch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// WithTargetingKeyHash replaces the targeting key with hash(targetingKey)
+// before a context is sent to the server, so raw user identifiers never
+// leave the process. hash must be deterministic, so that a user keeps the
+// same pseudonymous key and stays in the same rollout bucket. Use
+// SHA256TargetingKey for a ready-made hash. Local state such as the cache is
+// still keyed by the raw context.
+func WithTargetingKeyHash(hash func(string) string) Option {
+	return func(p *FlipswitchProvider) {
+		p.targetingKeyHash = hash
+	}
+}
+
+// SHA256TargetingKey returns the hex encoded SHA-256 digest of key, for use
+// with WithTargetingKeyHash.
+func SHA256TargetingKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashTargetingKey returns evalCtx with its targeting key hashed, copying
+// the context rather than modifying the caller's.
+func (p *FlipswitchProvider) hashTargetingKey(evalCtx openfeature.FlattenedContext) openfeature.FlattenedContext {
+	if p.targetingKeyHash == nil {
+		return evalCtx
+	}
+	key, ok := evalCtx[openfeature.TargetingKey].(string)
+	if !ok {
+		return evalCtx
+	}
+	hashed := make(openfeature.FlattenedContext, len(evalCtx))
+	for k, v := range evalCtx {
+		hashed[k] = v
+	}
+	hashed[openfeature.TargetingKey] = p.targetingKeyHash(key)
+	return hashed
+}
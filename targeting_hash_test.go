@@ -0,0 +1,90 @@
+package flipswitch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func TestSHA256TargetingKey_Deterministic(t *testing.T) {
+	first := SHA256TargetingKey("user-1")
+	if first != SHA256TargetingKey("user-1") {
+		t.Error("Expected identical inputs to produce identical hashes")
+	}
+	if first == SHA256TargetingKey("user-2") {
+		t.Error("Expected different inputs to produce different hashes")
+	}
+	// echo -n user-1 | sha256sum
+	if want := "c6c289e49e9c05b2145860387b73bcb18df43fb09a1e4a4a9713c76c88bb541b"; first != want {
+		t.Errorf("Expected %s, got %s", want, first)
+	}
+}
+
+func TestTargetingKeyHash_HashedKeySent(t *testing.T) {
+	server, sent := contextRecordingServer()
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithTargetingKeyHash(SHA256TargetingKey),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	evalCtx := openfeature.FlattenedContext{"targetingKey": "user-1", "plan": "pro"}
+	provider.EvaluateFlag("my-flag", evalCtx)
+	provider.EvaluateAllFlags(evalCtx)
+	provider.BooleanEvaluation(context.Background(), "my-flag", false, evalCtx)
+
+	want := SHA256TargetingKey("user-1")
+	bodies := sent()
+	if len(bodies) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(bodies))
+	}
+	for i, body := range bodies {
+		if body["targetingKey"] != want {
+			t.Errorf("Request %d: expected hashed targeting key %s, got %v", i+1, want, body["targetingKey"])
+		}
+		if body["plan"] != "pro" {
+			t.Errorf("Request %d: expected other attributes to pass through, got %v", i+1, body)
+		}
+	}
+	if evalCtx["targetingKey"] != "user-1" {
+		t.Errorf("Expected the caller's context to be unchanged, got %v", evalCtx["targetingKey"])
+	}
+}
+
+func TestTargetingKeyHash_CustomFunction(t *testing.T) {
+	server, sent := contextRecordingServer()
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithTargetingKeyHash(func(key string) string { return "pseudo-" + key }),
+		WithContextDenylist([]string{"email"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{"targetingKey": "user-1", "email": "a@b.c"})
+
+	bodies := sent()
+	if len(bodies) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(bodies))
+	}
+	if bodies[0]["targetingKey"] != "pseudo-user-1" {
+		t.Errorf("Expected pseudo-user-1, got %v", bodies[0]["targetingKey"])
+	}
+	if _, ok := bodies[0]["email"]; ok {
+		t.Errorf("Expected email to be stripped, got %v", bodies[0])
+	}
+}
@@ -0,0 +1,48 @@
+package flipswitch
+
+import (
+	"net/http"
+	"runtime"
+)
+
+// WithTelemetryDisabled stops the provider from sending the X-Flipswitch-SDK,
+// X-Flipswitch-Runtime, X-Flipswitch-OS and X-Flipswitch-Features headers.
+// The headers are then never computed.
+func WithTelemetryDisabled() Option {
+	return func(p *FlipswitchProvider) {
+		p.telemetryDisabled = true
+	}
+}
+
+// buildTelemetryHeaders computes the telemetry headers. They do not change
+// over the life of a provider, so this runs once in NewProvider.
+func buildTelemetryHeaders(realtime bool) http.Header {
+	features := "sse=false"
+	if realtime {
+		features = "sse=true"
+	}
+	header := http.Header{}
+	header.Set("X-Flipswitch-SDK", "go/"+sdkVersion)
+	header.Set("X-Flipswitch-Runtime", "go/"+runtime.Version()[2:]) // Strip "go" prefix from go1.21.0
+	header.Set("X-Flipswitch-OS", runtime.GOOS+"/"+runtime.GOARCH)
+	header.Set("X-Flipswitch-Features", features)
+	return header
+}
+
+// setTelemetryHeaders adds the precomputed telemetry headers to req. The
+// value slices are shared between requests and must not be modified.
+func (p *FlipswitchProvider) setTelemetryHeaders(req *http.Request) {
+	for key, values := range p.telemetry {
+		req.Header[key] = values
+	}
+}
+
+// getTelemetryHeaders returns the telemetry headers in the form taken by
+// NewSseClient.
+func (p *FlipswitchProvider) getTelemetryHeaders() map[string]string {
+	headers := make(map[string]string, len(p.telemetry))
+	for key, values := range p.telemetry {
+		headers[key] = values[0]
+	}
+	return headers
+}
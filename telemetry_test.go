@@ -0,0 +1,128 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+var telemetryHeaderNames = []string{
+	"X-Flipswitch-SDK",
+	"X-Flipswitch-Runtime",
+	"X-Flipswitch-OS",
+	"X-Flipswitch-Features",
+}
+
+// headerRecordingServer records the telemetry headers of every evaluation
+// request it serves.
+func headerRecordingServer() (*httptest.Server, func() []http.Header) {
+	var mu sync.Mutex
+	var seen []http.Header
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", countingFlag("my-flag", new(int32)))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := http.Header{}
+		for _, name := range telemetryHeaderNames {
+			if v := r.Header.Get(name); v != "" {
+				header.Set(name, v)
+			}
+		}
+		mu.Lock()
+		seen = append(seen, header)
+		mu.Unlock()
+		dispatcher.ServeHTTP(w, r)
+	}))
+	return server, func() []http.Header {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]http.Header(nil), seen...)
+	}
+}
+
+func TestTelemetry_HeadersStableAcrossCalls(t *testing.T) {
+	server, seen := headerRecordingServer()
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{"targetingKey": "a"})
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{"targetingKey": "b"})
+	provider.BooleanEvaluation(context.Background(), "my-flag", false, openfeature.FlattenedContext{})
+
+	headers := seen()
+	if len(headers) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(headers))
+	}
+	for _, name := range telemetryHeaderNames {
+		if headers[0].Get(name) == "" {
+			t.Errorf("Expected %s header", name)
+		}
+	}
+	for i, h := range headers[1:] {
+		if !reflect.DeepEqual(h, headers[0]) {
+			t.Errorf("Request %d headers %v differ from first request %v", i+2, h, headers[0])
+		}
+	}
+	if got := headers[0].Get("X-Flipswitch-Features"); got != "sse=false" {
+		t.Errorf("Expected sse=false, got %q", got)
+	}
+}
+
+func TestTelemetry_DisabledSendsNoHeaders(t *testing.T) {
+	server, seen := headerRecordingServer()
+	defer server.Close()
+
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithTelemetryDisabled(),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+	provider.BooleanEvaluation(context.Background(), "my-flag", false, openfeature.FlattenedContext{})
+
+	for i, h := range seen() {
+		if len(h) != 0 {
+			t.Errorf("Request %d: expected no telemetry headers, got %v", i+1, h)
+		}
+	}
+}
+
+func BenchmarkTelemetryHeaders(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"enabled", nil},
+		{"disabled", []Option{WithTelemetryDisabled()}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			provider, err := NewProvider("test-api-key", append([]Option{WithRealtime(false)}, bc.opts...)...)
+			if err != nil {
+				b.Fatalf("Failed to create provider: %v", err)
+			}
+			req, _ := http.NewRequest("POST", "http://localhost/ofrep/v1/evaluate/flags/my-flag", nil)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				req.Header = make(http.Header, 8)
+				provider.setTelemetryHeaders(req)
+			}
+		})
+	}
+}
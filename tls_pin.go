@@ -0,0 +1,111 @@
+package flipswitch
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrTLSPinMismatch is returned, wrapped in the request error, when the
+// server's certificate does not match any fingerprint given to WithTLSPin.
+var ErrTLSPinMismatch = errors.New("server certificate does not match any pinned fingerprint")
+
+// WithTLSPin pins the Flipswitch server's TLS certificate: connections are
+// rejected unless the SHA-256 fingerprint of the leaf certificate's public key
+// (its SubjectPublicKeyInfo) matches one of fingerprints. Fingerprints are
+// given in hex, optionally colon-separated, or in standard base64. Supply the
+// pin of the next key as well before rotating certificates.
+//
+// Pinning applies to evaluations and the SSE connection, and on top of the
+// usual certificate verification. The transport of a client set with
+// WithHTTPClient is cloned rather than modified; it must be an
+// *http.Transport. NewProvider returns an error if a fingerprint is
+// malformed or the transport cannot be pinned.
+func WithTLSPin(fingerprints ...string) Option {
+	return func(p *FlipswitchProvider) {
+		p.tlsPins = append(p.tlsPins, fingerprints...)
+	}
+}
+
+// applyTLSPin replaces the HTTP client with a copy whose transport verifies
+// the pins set with WithTLSPin, if any.
+func (p *FlipswitchProvider) applyTLSPin() error {
+	if len(p.tlsPins) == 0 {
+		return nil
+	}
+
+	pins := make([][]byte, 0, len(p.tlsPins))
+	for _, fingerprint := range p.tlsPins {
+		pin, err := parseFingerprint(fingerprint)
+		if err != nil {
+			return err
+		}
+		pins = append(pins, pin)
+	}
+
+	var transport *http.Transport
+	switch t := p.httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return fmt.Errorf("WithTLSPin: cannot pin HTTP client transport %T, use an *http.Transport", t)
+	}
+
+	config := transport.TLSClientConfig
+	if config == nil {
+		config = &tls.Config{}
+	}
+	// VerifyConnection, unlike VerifyPeerCertificate, also runs on resumed
+	// sessions
+	verify := config.VerifyConnection
+	config.VerifyConnection = func(state tls.ConnectionState) error {
+		if err := verifyPin(state, pins); err != nil {
+			return err
+		}
+		if verify != nil {
+			return verify(state)
+		}
+		return nil
+	}
+	transport.TLSClientConfig = config
+
+	client := *p.httpClient
+	client.Transport = transport
+	p.httpClient = &client
+	return nil
+}
+
+// verifyPin checks the leaf certificate of state against pins.
+func verifyPin(state tls.ConnectionState, pins [][]byte) error {
+	if len(state.PeerCertificates) == 0 {
+		return ErrTLSPinMismatch
+	}
+	sum := sha256.Sum256(state.PeerCertificates[0].RawSubjectPublicKeyInfo)
+	for _, pin := range pins {
+		if bytes.Equal(sum[:], pin) {
+			return nil
+		}
+	}
+	return ErrTLSPinMismatch
+}
+
+// parseFingerprint decodes a SHA-256 fingerprint in hex, optionally
+// colon-separated, or standard base64.
+func parseFingerprint(fingerprint string) ([]byte, error) {
+	trimmed := strings.TrimSpace(fingerprint)
+	if pin, err := hex.DecodeString(strings.ReplaceAll(trimmed, ":", "")); err == nil && len(pin) == sha256.Size {
+		return pin, nil
+	}
+	if pin, err := base64.StdEncoding.DecodeString(trimmed); err == nil && len(pin) == sha256.Size {
+		return pin, nil
+	}
+	return nil, fmt.Errorf("invalid TLS pin %q: expected a SHA-256 fingerprint in hex or base64", fingerprint)
+}
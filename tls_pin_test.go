@@ -0,0 +1,123 @@
+package flipswitch
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// serverPin returns the SHA-256 fingerprint of the public key of the
+// TLS test server's certificate.
+func serverPin(server *httptest.Server) []byte {
+	sum := sha256.Sum256(server.Certificate().RawSubjectPublicKeyInfo)
+	return sum[:]
+}
+
+func pinnedTLSServer() *httptest.Server {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("dark-mode", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "dark-mode", "value": true}
+	})
+	return httptest.NewTLSServer(dispatcher)
+}
+
+func TestWithTLSPin_MatchingPinConnects(t *testing.T) {
+	server := pinnedTLSServer()
+	defer server.Close()
+
+	pin := serverPin(server)
+	colonHex := strings.ToUpper(hex.EncodeToString(pin))
+	for i := len(colonHex) - 2; i > 0; i -= 2 {
+		colonHex = colonHex[:i] + ":" + colonHex[i:]
+	}
+
+	for name, fingerprint := range map[string]string{
+		"hex":       hex.EncodeToString(pin),
+		"colon hex": colonHex,
+		"base64":    base64.StdEncoding.EncodeToString(pin),
+	} {
+		t.Run(name, func(t *testing.T) {
+			provider, err := NewProvider("test-api-key",
+				WithBaseURL(server.URL),
+				WithRealtime(false),
+				WithHTTPClient(server.Client()),
+				WithTLSPin(strings.Repeat("00", sha256.Size), fingerprint),
+			)
+			if err != nil {
+				t.Fatalf("Failed to create provider: %v", err)
+			}
+			defer provider.Shutdown()
+
+			flag, err := provider.EvaluateFlagE("dark-mode", openfeature.FlattenedContext{})
+			if err != nil {
+				t.Fatalf("Expected the pinned server to be accepted, got %v", err)
+			}
+			if flag.Value != true {
+				t.Errorf("Expected true, got %v", flag.Value)
+			}
+		})
+	}
+}
+
+func TestWithTLSPin_MismatchRejectsEvaluationsAndSse(t *testing.T) {
+	server := pinnedTLSServer()
+	defer server.Close()
+
+	client := server.Client()
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithHTTPClient(client),
+		WithTLSPin(strings.Repeat("ab", sha256.Size)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if _, err := provider.EvaluateFlagE("dark-mode", openfeature.FlattenedContext{}); !errors.Is(err, ErrTLSPinMismatch) {
+		t.Errorf("Expected ErrTLSPinMismatch from an evaluation, got %v", err)
+	}
+	if result := provider.BooleanEvaluation(t.Context(), "dark-mode", false, openfeature.FlattenedContext{}); result.Value {
+		t.Error("Expected the default from an OpenFeature evaluation")
+	}
+	resp, err := provider.sseHTTPClient.Get(server.URL + defaultSsePath)
+	if err == nil {
+		resp.Body.Close()
+	}
+	if !errors.Is(err, ErrTLSPinMismatch) {
+		t.Errorf("Expected ErrTLSPinMismatch from the SSE client, got %v", err)
+	}
+
+	// The caller's client is left unpinned
+	resp, err = client.Get(server.URL + "/ofrep/v1/evaluate/flags/dark-mode")
+	if err != nil {
+		t.Fatalf("Expected the original client to be unchanged, got %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestWithTLSPin_InvalidConfiguration(t *testing.T) {
+	tests := map[string][]Option{
+		"malformed":  {WithTLSPin("not-a-fingerprint")},
+		"wrong size": {WithTLSPin(hex.EncodeToString([]byte("short")))},
+		"custom transport": {
+			WithHTTPClient(&http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)}),
+			WithTLSPin(strings.Repeat("00", sha256.Size)),
+		},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewProvider("test-api-key", append([]Option{WithRealtime(false)}, opts...)...); err == nil {
+				t.Error("Expected NewProvider to fail")
+			}
+		})
+	}
+}
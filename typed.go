@@ -0,0 +1,121 @@
+package flipswitch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// EvaluateTyped evaluates flagKey with the OpenFeature resolution method
+// matching T: BooleanEvaluation for bool, StringEvaluation for string,
+// IntEvaluation for int and int64, FloatEvaluation for float64 and
+// ObjectEvaluation for anything else. An object value that is not already a
+// T is decoded into one with encoding/json, so T may be a struct. It returns
+// defaultValue and a TYPE_MISMATCH error detail if the value cannot be
+// represented as a T.
+//
+// Go does not allow type parameters on methods, so this is a function taking
+// the provider.
+func EvaluateTyped[T any](p *FlipswitchProvider, ctx context.Context, flagKey string, defaultValue T, evalCtx openfeature.FlattenedContext) (T, openfeature.ProviderResolutionDetail) {
+	var value interface{}
+	var detail openfeature.ProviderResolutionDetail
+
+	switch def := any(defaultValue).(type) {
+	case bool:
+		result := p.BooleanEvaluation(ctx, flagKey, def, evalCtx)
+		value, detail = result.Value, result.ProviderResolutionDetail
+	case string:
+		result := p.StringEvaluation(ctx, flagKey, def, evalCtx)
+		value, detail = result.Value, result.ProviderResolutionDetail
+	case int64:
+		result := p.IntEvaluation(ctx, flagKey, def, evalCtx)
+		value, detail = result.Value, result.ProviderResolutionDetail
+	case int:
+		result := p.IntEvaluation(ctx, flagKey, int64(def), evalCtx)
+		value, detail = int(result.Value), result.ProviderResolutionDetail
+	case float64:
+		result := p.FloatEvaluation(ctx, flagKey, def, evalCtx)
+		value, detail = result.Value, result.ProviderResolutionDetail
+	default:
+		result := p.ObjectEvaluation(ctx, flagKey, defaultValue, evalCtx)
+		value, detail = result.Value, result.ProviderResolutionDetail
+	}
+
+	if typed, ok := value.(T); ok {
+		return typed, detail
+	}
+
+	// Object values arrive as decoded JSON; decode them into T
+	var typed T
+	raw, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(raw, &typed)
+	}
+	if err != nil {
+		return defaultValue, openfeature.ProviderResolutionDetail{
+			ResolutionError: openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("flag %q: value cannot be decoded as %T: %v", flagKey, defaultValue, err)),
+			Reason:          openfeature.ErrorReason,
+		}
+	}
+	return typed, detail
+}
+
+// GetValue evaluates flagKey like EvaluateFlagCtx and returns its value as a
+// T, or defaultValue if the flag is missing, the evaluation failed or the
+// value is not a T. T must be bool, string, int, int64, float64 or
+// map[string]interface{}; any other T always gets defaultValue. Numbers
+// convert between the numeric types, except that a value with a fraction is
+// not an int or int64.
+//
+// Unlike EvaluateTyped, it uses the direct evaluation path and reports no
+// resolution detail; use EvaluateFlagE to find out why the default was
+// returned.
+func GetValue[T any](p *FlipswitchProvider, ctx context.Context, flagKey string, defaultValue T, evalCtx openfeature.FlattenedContext) T {
+	eval := p.EvaluateFlagCtx(ctx, flagKey, evalCtx)
+	if eval == nil {
+		return defaultValue
+	}
+
+	var value interface{}
+	var ok bool
+	switch any(defaultValue).(type) {
+	case bool:
+		value, ok = eval.Value.(bool)
+	case string:
+		value, ok = eval.Value.(string)
+	case int:
+		var i int64
+		i, ok = integralValue(eval)
+		value = int(i)
+	case int64:
+		value, ok = integralValue(eval)
+	case float64:
+		switch inferType(eval.Value) {
+		case "integer", "number":
+			value, ok = eval.AsFloat(), true
+		}
+	case map[string]interface{}:
+		value, ok = eval.AsObject()
+	}
+	if !ok {
+		return defaultValue
+	}
+	return value.(T)
+}
+
+// integralValue returns the value of eval as an int64 if it is a number
+// without a fraction.
+func integralValue(eval *FlagEvaluation) (int64, bool) {
+	switch inferType(eval.Value) {
+	case "integer":
+		return int64(eval.AsInt()), true
+	case "number":
+		if f := eval.AsFloat(); f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return int64(f), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,196 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+func createTypedProvider(t *testing.T) (*FlipswitchProvider, func()) {
+	t.Helper()
+	values := map[string]interface{}{
+		"bool-flag":   true,
+		"string-flag": "blue",
+		"int-flag":    42,
+		"float-flag":  2.5,
+		"object-flag": map[string]interface{}{"batchSize": 50, "name": "fast"},
+	}
+	dispatcher := NewTestDispatcher()
+	for key, value := range values {
+		key, value := key, value
+		dispatcher.SetFlagResponse(key, func() (int, map[string]interface{}) {
+			return 200, map[string]interface{}{"key": key, "value": value, "reason": "TARGETING_MATCH", "variant": "v1"}
+		})
+	}
+	server := httptest.NewServer(dispatcher)
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		server.Close()
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	return provider, func() {
+		provider.Shutdown()
+		server.Close()
+	}
+}
+
+func TestEvaluateTyped_Bool(t *testing.T) {
+	provider, cleanup := createTypedProvider(t)
+	defer cleanup()
+
+	value, detail := EvaluateTyped(provider, context.Background(), "bool-flag", false, openfeature.FlattenedContext{})
+	if !value {
+		t.Error("Expected true")
+	}
+	if detail.Reason != openfeature.TargetingMatchReason || detail.Variant != "v1" {
+		t.Errorf("Expected resolution details from the server, got %+v", detail)
+	}
+}
+
+func TestEvaluateTyped_String(t *testing.T) {
+	provider, cleanup := createTypedProvider(t)
+	defer cleanup()
+
+	value, _ := EvaluateTyped(provider, context.Background(), "string-flag", "red", openfeature.FlattenedContext{})
+	if value != "blue" {
+		t.Errorf("Expected blue, got %q", value)
+	}
+}
+
+func TestEvaluateTyped_Int64(t *testing.T) {
+	provider, cleanup := createTypedProvider(t)
+	defer cleanup()
+
+	value, _ := EvaluateTyped(provider, context.Background(), "int-flag", int64(0), openfeature.FlattenedContext{})
+	if value != 42 {
+		t.Errorf("Expected 42, got %d", value)
+	}
+
+	intValue, _ := EvaluateTyped(provider, context.Background(), "int-flag", 0, openfeature.FlattenedContext{})
+	if intValue != 42 {
+		t.Errorf("Expected 42 as int, got %d", intValue)
+	}
+}
+
+func TestEvaluateTyped_Float64(t *testing.T) {
+	provider, cleanup := createTypedProvider(t)
+	defer cleanup()
+
+	value, _ := EvaluateTyped(provider, context.Background(), "float-flag", 0.0, openfeature.FlattenedContext{})
+	if value != 2.5 {
+		t.Errorf("Expected 2.5, got %v", value)
+	}
+}
+
+func TestEvaluateTyped_Object(t *testing.T) {
+	provider, cleanup := createTypedProvider(t)
+	defer cleanup()
+
+	value, _ := EvaluateTyped(provider, context.Background(), "object-flag", map[string]interface{}{}, openfeature.FlattenedContext{})
+	want := map[string]interface{}{"batchSize": float64(50), "name": "fast"}
+	if !reflect.DeepEqual(value, want) {
+		t.Errorf("Expected %v, got %v", want, value)
+	}
+
+	type settings struct {
+		BatchSize int    `json:"batchSize"`
+		Name      string `json:"name"`
+	}
+	decoded, detail := EvaluateTyped(provider, context.Background(), "object-flag", settings{}, openfeature.FlattenedContext{})
+	if decoded != (settings{BatchSize: 50, Name: "fast"}) {
+		t.Errorf("Expected the object decoded into a struct, got %+v", decoded)
+	}
+	if detail.Reason != openfeature.TargetingMatchReason {
+		t.Errorf("Expected reason TARGETING_MATCH, got %q", detail.Reason)
+	}
+}
+
+func TestEvaluateTyped_TypeMismatch(t *testing.T) {
+	provider, cleanup := createTypedProvider(t)
+	defer cleanup()
+
+	value, detail := EvaluateTyped(provider, context.Background(), "string-flag", false, openfeature.FlattenedContext{})
+	if value {
+		t.Error("Expected the default value")
+	}
+	if detail.Reason != openfeature.ErrorReason {
+		t.Errorf("Expected reason ERROR, got %q", detail.Reason)
+	}
+
+	type settings struct {
+		BatchSize int `json:"batchSize"`
+	}
+	def := settings{BatchSize: 7}
+	decoded, detail := EvaluateTyped(provider, context.Background(), "string-flag", def, openfeature.FlattenedContext{})
+	if decoded != def {
+		t.Errorf("Expected the default value, got %+v", decoded)
+	}
+	if detail.ResolutionDetail().ErrorCode != openfeature.TypeMismatchCode {
+		t.Errorf("Expected TYPE_MISMATCH, got %q", detail.ResolutionDetail().ErrorCode)
+	}
+}
+
+func TestGetValue_MatchingTypes(t *testing.T) {
+	provider, cleanup := createTypedProvider(t)
+	defer cleanup()
+	ctx, evalCtx := context.Background(), openfeature.FlattenedContext{"targetingKey": "user-1"}
+
+	if got := GetValue(provider, ctx, "bool-flag", false, evalCtx); !got {
+		t.Error("Expected true for bool-flag")
+	}
+	if got := GetValue(provider, ctx, "string-flag", "", evalCtx); got != "blue" {
+		t.Errorf("Expected 'blue', got %q", got)
+	}
+	if got := GetValue(provider, ctx, "int-flag", 0, evalCtx); got != 42 {
+		t.Errorf("Expected int 42, got %d", got)
+	}
+	if got := GetValue(provider, ctx, "int-flag", int64(0), evalCtx); got != 42 {
+		t.Errorf("Expected int64 42, got %d", got)
+	}
+	if got := GetValue(provider, ctx, "int-flag", 0.0, evalCtx); got != 42 {
+		t.Errorf("Expected float64 42, got %v", got)
+	}
+	if got := GetValue(provider, ctx, "float-flag", 0.0, evalCtx); got != 2.5 {
+		t.Errorf("Expected 2.5, got %v", got)
+	}
+	want := map[string]interface{}{"batchSize": float64(50), "name": "fast"}
+	if got := GetValue(provider, ctx, "object-flag", map[string]interface{}(nil), evalCtx); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestGetValue_MismatchReturnsDefault(t *testing.T) {
+	provider, cleanup := createTypedProvider(t)
+	defer cleanup()
+	ctx, evalCtx := context.Background(), openfeature.FlattenedContext{"targetingKey": "user-1"}
+
+	if got := GetValue(provider, ctx, "string-flag", true, evalCtx); !got {
+		t.Error("Expected the bool default for a string flag")
+	}
+	if got := GetValue(provider, ctx, "bool-flag", "fallback", evalCtx); got != "fallback" {
+		t.Errorf("Expected the string default for a bool flag, got %q", got)
+	}
+	if got := GetValue(provider, ctx, "float-flag", 7, evalCtx); got != 7 {
+		t.Errorf("Expected the int default for a fractional number, got %d", got)
+	}
+	if got := GetValue(provider, ctx, "float-flag", int64(7), evalCtx); got != 7 {
+		t.Errorf("Expected the int64 default for a fractional number, got %d", got)
+	}
+	if got := GetValue(provider, ctx, "string-flag", 1.5, evalCtx); got != 1.5 {
+		t.Errorf("Expected the float64 default for a string flag, got %v", got)
+	}
+	def := map[string]interface{}{"default": true}
+	if got := GetValue(provider, ctx, "int-flag", def, evalCtx); !reflect.DeepEqual(got, def) {
+		t.Errorf("Expected the map default for a number flag, got %v", got)
+	}
+	if got := GetValue(provider, ctx, "missing-flag", "fallback", evalCtx); got != "fallback" {
+		t.Errorf("Expected the default for a missing flag, got %q", got)
+	}
+	if got := GetValue(provider, ctx, "int-flag", uint(3), evalCtx); got != 3 {
+		t.Errorf("Expected the default for an unsupported type, got %d", got)
+	}
+}
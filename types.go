@@ -1,6 +1,11 @@
 package flipswitch
 
-import "time"
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+)
 
 // FlipswitchOptions contains configuration options for the Flipswitch provider.
 type FlipswitchOptions struct {
@@ -26,8 +31,28 @@ const (
 	StatusConnected ConnectionStatus = "connected"
 	// StatusDisconnected indicates the client is disconnected.
 	StatusDisconnected ConnectionStatus = "disconnected"
-	// StatusError indicates there was a connection error.
+	// StatusError indicates there was a connection error. The client
+	// reconnects after a backoff.
 	StatusError ConnectionStatus = "error"
+	// StatusUnauthorized indicates the server rejected the API key with 401
+	// or 403. The client does not reconnect.
+	StatusUnauthorized ConnectionStatus = "unauthorized"
+)
+
+// ChangeType classifies an SSE event by its meaning to the SDK.
+type ChangeType string
+
+const (
+	// ChangeFlagUpdated indicates a single flag was modified.
+	ChangeFlagUpdated ChangeType = "flag-updated"
+	// ChangeConfigUpdated indicates the configuration changed and all flags
+	// should be refreshed.
+	ChangeConfigUpdated ChangeType = "config-updated"
+	// ChangeApiKeyRotated indicates the API key was rotated or the rotation
+	// was aborted.
+	ChangeApiKeyRotated ChangeType = "api-key-rotated"
+	// ChangeHeartbeat indicates a keep-alive event with no payload of interest.
+	ChangeHeartbeat ChangeType = "heartbeat"
 )
 
 // FlagUpdatedEvent represents a single flag update event received via SSE.
@@ -43,6 +68,10 @@ type FlagUpdatedEvent struct {
 type ConfigUpdatedEvent struct {
 	// Timestamp is the ISO timestamp of when the change occurred.
 	Timestamp string `json:"timestamp"`
+
+	// AffectedKeys lists the flags touched by the update, if the server
+	// provides them.
+	AffectedKeys []string `json:"affectedKeys,omitempty"`
 }
 
 // ApiKeyRotatedEvent represents an API key rotation event received via SSE.
@@ -63,9 +92,15 @@ type FlagChangeEvent struct {
 
 	// Timestamp is the ISO timestamp of when the change occurred.
 	Timestamp string `json:"timestamp"`
+
+	// AffectedKeys lists the flags touched by a bulk invalidation when the
+	// server reports them. Empty if the scope is unknown.
+	AffectedKeys []string `json:"affectedKeys,omitempty"`
 }
 
 // GetTimestampAsTime returns the timestamp as a time.Time object.
+// The result is a wall clock time parsed from the server, so it carries no
+// monotonic reading and should not be used to measure elapsed time.
 func (e *FlagChangeEvent) GetTimestampAsTime() (time.Time, error) {
 	if e.Timestamp == "" {
 		return time.Time{}, nil
@@ -76,19 +111,19 @@ func (e *FlagChangeEvent) GetTimestampAsTime() (time.Time, error) {
 // FlagEvaluation represents the result of evaluating a single flag.
 type FlagEvaluation struct {
 	// Key is the flag key.
-	Key string
+	Key string `json:"key"`
 
 	// Value is the evaluated value.
-	Value interface{}
+	Value interface{} `json:"value"`
 
 	// ValueType is the type of the value (boolean, string, number, etc.).
-	ValueType string
+	ValueType string `json:"valueType"`
 
 	// Reason is the reason for this evaluation result.
-	Reason string
+	Reason string `json:"reason,omitempty"`
 
 	// Variant is the variant that matched, if applicable.
-	Variant string
+	Variant string `json:"variant,omitempty"`
 }
 
 // AsBoolean returns the value as a boolean.
@@ -99,32 +134,81 @@ func (e *FlagEvaluation) AsBoolean() bool {
 	return false
 }
 
-// AsInt returns the value as an integer.
+// AsInt returns the value as an integer. Any Go numeric type and
+// json.Number are converted; fractions are truncated.
 func (e *FlagEvaluation) AsInt() int {
 	switch v := e.Value.(type) {
-	case int:
-		return v
-	case int64:
-		return int(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		if f, err := v.Float64(); err == nil {
+			return int(f)
+		}
+		return 0
 	case float64:
 		return int(v)
+	case float32:
+		return int(v)
+	}
+	if i, ok := integerValue(e.Value); ok {
+		return int(i)
 	}
 	return 0
 }
 
-// AsFloat returns the value as a float64.
+// AsFloat returns the value as a float64. Any Go numeric type and
+// json.Number are converted.
 func (e *FlagEvaluation) AsFloat() float64 {
 	switch v := e.Value.(type) {
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return 0.0
 	case float64:
 		return v
-	case int:
+	case float32:
 		return float64(v)
-	case int64:
+	case uint:
+		return float64(v)
+	case uint64:
 		return float64(v)
 	}
+	if i, ok := integerValue(e.Value); ok {
+		return float64(i)
+	}
 	return 0.0
 }
 
+// integerValue converts any Go integer type to int64. Unsigned values above
+// math.MaxInt64 wrap.
+func integerValue(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), true
+	}
+	return 0, false
+}
+
 // AsString returns the value as a string.
 func (e *FlagEvaluation) AsString() string {
 	if s, ok := e.Value.(string); ok {
@@ -133,7 +217,22 @@ func (e *FlagEvaluation) AsString() string {
 	return ""
 }
 
-// GetValueAsString returns the value formatted for display.
+// AsObject returns the value as a JSON object. ok is false if the value is
+// not an object.
+func (e *FlagEvaluation) AsObject() (value map[string]interface{}, ok bool) {
+	value, ok = e.Value.(map[string]interface{})
+	return value, ok
+}
+
+// AsArray returns the value as a JSON array. ok is false if the value is not
+// an array.
+func (e *FlagEvaluation) AsArray() (value []interface{}, ok bool) {
+	value, ok = e.Value.([]interface{})
+	return value, ok
+}
+
+// GetValueAsString returns the value formatted for display. Objects and
+// arrays are formatted as compact JSON.
 func (e *FlagEvaluation) GetValueAsString() string {
 	if e.Value == nil {
 		return "null"
@@ -146,6 +245,12 @@ func (e *FlagEvaluation) GetValueAsString() string {
 			return "true"
 		}
 		return "false"
+	case map[string]interface{}, []interface{}:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(encoded)
 	default:
 		return stringValue(v)
 	}
@@ -172,49 +277,21 @@ func stringValue(v interface{}) string {
 }
 
 func intToString(i int) string {
-	if i == 0 {
-		return "0"
-	}
-	neg := false
-	if i < 0 {
-		neg = true
-		i = -i
-	}
-	var b [20]byte
-	pos := len(b)
-	for i > 0 {
-		pos--
-		b[pos] = byte('0' + i%10)
-		i /= 10
-	}
-	if neg {
-		pos--
-		b[pos] = '-'
-	}
-	return string(b[pos:])
+	return strconv.Itoa(i)
 }
 
 func int64ToString(i int64) string {
-	return intToString(int(i))
+	return strconv.FormatInt(i, 10)
 }
 
+// floatToString formats f with the fewest digits that parse back to the same
+// value, so 5.0 is "5", 3.14159 is "3.14159" and 1e-7 is "1e-07". Integral
+// values below 1e21 are written without an exponent, so 1e6 is "1000000".
 func floatToString(f float64) string {
-	// Simple float to string conversion
-	if f == float64(int(f)) {
-		return intToString(int(f))
-	}
-	// For non-integer floats, use a simple approach
-	intPart := int(f)
-	fracPart := f - float64(intPart)
-	if fracPart < 0 {
-		fracPart = -fracPart
+	if f == math.Trunc(f) && math.Abs(f) < 1e21 {
+		return strconv.FormatFloat(f, 'f', -1, 64)
 	}
-	// Convert to 2 decimal places
-	fracInt := int(fracPart * 100)
-	if fracInt == 0 {
-		return intToString(intPart)
-	}
-	return intToString(intPart) + "." + intToString(fracInt)
+	return strconv.FormatFloat(f, 'g', -1, 64)
 }
 
 // FlagChangeHandler is called when a flag changes.
@@ -224,5 +301,9 @@ type FlagChangeHandler func(event FlagChangeEvent)
 // removes the corresponding listener.
 type CancelFunc func()
 
+// ListenerID identifies a flag change listener registered with
+// AddFlagChangeListenerWithID.
+type ListenerID int
+
 // ConnectionStatusHandler is called when the SSE connection status changes.
 type ConnectionStatusHandler func(status ConnectionStatus)
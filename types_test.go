@@ -1,6 +1,8 @@
 package flipswitch
 
 import (
+	"encoding/json"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -90,6 +92,33 @@ func TestAsInt_Float64(t *testing.T) {
 	}
 }
 
+func TestAsInt_NumericTypes(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  int
+	}{
+		{int8(-8), -8},
+		{int16(16), 16},
+		{int32(32), 32},
+		{uint(7), 7},
+		{uint8(8), 8},
+		{uint16(16), 16},
+		{uint32(32), 32},
+		{uint64(64), 64},
+		{float32(2.5), 2},
+		{json.Number("42"), 42},
+		{json.Number("4.9"), 4},
+		{json.Number("1e3"), 1000},
+		{json.Number("not-a-number"), 0},
+	}
+	for _, tt := range tests {
+		e := &FlagEvaluation{Value: tt.value}
+		if got := e.AsInt(); got != tt.want {
+			t.Errorf("AsInt(%T %v) = %d, want %d", tt.value, tt.value, got, tt.want)
+		}
+	}
+}
+
 func TestAsInt_NonNumber(t *testing.T) {
 	e := &FlagEvaluation{Value: "abc"}
 	if got := e.AsInt(); got != 0 {
@@ -122,6 +151,33 @@ func TestAsFloat_Int64(t *testing.T) {
 	}
 }
 
+func TestAsFloat_NumericTypes(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  float64
+	}{
+		{int8(-8), -8},
+		{int16(16), 16},
+		{int32(32), 32},
+		{uint(7), 7},
+		{uint8(8), 8},
+		{uint16(16), 16},
+		{uint32(32), 32},
+		{uint64(1 << 63), 1 << 63},
+		{float32(2.5), 2.5},
+		{json.Number("42"), 42},
+		{json.Number("4.25"), 4.25},
+		{json.Number("1e-3"), 0.001},
+		{json.Number("not-a-number"), 0},
+	}
+	for _, tt := range tests {
+		e := &FlagEvaluation{Value: tt.value}
+		if got := e.AsFloat(); got != tt.want {
+			t.Errorf("AsFloat(%T %v) = %v, want %v", tt.value, tt.value, got, tt.want)
+		}
+	}
+}
+
 func TestAsFloat_NonNumber(t *testing.T) {
 	e := &FlagEvaluation{Value: "abc"}
 	if got := e.AsFloat(); got != 0.0 {
@@ -221,6 +277,65 @@ func TestGetValueAsString_Unknown(t *testing.T) {
 	}
 }
 
+func TestGetValueAsString_NestedObject(t *testing.T) {
+	e := &FlagEvaluation{Value: map[string]interface{}{
+		"theme":  "dark",
+		"limits": map[string]interface{}{"rps": float64(100), "burst": nil},
+		"tags":   []interface{}{"a", true},
+	}}
+	want := `{"limits":{"burst":null,"rps":100},"tags":["a",true],"theme":"dark"}`
+	if got := e.GetValueAsString(); got != want {
+		t.Errorf("expected '%s', got '%s'", want, got)
+	}
+}
+
+func TestGetValueAsString_MixedArray(t *testing.T) {
+	e := &FlagEvaluation{Value: []interface{}{float64(1), "two", false, nil, []interface{}{3.5}, map[string]interface{}{"k": "v"}}}
+	want := `[1,"two",false,null,[3.5],{"k":"v"}]`
+	if got := e.GetValueAsString(); got != want {
+		t.Errorf("expected '%s', got '%s'", want, got)
+	}
+}
+
+// ========================================
+// AsObject / AsArray Tests
+// ========================================
+
+func TestAsObject(t *testing.T) {
+	nested := map[string]interface{}{"limits": map[string]interface{}{"rps": float64(100)}}
+	e := &FlagEvaluation{Value: nested}
+	got, ok := e.AsObject()
+	if !ok {
+		t.Fatal("expected an object")
+	}
+	if limits, ok := got["limits"].(map[string]interface{}); !ok || limits["rps"] != float64(100) {
+		t.Errorf("expected the nested object, got %v", got)
+	}
+	if _, ok := (&FlagEvaluation{Value: []interface{}{}}).AsObject(); ok {
+		t.Error("expected an array not to be an object")
+	}
+	if got, ok := (&FlagEvaluation{Value: nil}).AsObject(); ok || got != nil {
+		t.Errorf("expected nil not to be an object, got %v", got)
+	}
+}
+
+func TestAsArray(t *testing.T) {
+	e := &FlagEvaluation{Value: []interface{}{"a", float64(2), map[string]interface{}{"b": true}}}
+	got, ok := e.AsArray()
+	if !ok || len(got) != 3 {
+		t.Fatalf("expected a 3 element array, got %v, %v", got, ok)
+	}
+	if obj, ok := got[2].(map[string]interface{}); !ok || obj["b"] != true {
+		t.Errorf("expected a nested object, got %v", got[2])
+	}
+	if _, ok := (&FlagEvaluation{Value: map[string]interface{}{}}).AsArray(); ok {
+		t.Error("expected an object not to be an array")
+	}
+	if _, ok := (&FlagEvaluation{Value: "a,b"}).AsArray(); ok {
+		t.Error("expected a string not to be an array")
+	}
+}
+
 // ========================================
 // stringValue Tests (through GetValueAsString default branch)
 // ========================================
@@ -279,6 +394,12 @@ func TestInt64ToString(t *testing.T) {
 	}
 }
 
+func TestInt64ToString_Large(t *testing.T) {
+	if got := int64ToString(9223372036854775807); got != "9223372036854775807" {
+		t.Errorf("expected '9223372036854775807', got '%s'", got)
+	}
+}
+
 func TestInt64ToString_Negative(t *testing.T) {
 	if got := int64ToString(-50); got != "-50" {
 		t.Errorf("expected '-50', got '%s'", got)
@@ -310,8 +431,33 @@ func TestFloatToString_Zero(t *testing.T) {
 func TestFloatToString_NegativeWithDecimals(t *testing.T) {
 	got := floatToString(-2.50)
 	if got != "-2.50" && got != "-2.5" {
-		// The implementation converts -2.50: intPart=-2, fracPart=0.50, fracInt=50
-		// So result is intToString(-2) + "." + intToString(50) = "-2.50"
 		t.Errorf("expected '-2.50' or '-2.5', got '%s'", got)
 	}
 }
+
+func TestFloatToString_RoundTrips(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{3.14159, "3.14159"},
+		{0.001, "0.001"},
+		{-0.25, "-0.25"},
+		{-0.001, "-0.001"},
+		{1e-7, "1e-07"},
+		{1e6, "1000000"},
+		{-1e6, "-1000000"},
+		{1e21, "1e+21"},
+		{123456789012345680000, "123456789012345680000"},
+		{9007199254740993, "9007199254740992"},
+	}
+	for _, tt := range tests {
+		got := floatToString(tt.in)
+		if got != tt.want {
+			t.Errorf("floatToString(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+		if back, err := strconv.ParseFloat(got, 64); err != nil || back != tt.in {
+			t.Errorf("floatToString(%v) = %q does not parse back, got %v, %v", tt.in, got, back, err)
+		}
+	}
+}
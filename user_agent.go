@@ -0,0 +1,34 @@
+package flipswitch
+
+import "net/http"
+
+// Version is the version of this SDK. It is reported in the default
+// User-Agent, and as the SDK version when the build info does not carry the
+// module version.
+const Version = "0.1.0"
+
+// defaultUserAgent is sent as User-Agent unless WithUserAgent is given.
+const defaultUserAgent = "flipswitch-go-sdk/" + Version
+
+// WithUserAgent replaces the default "flipswitch-go-sdk/<version>"
+// User-Agent sent on every request: flag evaluations, the API key check made
+// by Init and the SSE connection. An empty value keeps the default.
+func WithUserAgent(userAgent string) Option {
+	return func(p *FlipswitchProvider) {
+		if userAgent != "" {
+			p.userAgent = userAgent
+		}
+	}
+}
+
+// setUserAgent adds the configured User-Agent to req.
+func (p *FlipswitchProvider) setUserAgent(req *http.Request) {
+	req.Header.Set("User-Agent", p.userAgent)
+}
+
+// withSseUserAgent sets the User-Agent sent on every connection attempt.
+func withSseUserAgent(userAgent string) SseOption {
+	return func(c *SseClient) {
+		c.userAgent = userAgent
+	}
+}
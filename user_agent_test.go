@@ -0,0 +1,103 @@
+package flipswitch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// userAgentServer serves flag "my-flag" and an SSE stream, recording the
+// User-Agent of every request by path. connected receives once the stream is
+// open.
+func userAgentServer() (server *httptest.Server, userAgents func() map[string][]string, connected chan struct{}) {
+	connected = make(chan struct{}, 1)
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("my-flag", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "my-flag", "value": true}
+	})
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"flags": []interface{}{}}
+	})
+	dispatcher.SetSseHandler(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case connected <- struct{}{}:
+		default:
+		}
+		serveSseKeepAlive(w, r)
+	})
+
+	var mu sync.Mutex
+	seen := map[string][]string{}
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.URL.Path] = append(seen[r.URL.Path], r.Header.Get("User-Agent"))
+		mu.Unlock()
+		dispatcher.ServeHTTP(w, r)
+	}))
+	userAgents = func() map[string][]string {
+		mu.Lock()
+		defer mu.Unlock()
+		copied := make(map[string][]string, len(seen))
+		for path, values := range seen {
+			copied[path] = append([]string(nil), values...)
+		}
+		return copied
+	}
+	return server, userAgents, connected
+}
+
+func TestWithUserAgent_SentOnEveryRequestType(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"default", nil, "flipswitch-go-sdk/" + Version},
+		{"override", []Option{WithUserAgent("checkout-service/2.3")}, "checkout-service/2.3"},
+		{"empty keeps default", []Option{WithUserAgent("")}, "flipswitch-go-sdk/" + Version},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server, userAgents, connected := userAgentServer()
+			defer server.Close()
+
+			provider, err := NewProvider("test-api-key", append([]Option{WithBaseURL(server.URL)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("Failed to create provider: %v", err)
+			}
+			defer provider.Shutdown()
+
+			if err := provider.Init(openfeature.EvaluationContext{}); err != nil {
+				t.Fatalf("Failed to initialize: %v", err)
+			}
+			select {
+			case <-connected:
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for SSE connection")
+			}
+			provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{})
+			provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+			provider.BooleanEvaluation(context.Background(), "my-flag", false, openfeature.FlattenedContext{})
+
+			got := userAgents()
+			for _, path := range []string{
+				"/ofrep/v1/evaluate/flags",
+				"/ofrep/v1/evaluate/flags/my-flag",
+				defaultSsePath,
+			} {
+				if len(got[path]) == 0 {
+					t.Errorf("Expected a request to %s", path)
+				}
+				for _, userAgent := range got[path] {
+					if userAgent != tt.want {
+						t.Errorf("Expected User-Agent %q on %s, got %q", tt.want, path, userAgent)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package flipswitch
+
+// WithValueTransformer post-processes flag values, for example to clamp a
+// rollout percentage or map a string to an internal enum, consistently
+// wherever they are read through EvaluateFlag and EvaluateAllFlags (and
+// their Ctx, WithContext and Grouped variants). transform is called with each
+// returned flag's key and value and returns the value to return instead; it
+// should return the value unchanged for flags it does not handle. The
+// transform is applied to the returned copy only, so the cache and the other
+// evaluation methods keep the server's value, and ValueType continues to
+// describe it. transform must not modify the value in place. A panic in it
+// is recovered and logged, and the value is returned untransformed.
+func WithValueTransformer(transform func(flagKey string, value interface{}) interface{}) Option {
+	return func(p *FlipswitchProvider) {
+		p.valueTransformer = transform
+	}
+}
+
+// transformFlag returns a copy of eval with the value transformer applied,
+// or eval itself if no transformer is set.
+func (p *FlipswitchProvider) transformFlag(eval *FlagEvaluation) *FlagEvaluation {
+	if p.valueTransformer == nil || eval == nil {
+		return eval
+	}
+	result := *eval
+	result.Value = p.transformValue(eval.Key, eval.Value)
+	return &result
+}
+
+// transformFlags returns a copy of flags with the value transformer applied,
+// or flags itself if no transformer is set.
+func (p *FlipswitchProvider) transformFlags(flags []FlagEvaluation) []FlagEvaluation {
+	if p.valueTransformer == nil {
+		return flags
+	}
+	result := make([]FlagEvaluation, len(flags))
+	for i, flag := range flags {
+		result[i] = flag
+		result[i].Value = p.transformValue(flag.Key, flag.Value)
+	}
+	return result
+}
+
+func (p *FlipswitchProvider) transformValue(flagKey string, value interface{}) (result interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Errorw("Error in value transformer", "flagKey", flagKey, "panic", r)
+			result = value
+		}
+	}()
+	return p.valueTransformer(flagKey, value)
+}
@@ -0,0 +1,123 @@
+package flipswitch
+
+import (
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// clampRollout caps the rollout flag at 50 and leaves other flags alone.
+func clampRollout(flagKey string, value interface{}) interface{} {
+	if percent, ok := value.(float64); ok && flagKey == "rollout" && percent > 50 {
+		return float64(50)
+	}
+	return value
+}
+
+func transformerServer() *httptest.Server {
+	dispatcher := NewTestDispatcher()
+	dispatcher.SetFlagResponse("rollout", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "rollout", "value": 80}
+	})
+	dispatcher.SetFlagResponse("theme", func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"key": "theme", "value": "dark"}
+	})
+	dispatcher.SetBulkResponse(func() (int, map[string]interface{}) {
+		return 200, map[string]interface{}{"flags": []interface{}{
+			map[string]interface{}{"key": "rollout", "value": 80},
+			map[string]interface{}{"key": "theme", "value": "dark"},
+		}}
+	})
+	return httptest.NewServer(dispatcher)
+}
+
+func TestValueTransformer_AppliedToEvaluateFlag(t *testing.T) {
+	server := transformerServer()
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithCache(time.Minute),
+		WithValueTransformer(clampRollout),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	for i := 0; i < 2; i++ {
+		if result := provider.EvaluateFlag("rollout", openfeature.FlattenedContext{}); result == nil || result.Value != float64(50) {
+			t.Errorf("Call %d: expected the clamped value 50, got %+v", i+1, result)
+		}
+	}
+	if result := provider.EvaluateFlag("theme", openfeature.FlattenedContext{}); result == nil || result.AsString() != "dark" {
+		t.Errorf("Expected theme to pass through, got %+v", result)
+	}
+
+	// The cache keeps the server's value
+	cached, err := provider.EvaluateFlagWithPolicy("rollout", openfeature.FlattenedContext{}, CacheOnly)
+	if err != nil || cached.Value != float64(80) {
+		t.Errorf("Expected the cache to hold the raw value 80, got %+v, %v", cached, err)
+	}
+}
+
+func TestValueTransformer_AppliedToEvaluateAllFlags(t *testing.T) {
+	server := transformerServer()
+	defer server.Close()
+
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithValueTransformer(clampRollout),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	values := make(map[string]interface{})
+	for _, flag := range provider.EvaluateAllFlags(openfeature.FlattenedContext{}) {
+		values[flag.Key] = flag.Value
+	}
+	if values["rollout"] != float64(50) {
+		t.Errorf("Expected the clamped value 50, got %v", values["rollout"])
+	}
+	if values["theme"] != "dark" {
+		t.Errorf("Expected theme to pass through, got %v", values["theme"])
+	}
+}
+
+func TestValueTransformer_PanicReturnsRawValue(t *testing.T) {
+	server := transformerServer()
+	defer server.Close()
+
+	var calls int32
+	logger := &recordingLogger{}
+	provider, err := NewProvider("test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithLogger(logger),
+		WithValueTransformer(func(string, interface{}) interface{} {
+			atomic.AddInt32(&calls, 1)
+			panic("boom")
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if result := provider.EvaluateFlag("rollout", openfeature.FlattenedContext{}); result == nil || result.Value != float64(80) {
+		t.Errorf("Expected the raw value after a panic, got %+v", result)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("Expected the transformer to be called once, got %d", calls)
+	}
+	if _, ok := logger.find("Error in value transformer"); !ok {
+		t.Error("Expected the panic to be logged")
+	}
+}
@@ -0,0 +1,87 @@
+package flipswitch
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// signatureHeader carries the base64-encoded Ed25519 signature of the
+// response body.
+const signatureHeader = "X-Flipswitch-Signature"
+
+// ResponseVerificationError is returned when an evaluation response fails
+// signature verification.
+type ResponseVerificationError struct {
+	// URL is the request URL whose response was rejected.
+	URL string
+
+	// Reason describes why verification failed.
+	Reason string
+}
+
+func (e *ResponseVerificationError) Error() string {
+	return "response verification failed for " + e.URL + ": " + e.Reason
+}
+
+// WithResponseVerification requires successful evaluation responses, for
+// EvaluateFlag, EvaluateAllFlags and the typed OpenFeature evaluations alike,
+// to carry an X-Flipswitch-Signature header holding a base64 Ed25519
+// signature of the body made with the key matching pubKey. Responses that
+// are unsigned or fail verification are rejected with a
+// *ResponseVerificationError; a typed evaluation then resolves to an error
+// and its result is never cached.
+func WithResponseVerification(pubKey ed25519.PublicKey) Option {
+	return func(p *FlipswitchProvider) {
+		p.verificationKey = pubKey
+	}
+}
+
+// verifyResponse checks the signature of a successful response body when
+// verification is enabled.
+func (p *FlipswitchProvider) verifyResponse(resp *http.Response, body []byte) error {
+	if p.verificationKey == nil || !isSuccess(resp.StatusCode) {
+		return nil
+	}
+
+	url := resp.Request.URL.String()
+	header := resp.Header.Get(signatureHeader)
+	if header == "" {
+		return &ResponseVerificationError{URL: url, Reason: "missing signature"}
+	}
+	signature, err := base64.StdEncoding.DecodeString(header)
+	if err != nil {
+		return &ResponseVerificationError{URL: url, Reason: "malformed signature"}
+	}
+	if !ed25519.Verify(p.verificationKey, body, signature) {
+		return &ResponseVerificationError{URL: url, Reason: "signature mismatch"}
+	}
+	return nil
+}
+
+// verifyingTransport rejects responses that fail verification, for the OFREP
+// client, which reads responses itself.
+type verifyingTransport struct {
+	next   http.RoundTripper
+	verify func(resp *http.Response, body []byte) error
+}
+
+func (t *verifyingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || !isSuccess(resp.StatusCode) {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if err := t.verify(resp, body); err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
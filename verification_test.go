@@ -0,0 +1,198 @@
+package flipswitch
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const (
+	signedFlagBody = `{"key":"kill-switch","value":true}`
+	signedBulkBody = `{"flags":[{"key":"kill-switch","value":true}]}`
+)
+
+// signingServer serves fixed evaluation bodies. sign produces the signature
+// header for a body, or "" to leave the response unsigned; tamper replaces
+// the body after it was signed.
+func signingServer(sign func(body []byte) string, tamper func(body string) string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := signedFlagBody
+		if r.URL.Path == "/ofrep/v1/evaluate/flags" {
+			body = signedBulkBody
+		}
+		if signature := sign([]byte(body)); signature != "" {
+			w.Header().Set(signatureHeader, signature)
+		}
+		if tamper != nil {
+			body = tamper(body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+}
+
+func signWith(key ed25519.PrivateKey) func(body []byte) string {
+	return func(body []byte) string {
+		return base64.StdEncoding.EncodeToString(ed25519.Sign(key, body))
+	}
+}
+
+func createVerifyingProvider(t *testing.T, server *httptest.Server, pubKey ed25519.PublicKey) *FlipswitchProvider {
+	t.Helper()
+	provider, err := NewProvider(
+		"test-api-key",
+		WithBaseURL(server.URL),
+		WithRealtime(false),
+		WithResponseVerification(pubKey),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	return provider
+}
+
+func TestResponseVerification_SignedResponseAccepted(t *testing.T) {
+	pubKey, privKey, _ := ed25519.GenerateKey(nil)
+	server := signingServer(signWith(privKey), nil)
+	defer server.Close()
+
+	provider := createVerifyingProvider(t, server, pubKey)
+	defer provider.Shutdown()
+
+	if result := provider.EvaluateFlag("kill-switch", openfeature.FlattenedContext{}); result == nil || result.Value != true {
+		t.Errorf("Expected signed flag to evaluate to true, got %+v", result)
+	}
+	flags := provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+	if len(flags) != 1 || flags[0].Value != true {
+		t.Errorf("Expected signed bulk response to be accepted, got %+v", flags)
+	}
+}
+
+func TestResponseVerification_RejectsBadResponses(t *testing.T) {
+	pubKey, privKey, _ := ed25519.GenerateKey(nil)
+	_, otherKey, _ := ed25519.GenerateKey(nil)
+
+	tests := []struct {
+		name   string
+		sign   func(body []byte) string
+		tamper func(body string) string
+		reason string
+	}{
+		{
+			name: "Tampered",
+			sign: signWith(privKey),
+			tamper: func(body string) string {
+				return body[:len(body)-len("true}")] + "false}"
+			},
+			reason: "signature mismatch",
+		},
+		{
+			name:   "WrongKey",
+			sign:   signWith(otherKey),
+			reason: "signature mismatch",
+		},
+		{
+			name:   "Unsigned",
+			sign:   func([]byte) string { return "" },
+			reason: "missing signature",
+		},
+		{
+			name:   "Malformed",
+			sign:   func([]byte) string { return "not base64!" },
+			reason: "malformed signature",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := signingServer(tt.sign, tt.tamper)
+			defer server.Close()
+
+			provider := createVerifyingProvider(t, server, pubKey)
+			defer provider.Shutdown()
+
+			_, err := provider.evaluateFlag(context.Background(), "kill-switch", openfeature.FlattenedContext{})
+			var verr *ResponseVerificationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Expected *ResponseVerificationError for single evaluation, got %v", err)
+			}
+			if verr.Reason != tt.reason {
+				t.Errorf("Expected reason %q, got %q", tt.reason, verr.Reason)
+			}
+
+			_, err = provider.fetchAllFlags(context.Background(), openfeature.FlattenedContext{})
+			if !errors.As(err, &verr) {
+				t.Fatalf("Expected *ResponseVerificationError for bulk evaluation, got %v", err)
+			}
+
+			if result := provider.EvaluateFlag("kill-switch", openfeature.FlattenedContext{}); result != nil {
+				t.Errorf("Expected unverified flag to be rejected, got %+v", result)
+			}
+		})
+	}
+}
+
+func TestResponseVerification_TypedEvaluations(t *testing.T) {
+	pubKey, privKey, _ := ed25519.GenerateKey(nil)
+	tamper := func(body string) string { return body[:len(body)-len("true}")] + "false}" }
+
+	for name, tampered := range map[string]bool{"Signed": false, "Tampered": true} {
+		t.Run(name, func(t *testing.T) {
+			var server *httptest.Server
+			if tampered {
+				server = signingServer(signWith(privKey), tamper)
+			} else {
+				server = signingServer(signWith(privKey), nil)
+			}
+			defer server.Close()
+
+			provider, err := NewProvider(
+				"test-api-key",
+				WithBaseURL(server.URL),
+				WithRealtime(false),
+				WithResponseVerification(pubKey),
+				WithCache(time.Minute),
+			)
+			if err != nil {
+				t.Fatalf("Failed to create provider: %v", err)
+			}
+			defer provider.Shutdown()
+
+			result := provider.BooleanEvaluation(context.Background(), "kill-switch", false, openfeature.FlattenedContext{})
+			if !tampered {
+				if result.Value != true || result.Error() != nil {
+					t.Errorf("Expected the signed value, got %+v", result)
+				}
+				return
+			}
+			if result.Value != false || result.Reason != openfeature.ErrorReason {
+				t.Errorf("Expected the default with an error for a tampered response, got %+v", result)
+			}
+			if n := provider.cache.len(); n != 0 {
+				t.Errorf("Expected the unverified result not to be cached, got %d entries", n)
+			}
+		})
+	}
+}
+
+func TestResponseVerification_DisabledByDefault(t *testing.T) {
+	server := signingServer(func([]byte) string { return "" }, nil)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if result := provider.EvaluateFlag("kill-switch", openfeature.FlattenedContext{}); result == nil {
+		t.Error("Expected unsigned response to be accepted without verification")
+	}
+}
@@ -0,0 +1,127 @@
+package flipswitch
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// versionedServer serves my-flag as "current" unless the request is pinned to
+// version v1, in which case it serves "v1". It counts requests.
+func versionedServer(calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		value := "current"
+		if r.URL.Query().Get("version") == "v1" {
+			value = "v1"
+		}
+		flag := map[string]interface{}{"key": "my-flag", "value": value, "reason": "STATIC"}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/ofrep/v1/evaluate/flags":
+			json.NewEncoder(w).Encode(map[string]interface{}{"flags": []interface{}{flag}})
+		case "/ofrep/v1/evaluate/flags/my-flag":
+			json.NewEncoder(w).Encode(flag)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestEvaluateAllFlagsAt_SendsVersion(t *testing.T) {
+	var calls int32
+	server := versionedServer(&calls)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	flags, err := provider.EvaluateAllFlagsAt("v1", openfeature.FlattenedContext{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(flags) != 1 || flags[0].AsString() != "v1" {
+		t.Errorf("Expected the pinned value, got %+v", flags)
+	}
+
+	current := provider.EvaluateAllFlags(openfeature.FlattenedContext{})
+	if len(current) != 1 || current[0].AsString() != "current" {
+		t.Errorf("Expected the current value without a version, got %+v", current)
+	}
+}
+
+func TestEvaluateFlagAt_SendsVersionAndBypassesCache(t *testing.T) {
+	var calls int32
+	server := versionedServer(&calls)
+	defer server.Close()
+
+	provider := createCachingProvider(t, server, time.Minute)
+	defer provider.Shutdown()
+
+	if result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{}); result == nil || result.AsString() != "current" {
+		t.Fatalf("Expected current value, got %+v", result)
+	}
+
+	for i := 0; i < 2; i++ {
+		result, err := provider.EvaluateFlagAt("my-flag", "v1", openfeature.FlattenedContext{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.AsString() != "v1" {
+			t.Errorf("Expected the pinned value, got %v", result.Value)
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected every pinned call to reach the server, got %d requests", got)
+	}
+
+	// The pinned result must not replace the cached current value
+	if result := provider.EvaluateFlag("my-flag", openfeature.FlattenedContext{}); result == nil || result.AsString() != "current" {
+		t.Errorf("Expected cached current value, got %+v", result)
+	}
+}
+
+func TestEvaluateFlagAt_RequiresVersion(t *testing.T) {
+	var calls int32
+	server := versionedServer(&calls)
+	defer server.Close()
+
+	provider, err := createTestProvider(server)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown()
+
+	if _, err := provider.EvaluateFlagAt("my-flag", "", openfeature.FlattenedContext{}); !errors.Is(err, errEmptyVersion) {
+		t.Errorf("Expected errEmptyVersion, got %v", err)
+	}
+	if _, err := provider.EvaluateAllFlagsAt("", openfeature.FlattenedContext{}); !errors.Is(err, errEmptyVersion) {
+		t.Errorf("Expected errEmptyVersion, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Errorf("Expected no requests, got %d", got)
+	}
+}
+
+func TestEvaluationURL_EscapesVersion(t *testing.T) {
+	provider, err := NewProvider("test-api-key", WithBaseURL("http://localhost"), WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	got := provider.evaluationURL("my-flag", "2024-06-15T12:00:00+02:00")
+	want := "http://localhost/ofrep/v1/evaluate/flags/my-flag?version=2024-06-15T12%3A00%3A00%2B02%3A00"
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,68 @@
+package flipswitch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// warmupConcurrency bounds the bulk evaluations Warmup runs at once.
+const warmupConcurrency = 4
+
+// errCacheDisabled is returned by Warmup when there is no cache to fill.
+var errCacheDisabled = errors.New("cache is not enabled, use WithCache")
+
+// Warmup bulk-evaluates every flag for each of contexts and stores the
+// results in the cache, so the first EvaluateFlag call for those contexts is
+// served without a request. Contexts are fetched concurrently. A failure for
+// one context does not stop the others; all failures are returned joined
+// together. Warmup requires WithCache.
+func (p *FlipswitchProvider) Warmup(ctx context.Context, contexts []openfeature.FlattenedContext) error {
+	if p.cache == nil {
+		return errCacheDisabled
+	}
+
+	errs := make([]error, len(contexts))
+	sem := make(chan struct{}, warmupConcurrency)
+	var wg sync.WaitGroup
+
+	for i, evalCtx := range contexts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = fmt.Errorf("warming context %d: %w", i, ctx.Err())
+				return
+			}
+			defer func() { <-sem }()
+
+			if err := p.warmContext(ctx, evalCtx); err != nil {
+				errs[i] = fmt.Errorf("warming context %d: %w", i, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// warmContext caches the bulk evaluation of all flags for evalCtx. Without a
+// cache it only performs the evaluation.
+func (p *FlipswitchProvider) warmContext(ctx context.Context, evalCtx openfeature.FlattenedContext) error {
+	ctxHash := contextHash(evalCtx)
+	generation := p.cache.currentGeneration()
+
+	flags, err := p.fetchAllFlags(ctx, evalCtx)
+	if err != nil {
+		return err
+	}
+	for _, flag := range flags {
+		p.cache.set(flag.Key, ctxHash, flag, generation)
+	}
+	return nil
+}
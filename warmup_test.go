@@ -0,0 +1,131 @@
+package flipswitch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// tenantServer serves bulk evaluations that echo the tenant from the request
+// context, failing for tenants in failing, and counts requests per tenant.
+// Single flag requests fail so that cache hits can be told apart.
+type tenantServer struct {
+	failing map[string]bool
+	mu      sync.Mutex
+	bulk    map[string]int
+	single  int
+}
+
+func (s *tenantServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/ofrep/v1/evaluate/flags" {
+		s.mu.Lock()
+		s.single++
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var body struct {
+		Context map[string]interface{} `json:"context"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+	tenant, _ := body.Context["tenant"].(string)
+
+	s.mu.Lock()
+	s.bulk[tenant]++
+	s.mu.Unlock()
+
+	if s.failing[tenant] {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"flags": []interface{}{
+			map[string]interface{}{"key": "plan", "value": tenant},
+		},
+	})
+}
+
+func tenantContexts(tenants ...string) []openfeature.FlattenedContext {
+	contexts := make([]openfeature.FlattenedContext, len(tenants))
+	for i, tenant := range tenants {
+		contexts[i] = openfeature.FlattenedContext{"targetingKey": "svc", "tenant": tenant}
+	}
+	return contexts
+}
+
+func TestWarmup_FetchesAndCachesAllContexts(t *testing.T) {
+	handler := &tenantServer{bulk: make(map[string]int)}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	provider := createCachingProvider(t, server, time.Minute)
+	defer provider.Shutdown()
+
+	contexts := tenantContexts("acme", "globex", "initech", "umbrella", "hooli")
+	if err := provider.Warmup(context.Background(), contexts); err != nil {
+		t.Fatalf("Unexpected warmup error: %v", err)
+	}
+
+	for _, evalCtx := range contexts {
+		tenant := evalCtx["tenant"].(string)
+		if handler.bulk[tenant] != 1 {
+			t.Errorf("Expected 1 bulk request for %s, got %d", tenant, handler.bulk[tenant])
+		}
+		result := provider.EvaluateFlag("plan", evalCtx)
+		if result == nil || result.Value != tenant {
+			t.Errorf("Expected cached plan %q for %s, got %+v", tenant, tenant, result)
+		}
+	}
+	if handler.single != 0 {
+		t.Errorf("Expected warmed evaluations to be served from cache, got %d requests", handler.single)
+	}
+}
+
+func TestWarmup_FailureDoesNotBlockOthers(t *testing.T) {
+	handler := &tenantServer{
+		bulk:    make(map[string]int),
+		failing: map[string]bool{"globex": true},
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	provider := createCachingProvider(t, server, time.Minute)
+	defer provider.Shutdown()
+
+	contexts := tenantContexts("acme", "globex", "initech")
+	err := provider.Warmup(context.Background(), contexts)
+	if err == nil {
+		t.Fatal("Expected an error for the failing context")
+	}
+	var se *HTTPStatusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected joined error to carry the 503, got %v", err)
+	}
+
+	for _, tenant := range []string{"acme", "initech"} {
+		evalCtx := tenantContexts(tenant)[0]
+		if result := provider.EvaluateFlag("plan", evalCtx); result == nil || result.Value != tenant {
+			t.Errorf("Expected %s to be cached despite the failure, got %+v", tenant, result)
+		}
+	}
+}
+
+func TestWarmup_RequiresCache(t *testing.T) {
+	provider, err := NewProvider("test-api-key", WithRealtime(false))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	if err := provider.Warmup(context.Background(), tenantContexts("acme")); !errors.Is(err, errCacheDisabled) {
+		t.Errorf("Expected errCacheDisabled, got %v", err)
+	}
+}